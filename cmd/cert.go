@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"wx_channel/pkg/certificate"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "管理本机根证书",
+	Long: `管理本机根证书。
+
+每个安装在首次运行时生成独立的根证书和私钥，保存在：
+  ` + certificate.DefaultCADir(),
+}
+
+var certShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "显示根证书的指纹和有效期",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		caDir := certificate.DefaultCADir()
+		ca, err := certificate.LoadCA(caDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				color.Yellow("尚未生成根证书，首次运行程序时会自动生成。\n")
+				os.Exit(1)
+			}
+			color.Red("读取根证书失败: %v\n", err)
+			os.Exit(1)
+		}
+
+		info := ca.Info()
+		info.CertPath = certificate.CertPath(caDir)
+		if configShowJSON {
+			printJSON(info)
+			return
+		}
+		installed, _ := certificate.CheckCertificate(ca.Name())
+		fmt.Printf("名称:        %s\n", info.Name)
+		fmt.Printf("SHA-256:     %s\n", info.Fingerprint)
+		fmt.Printf("SHA-1:       %s\n", info.FingerprintSHA1)
+		fmt.Printf("有效期至:    %s（剩余 %d 天）\n", info.NotAfter.Format("2006-01-02"), info.ExpiresInDays)
+		fmt.Printf("证书文件:    %s\n", info.CertPath)
+		fmt.Printf("已安装:      %v\n", installed)
+	},
+}
+
+var certRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "生成新的根证书并移除旧证书",
+	Long: `生成新的根证书，安装到系统后替换本机保存的证书和私钥，再从系统中移除旧证书。
+新证书安装失败时不做任何修改。运行中的程序需要重启后才会使用新证书。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		color.Yellow("正在生成新的根证书...\n")
+		newCA, oldCA, err := certificate.RotateCA(certificate.DefaultCADir())
+		if newCA == nil {
+			color.Red("轮换根证书失败: %v\n", err)
+			color.Yellow("请尝试以管理员身份运行此命令。\n")
+			os.Exit(1)
+		}
+
+		color.Green("✓ 新根证书已安装: %s\n", newCA.Name())
+		fmt.Printf("  SHA-256: %s\n", newCA.Fingerprint())
+		if err != nil {
+			color.Red("%v\n", err)
+			os.Exit(1)
+		}
+		if oldCA != nil {
+			color.Green("✓ 已移除旧根证书: %s\n", oldCA.Name())
+		}
+		color.Yellow("注意：如果程序正在运行，请重启程序和浏览器以使用新证书。\n")
+	},
+}
+
+func init() {
+	certCmd.PersistentFlags().BoolVar(&configShowJSON, "json", false, "以 JSON 格式输出")
+
+	rootCmd.AddCommand(certCmd)
+	certCmd.AddCommand(certShowCmd, certRotateCmd)
+}
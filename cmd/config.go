@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"wx_channel/internal/config"
+	"wx_channel/internal/database"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	configShowJSON    bool
+	configShowSecrets bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "查看、修改和校验配置",
+	Long: `查看、修改和校验配置。
+
+配置按以下优先级合并（从高到低）：
+  database  控制台设置页保存到 records.db 的值
+  override  命令行参数（如 --port）
+  env       WX_CHANNEL_* 环境变量（嵌套键用下划线，如 WX_CHANNEL_HUB_SYNC_ENABLED）
+  file      config.yaml
+  default   内置默认值`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "列出所有配置项的生效值及来源",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		closeDB := loadConfigWithDatabase()
+		defer closeDB()
+
+		items := config.Effective(configShowSecrets)
+		if configShowJSON {
+			printJSON(items)
+			return
+		}
+
+		if file := viper.ConfigFileUsed(); file != "" {
+			fmt.Printf("配置文件: %s\n\n", file)
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+		for _, ev := range items {
+			fmt.Fprintf(tw, "%s\t%v\t%s\n", ev.Key, ev.Value, ev.Source)
+		}
+		tw.Flush()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "获取单个配置项的生效值及来源",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		closeDB := loadConfigWithDatabase()
+		defer closeDB()
+
+		ev, ok := config.Lookup(args[0], configShowSecrets)
+		if !ok {
+			color.Red("未知的配置项: %s\n", args[0])
+			closeDB()
+			os.Exit(1)
+		}
+		if configShowJSON {
+			printJSON(ev)
+			return
+		}
+		fmt.Printf("%v\t(%s)\n", ev.Value, ev.Source)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "校验后将配置项写入配置文件",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		closeDB := loadConfigWithDatabase()
+		defer closeDB()
+
+		configFile := viper.ConfigFileUsed()
+		if configFile == "" {
+			configFile = "config.yaml"
+		}
+
+		if err := config.SetFileValue(configFile, args[0], args[1]); err != nil {
+			color.Red("%v\n", err)
+			closeDB()
+			os.Exit(1)
+		}
+		color.Green("✓ 已写入 %s: %s\n", configFile, args[0])
+
+		// 更高优先级的来源会覆盖配置文件中的值
+		config.Reload()
+		if src := config.SourceOf(args[0]); src != config.SourceFile {
+			color.Yellow("注意：%s 当前由 %s 层覆盖，配置文件中的值暂不生效\n", args[0], src)
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "校验配置（未知键、类型错误、取值范围）",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		closeDB := loadConfigWithDatabase()
+		defer closeDB()
+
+		if err := config.Check(); err != nil {
+			color.Red("%v\n", err)
+			closeDB()
+			os.Exit(1)
+		}
+		color.Green("✓ 配置有效\n")
+	},
+}
+
+// loadConfigWithDatabase 加载配置，并在 records.db 已存在时叠加数据库层
+// 返回的函数用于关闭数据库
+func loadConfigWithDatabase() func() {
+	cfg := config.Load()
+
+	downloadsDir, err := cfg.GetResolvedDownloadsDir()
+	if err != nil {
+		return func() {}
+	}
+	dbPath := filepath.Join(downloadsDir, "records.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return func() {}
+	}
+	if err := database.Initialize(&database.Config{DBPath: dbPath}); err != nil {
+		color.Yellow("无法打开数据库 %s，忽略数据库配置: %v\n", dbPath, err)
+		return func() {}
+	}
+
+	config.SetDatabaseLoader(database.NewSettingsRepository())
+	config.Reload()
+	return func() { _ = database.Close() }
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func init() {
+	configCmd.PersistentFlags().BoolVar(&configShowJSON, "json", false, "以 JSON 格式输出")
+	configCmd.PersistentFlags().BoolVar(&configShowSecrets, "show-secrets", false, "显示敏感配置项的明文")
+
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd, configGetCmd, configSetCmd, configValidateCmd)
+}
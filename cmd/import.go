@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"wx_channel/internal/config"
+	"wx_channel/internal/database"
+	"wx_channel/internal/services"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat   string
+	importStrategy string
+	importDryRun   bool
+	importJSON     bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "从导出文件导入浏览记录或下载记录",
+	Long: `从控制台导出的 JSON 或 CSV 文件导入浏览记录或下载记录，可用于恢复备份或合并多台机器的记录。
+
+ID 已存在时的处理方式（--strategy）：
+  skip       保留已有记录（默认）
+  overwrite  用导入的记录覆盖
+  newest     按更新时间（UpdatedAt）保留较新的一条
+
+程序运行时也可以通过 POST /api/import/browse 和 /api/import/downloads 导入。`,
+}
+
+var importBrowseCmd = &cobra.Command{
+	Use:   "browse <file>",
+	Short: "导入浏览记录",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runImport(args[0], func(s *services.ImportService, data []byte, opts services.ImportOptions) (*services.ImportReport, error) {
+			return s.ImportBrowseHistory(data, opts)
+		})
+	},
+}
+
+var importDownloadsCmd = &cobra.Command{
+	Use:   "downloads <file>",
+	Short: "导入下载记录",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runImport(args[0], func(s *services.ImportService, data []byte, opts services.ImportOptions) (*services.ImportReport, error) {
+			return s.ImportDownloadRecords(data, opts)
+		})
+	},
+}
+
+// runImport 读取文件并导入到下载目录下的 records.db，打印导入结果
+func runImport(file string, run func(*services.ImportService, []byte, services.ImportOptions) (*services.ImportReport, error)) {
+	strategy, err := services.ParseImportStrategy(importStrategy)
+	if err != nil {
+		color.Red("%v\n", err)
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		color.Red("读取文件失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	closeDB, err := openRecordsDatabase()
+	if err != nil {
+		color.Red("%v\n", err)
+		os.Exit(1)
+	}
+	defer closeDB()
+
+	report, err := run(services.NewImportService(), data, services.ImportOptions{
+		Format:   services.ExportFormat(strings.ToLower(importFormat)),
+		Strategy: strategy,
+		DryRun:   importDryRun,
+	})
+	if err != nil {
+		color.Red("导入失败: %v\n", err)
+		closeDB()
+		os.Exit(1)
+	}
+
+	if importJSON {
+		printJSON(report)
+		return
+	}
+	if report.DryRun {
+		color.Yellow("预览模式，未写入数据库\n")
+	}
+	fmt.Printf("格式: %s  策略: %s\n", report.Format, report.Strategy)
+	fmt.Printf("共 %d 行：新增 %d，覆盖 %d，跳过 %d，失败 %d\n",
+		report.Total, report.Created, report.Updated, report.Skipped, report.Failed)
+	for _, e := range report.Errors {
+		color.Red("  第 %d 行 %s: %s\n", e.Row, e.ID, e.Error)
+	}
+}
+
+// openRecordsDatabase 打开下载目录下的 records.db（不存在时创建），返回的函数用于关闭数据库
+func openRecordsDatabase() (func(), error) {
+	cfg := config.Load()
+	downloadsDir, err := cfg.GetResolvedDownloadsDir()
+	if err != nil {
+		return nil, fmt.Errorf("无法解析下载目录: %w", err)
+	}
+	dbPath := filepath.Join(downloadsDir, "records.db")
+	if err := database.Initialize(&database.Config{DBPath: dbPath}); err != nil {
+		return nil, fmt.Errorf("无法打开数据库 %s: %w", dbPath, err)
+	}
+	return func() { _ = database.Close() }, nil
+}
+
+func init() {
+	importCmd.PersistentFlags().StringVar(&importFormat, "format", "", "文件格式 json 或 csv（默认根据内容识别）")
+	importCmd.PersistentFlags().StringVar(&importStrategy, "strategy", "skip", "ID 已存在时的处理方式：skip、overwrite、newest")
+	importCmd.PersistentFlags().BoolVar(&importDryRun, "dry-run", false, "只预览结果，不写入数据库")
+	importCmd.PersistentFlags().BoolVar(&importJSON, "json", false, "以 JSON 格式输出结果")
+
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importBrowseCmd, importDownloadsCmd)
+}
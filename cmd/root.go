@@ -6,6 +6,7 @@ import (
 	"wx_channel/internal/app"
 	"wx_channel/internal/config"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -27,6 +28,13 @@ var rootCmd = &cobra.Command{
 			cfg.SetPort(port)
 		}
 
+		// 配置有误时直接退出，避免带着错误的值运行
+		if err := config.Check(); err != nil {
+			color.Red("%v\n", err)
+			color.Yellow("请修正配置后重新启动，可运行 `wx_channel config validate` 复查\n")
+			os.Exit(1)
+		}
+
 		// 创建并运行应用
 		application := app.NewApp(cfg)
 		application.Run()
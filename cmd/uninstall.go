@@ -12,29 +12,40 @@ import (
 var uninstallCmd = &cobra.Command{
 	Use:   "uninstall",
 	Short: "卸载根证书",
-	Long:  `卸载 SunnyNet 根证书。如果证书未安装，无需卸载。`,
+	Long:  `卸载本机根证书（以及旧版本安装的 SunnyNet 公共根证书）。如果证书未安装，无需卸载。`,
 	Run: func(cmd *cobra.Command, args []string) {
 		color.Yellow("正在卸载根证书...\n")
 
-		// 检查证书是否存在
-		existing, err := certificate.CheckCertificate("SunnyNet")
-		if err != nil {
-			color.Red("检查证书时发生错误: %v\n", err.Error())
-			color.Yellow("请手动检查证书是否已安装。\n")
-			os.Exit(1)
+		names := []string{certificate.LegacyCertName}
+		if ca, err := certificate.LoadCA(certificate.DefaultCADir()); err == nil {
+			names = append([]string{ca.Name()}, names...)
 		}
 
-		if !existing {
-			color.Green("✓ 证书未安装，无需卸载。\n")
-			os.Exit(0)
+		removed := 0
+		for _, name := range names {
+			// 检查证书是否存在
+			existing, err := certificate.CheckCertificate(name)
+			if err != nil {
+				color.Red("检查证书时发生错误: %v\n", err.Error())
+				color.Yellow("请手动检查证书是否已安装。\n")
+				os.Exit(1)
+			}
+			if !existing {
+				continue
+			}
+
+			// 尝试卸载证书
+			if err := certificate.RemoveCertificate(name); err != nil {
+				color.Red("卸载证书 %s 失败: %v\n", name, err.Error())
+				color.Yellow("请尝试以管理员身份运行此命令。\n")
+				os.Exit(1)
+			}
+			removed++
 		}
 
-		// 尝试卸载证书
-		err = certificate.RemoveCertificate("SunnyNet")
-		if err != nil {
-			color.Red("卸载证书失败: %v\n", err.Error())
-			color.Yellow("请尝试以管理员身份运行此命令。\n")
-			os.Exit(1)
+		if removed == 0 {
+			color.Green("✓ 证书未安装，无需卸载。\n")
+			os.Exit(0)
 		}
 
 		color.Green("✓ 证书卸载成功！\n")
@@ -283,6 +283,20 @@ func FetchVideos(hub *ws.Hub) http.HandlerFunc {
 				continue
 			}
 
+			// 每次抓取都记录互动数据快照，已存在的视频也不例外
+			snapshot := models.VideoStatsSnapshot{
+				SubscriptionID: subscription.ID,
+				ObjectID:       objectID,
+				LikeCount:      getIntField(actualVideo, "likeCount"),
+				CommentCount:   getIntField(actualVideo, "commentCount"),
+				FavCount:       getIntField(actualVideo, "favCount"),
+				ForwardCount:   getIntField(actualVideo, "forwardCount"),
+				CapturedAt:     time.Now(),
+			}
+			if err := database.DB.Create(&snapshot).Error; err != nil {
+				fmt.Printf("[Subscription] Failed to save stats snapshot %s: %v\n", objectID, err)
+			}
+
 			// Check if video already exists
 			var existing models.SubscribedVideo
 			existsErr := database.DB.Where("subscription_id = ? AND object_id = ?", subscription.ID, objectID).First(&existing).Error
@@ -455,6 +469,41 @@ func GetSubscriptionVideos(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetSubscriptionVideoStats 获取订阅视频的互动数据时间序列
+func GetSubscriptionVideoStats(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.ContextKeyUserID).(uint)
+	vars := mux.Vars(r)
+	subID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	// Verify subscription belongs to user
+	var subscription models.Subscription
+	if err := database.DB.Where("id = ? AND user_id = ?", subID, userID).First(&subscription).Error; err != nil {
+		http.Error(w, "Subscription not found", http.StatusNotFound)
+		return
+	}
+
+	var snapshots []models.VideoStatsSnapshot
+	if err := database.DB.Where("subscription_id = ? AND object_id = ?", subID, vars["objectId"]).
+		Order("captured_at asc").
+		Find(&snapshots).Error; err != nil {
+		http.Error(w, "Failed to fetch stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": 0,
+		"data": map[string]interface{}{
+			"object_id": vars["objectId"],
+			"points":    snapshots,
+		},
+	})
+}
+
 // DeleteSubscription 取消订阅
 func DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(middleware.ContextKeyUserID).(uint)
@@ -479,6 +528,7 @@ func DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 
 	// Also delete associated videos
 	database.DB.Where("subscription_id = ?", subID).Delete(&models.SubscribedVideo{})
+	database.DB.Where("subscription_id = ?", subID).Delete(&models.VideoStatsSnapshot{})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -46,6 +46,7 @@ func InitDB(path string) error {
 		&models.Setting{},
 		&models.Subscription{},
 		&models.SubscribedVideo{},
+		&models.VideoStatsSnapshot{},
 		&models.HubBrowseHistory{},
 		&models.HubDownloadRecord{},
 		&models.SyncStatus{},
@@ -392,6 +393,10 @@ func DeleteSubscription(id uint) error {
 		tx.Rollback()
 		return err
 	}
+	if err := tx.Where("subscription_id = ?", id).Delete(&models.VideoStatsSnapshot{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
 
 	// 删除订阅
 	if err := tx.Delete(&models.Subscription{}, id).Error; err != nil {
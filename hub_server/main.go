@@ -115,6 +115,7 @@ func main() {
 	auth.HandleFunc("/api/subscriptions", controllers.GetSubscriptions).Methods("GET")
 	auth.HandleFunc("/api/subscriptions/{id}/fetch", controllers.FetchVideos(hub)).Methods("POST")
 	auth.HandleFunc("/api/subscriptions/{id}/videos", controllers.GetSubscriptionVideos).Methods("GET")
+	auth.HandleFunc("/api/subscriptions/{id}/videos/{objectId}/stats/history", controllers.GetSubscriptionVideoStats).Methods("GET")
 	auth.HandleFunc("/api/subscriptions/{id}", controllers.DeleteSubscription).Methods("DELETE")
 
 	// Task & Remote Call
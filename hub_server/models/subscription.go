@@ -55,3 +55,15 @@ type SubscribedVideo struct {
 	PublishedAt time.Time `json:"published_at"` // 微信发布时间（createTime）
 	CreatedAt   time.Time `json:"created_at"`   // 添加到数据库时间
 }
+
+// VideoStatsSnapshot 订阅视频互动数据快照 - 每次抓取追加一条，用于趋势分析
+type VideoStatsSnapshot struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint      `json:"subscription_id" gorm:"index"`
+	ObjectID       string    `json:"object_id" gorm:"index:idx_stats_object_time;not null"`
+	LikeCount      int       `json:"like_count"`
+	CommentCount   int       `json:"comment_count"`
+	FavCount       int       `json:"fav_count"`
+	ForwardCount   int       `json:"forward_count"`
+	CapturedAt     time.Time `json:"captured_at" gorm:"index:idx_stats_object_time"`
+}
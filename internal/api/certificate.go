@@ -3,7 +3,6 @@ package api
 import (
 	"net/http"
 
-	"wx_channel/internal/assets"
 	"wx_channel/internal/response"
 	"wx_channel/pkg/certificate"
 
@@ -22,29 +21,37 @@ func NewCertificateService(sunny *SunnyNet.Sunny) *CertificateService {
 	}
 }
 
-// GetStatus 获取证书状态
+// GetStatus 获取证书状态（名称、指纹、有效期、是否已安装）
 func (s *CertificateService) GetStatus(w http.ResponseWriter, r *http.Request) {
-	// 检查 "SunnyNet" 证书是否安装
-	installed, err := certificate.CheckCertificate("SunnyNet")
+	caDir := certificate.DefaultCADir()
+	ca, err := certificate.LoadCA(caDir)
 	if err != nil {
-		response.Error(w, 500, "Failed to check certificate: "+err.Error())
+		response.Error(w, 500, "Failed to load certificate: "+err.Error())
 		return
 	}
 
-	status := map[string]interface{}{
-		"installed": installed,
-		"name":      "SunnyNet",
+	installed, err := certificate.CheckCertificate(ca.Name())
+	if err != nil {
+		response.Error(w, 500, "Failed to check certificate: "+err.Error())
+		return
 	}
-	response.Success(w, status)
+
+	info := ca.Info()
+	info.CertPath = certificate.CertPath(caDir)
+	response.Success(w, struct {
+		certificate.CAInfo
+		Installed bool `json:"installed"`
+	}{info, installed})
 }
 
 // Install 安装证书
 func (s *CertificateService) Install(w http.ResponseWriter, r *http.Request) {
-	// 调用 pkg/certificate 进行安装
-	// 使用内置的证书数据
-	// 注意：assets.CertData 是 []byte 类型
-	err := certificate.InstallCertificate(assets.CertData)
+	ca, err := certificate.LoadCA(certificate.DefaultCADir())
 	if err != nil {
+		response.Error(w, 500, "Failed to load certificate: "+err.Error())
+		return
+	}
+	if err := certificate.InstallCertificate(ca.CertPEM); err != nil {
 		// 证书安装可能因为用户取消或权限不足失败
 		response.Error(w, 500, "Failed to install certificate: "+err.Error())
 		return
@@ -53,16 +60,23 @@ func (s *CertificateService) Install(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, "Certificate installation started/completed")
 }
 
-// Download 下载证书
+// Download 下载证书（仅公钥证书，私钥不对外提供）
 func (s *CertificateService) Download(w http.ResponseWriter, r *http.Request) {
+	ca, err := certificate.LoadCA(certificate.DefaultCADir())
+	if err != nil {
+		response.Error(w, 500, "Failed to load certificate: "+err.Error())
+		return
+	}
 	// 提供证书下载，方便用户手动安装
-	w.Header().Set("Content-Disposition", "attachment; filename=SunnyRoot.cer")
+	w.Header().Set("Content-Disposition", "attachment; filename="+certificate.ExportFileName)
 	w.Header().Set("Content-Type", "application/x-x509-ca-cert")
-	w.Write(assets.CertData)
+	w.Write(ca.CertPEM)
 }
 
 // RegisterRoutes 注册路由
 func (s *CertificateService) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/certificate", s.GetStatus)
+	mux.HandleFunc("/api/v1/certificate", s.GetStatus)
 	mux.HandleFunc("/api/v1/certificate/status", s.GetStatus)
 	mux.HandleFunc("/api/v1/certificate/install", s.Install)
 	mux.HandleFunc("/api/v1/certificate/download", s.Download)
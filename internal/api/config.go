@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"wx_channel/internal/config"
+	"wx_channel/internal/response"
+
+	"github.com/spf13/viper"
+)
+
+// ConfigAPI 配置查看接口
+type ConfigAPI struct{}
+
+// NewConfigAPI 创建配置查看接口
+func NewConfigAPI() *ConfigAPI {
+	return &ConfigAPI{}
+}
+
+// EffectiveConfig 生效配置及校验结果（敏感项始终脱敏）
+type EffectiveConfig struct {
+	ConfigFile string                  `json:"config_file"`
+	Items      []config.EffectiveValue `json:"items"`
+	Problems   []string                `json:"problems"`
+}
+
+// GetEffective 获取每个配置项的生效值与来源层
+// GET /api/v1/config/effective[?key=download_dir]
+func (a *ConfigAPI) GetEffective(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.ErrorWithStatus(w, http.StatusMethodNotAllowed, 405, "method not allowed")
+		return
+	}
+
+	if key := r.URL.Query().Get("key"); key != "" {
+		ev, ok := config.Lookup(key, false)
+		if !ok {
+			response.ErrorWithStatus(w, http.StatusNotFound, 404, "unknown config key: "+key)
+			return
+		}
+		response.Success(w, ev)
+		return
+	}
+
+	result := EffectiveConfig{
+		ConfigFile: viper.ConfigFileUsed(),
+		Items:      config.Effective(false),
+		Problems:   []string{},
+	}
+	var verr *config.ValidationError
+	if err := config.Check(); errors.As(err, &verr) {
+		result.Problems = verr.Problems
+	}
+
+	response.Success(w, result)
+}
+
+// RegisterRoutes 注册路由
+func (a *ConfigAPI) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/config/effective", a.GetEffective)
+}
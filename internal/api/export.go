@@ -2,23 +2,39 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"wx_channel/internal/database"
 	"wx_channel/internal/response"
 	"wx_channel/internal/services"
+	"wx_channel/internal/utils"
 )
 
 type ExportAPI struct {
 	service *services.ExportService
+	views   *services.ViewService
 }
 
 func NewExportAPI() *ExportAPI {
 	return &ExportAPI{
 		service: services.NewExportService(),
+		views:   services.NewViewService(),
 	}
 }
 
+// exportFilter 根据 query/view 构造过滤参数，两者都为空时返回 nil 表示导出全部
+func (h *ExportAPI) exportFilter(query, view, target string) (*database.FilterParams, error) {
+	if query == "" && view == "" {
+		return nil, nil
+	}
+	params := &database.FilterParams{Query: query}
+	if err := h.views.ResolveFilter(params, view, target, true); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
 // HandleExportDownloadRecords 导出下载记录
 func (h *ExportAPI) HandleExportDownloadRecords(w http.ResponseWriter, r *http.Request) {
 	// 解析格式（默认 csv）
@@ -30,6 +46,8 @@ func (h *ExportAPI) HandleExportDownloadRecords(w http.ResponseWriter, r *http.R
 	// 解析 ID（可选）
 	// 支持查询字符串中的 ids 参数（逗号分隔）或 JSON 请求体
 	var ids []string
+	query := r.URL.Query().Get("query")
+	view := r.URL.Query().Get("view")
 	if r.Method == http.MethodGet {
 		idsStr := r.URL.Query().Get("ids")
 		if idsStr != "" {
@@ -39,6 +57,8 @@ func (h *ExportAPI) HandleExportDownloadRecords(w http.ResponseWriter, r *http.R
 		var req struct {
 			IDs    []string `json:"ids"`
 			Format string   `json:"format"`
+			Query  string   `json:"query"`
+			View   string   `json:"view"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err == nil {
 			if len(req.IDs) > 0 {
@@ -47,30 +67,33 @@ func (h *ExportAPI) HandleExportDownloadRecords(w http.ResponseWriter, r *http.R
 			if req.Format != "" {
 				formatStr = req.Format
 			}
+			if req.Query != "" {
+				query = req.Query
+			}
+			if req.View != "" {
+				view = req.View
+			}
 		}
 	} else {
 		response.ErrorWithStatus(w, http.StatusMethodNotAllowed, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	// 确定格式
-	format := services.ExportFormat(strings.ToLower(formatStr))
-	if format != services.ExportFormatCSV && format != services.ExportFormatJSON {
+	// 确定格式，未知格式按 CSV 导出
+	format, ok := services.ParseExportFormat(formatStr)
+	if !ok {
 		format = services.ExportFormatCSV
 	}
 
-	// 执行导出
-	result, err := h.service.ExportDownloadRecords(format, ids)
+	// 执行导出：指定 ID 时按 ID 导出，否则按查询语法或已保存视图过滤
+	filter, err := h.exportFilter(query, view, database.ViewTargetDownloads)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	// 设置文件下载响应头
-	w.Header().Set("Content-Type", result.ContentType)
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", result.Filename))
-	w.WriteHeader(http.StatusOK)
-	w.Write(result.Data)
+	streamExport(w, "download_records", format, func(out io.Writer) (int, error) {
+		return h.service.StreamDownloadRecords(out, format, ids, filter)
+	})
 }
 
 // HandleExportBrowseHistory 导出浏览历史
@@ -83,6 +106,8 @@ func (h *ExportAPI) HandleExportBrowseHistory(w http.ResponseWriter, r *http.Req
 
 	// 解析 ID（可选）
 	var ids []string
+	query := r.URL.Query().Get("query")
+	view := r.URL.Query().Get("view")
 	if r.Method == http.MethodGet {
 		idsStr := r.URL.Query().Get("ids")
 		if idsStr != "" {
@@ -92,6 +117,8 @@ func (h *ExportAPI) HandleExportBrowseHistory(w http.ResponseWriter, r *http.Req
 		var req struct {
 			IDs    []string `json:"ids"`
 			Format string   `json:"format"`
+			Query  string   `json:"query"`
+			View   string   `json:"view"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err == nil {
 			if len(req.IDs) > 0 {
@@ -100,28 +127,46 @@ func (h *ExportAPI) HandleExportBrowseHistory(w http.ResponseWriter, r *http.Req
 			if req.Format != "" {
 				formatStr = req.Format
 			}
+			if req.Query != "" {
+				query = req.Query
+			}
+			if req.View != "" {
+				view = req.View
+			}
 		}
 	} else {
 		response.ErrorWithStatus(w, http.StatusMethodNotAllowed, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	// 确定格式
-	format := services.ExportFormat(strings.ToLower(formatStr))
-	if format != services.ExportFormatCSV && format != services.ExportFormatJSON {
+	// 确定格式，未知格式按 CSV 导出
+	format, ok := services.ParseExportFormat(formatStr)
+	if !ok {
 		format = services.ExportFormatCSV
 	}
 
-	// 执行导出
-	result, err := h.service.ExportBrowseHistory(format, ids)
+	// 执行导出：指定 ID 时按 ID 导出，否则按查询语法或已保存视图过滤
+	filter, err := h.exportFilter(query, view, database.ViewTargetBrowse)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	streamExport(w, "browse_history", format, func(out io.Writer) (int, error) {
+		return h.service.StreamBrowseHistory(out, format, ids, filter)
+	})
+}
 
-	// 设置文件下载响应头
-	w.Header().Set("Content-Type", result.ContentType)
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", result.Filename))
-	w.WriteHeader(http.StatusOK)
-	w.Write(result.Data)
+// streamExport 边查询边写出导出文件；开始写出之前出错时返回 JSON 错误，之后出错只能中断并记录日志
+func streamExport(w http.ResponseWriter, prefix string, format services.ExportFormat, run func(io.Writer) (int, error)) {
+	out := response.NewAttachmentWriter(w, format.ContentType(), services.GenerateTimestampFilename(prefix, format))
+	count, err := run(out)
+	if err != nil {
+		if !out.Started() {
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.LogWarn("[导出] %s 导出中断（已写出 %d 条）: %v", prefix, count, err)
+		return
+	}
+	out.Start()
 }
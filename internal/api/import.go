@@ -0,0 +1,95 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"wx_channel/internal/response"
+	"wx_channel/internal/services"
+)
+
+// maxImportSize 导入文件大小上限
+const maxImportSize = 200 << 20
+
+// ImportAPI 处理浏览记录和下载记录导入
+type ImportAPI struct {
+	service *services.ImportService
+}
+
+// NewImportAPI 创建导入 API 处理器
+func NewImportAPI() *ImportAPI {
+	return &ImportAPI{service: services.NewImportService()}
+}
+
+// HandleImportBrowse 导入浏览记录
+func (h *ImportAPI) HandleImportBrowse(w http.ResponseWriter, r *http.Request) {
+	h.handleImport(w, r, h.service.ImportBrowseHistory)
+}
+
+// HandleImportDownloads 导入下载记录
+func (h *ImportAPI) HandleImportDownloads(w http.ResponseWriter, r *http.Request) {
+	h.handleImport(w, r, h.service.ImportDownloadRecords)
+}
+
+// handleImport 读取上传内容（multipart 的 file 字段或原始请求体）并按 format/strategy/dryRun 参数导入
+func (h *ImportAPI) handleImport(w http.ResponseWriter, r *http.Request,
+	run func([]byte, services.ImportOptions) (*services.ImportReport, error)) {
+	if r.Method != http.MethodPost {
+		response.ErrorWithStatus(w, http.StatusMethodNotAllowed, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	strategy, err := services.ParseImportStrategy(q.Get("strategy"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	dryRun, _ := strconv.ParseBool(q.Get("dryRun"))
+	opts := services.ImportOptions{
+		Format:   services.ExportFormat(strings.ToLower(q.Get("format"))),
+		Strategy: strategy,
+		DryRun:   dryRun,
+	}
+
+	data, err := readImportBody(w, r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "读取导入文件失败: "+err.Error())
+		return
+	}
+	if len(data) == 0 {
+		response.Error(w, http.StatusBadRequest, "导入文件为空")
+		return
+	}
+
+	report, err := run(data, opts)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(w, report)
+}
+
+// readImportBody 读取导入内容，支持 multipart/form-data 上传和直接提交文件内容
+func readImportBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+	return io.ReadAll(r.Body)
+}
+
+// RegisterRoutes 注册导入路由
+func (h *ImportAPI) RegisterRoutes(mux *http.ServeMux) {
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		mux.HandleFunc(prefix+"/import/browse", h.HandleImportBrowse)
+		mux.HandleFunc(prefix+"/import/downloads", h.HandleImportDownloads)
+	}
+}
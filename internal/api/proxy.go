@@ -2,8 +2,10 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"wx_channel/internal/response"
+	"wx_channel/pkg/proxy"
 
 	"github.com/qtgolang/SunnyNet/SunnyNet"
 )
@@ -37,6 +39,22 @@ func (s *ProxyService) GetStatus(w http.ResponseWriter, r *http.Request) {
 		"version": "SunnyNet (latest)", // 无法直接获取版本？
 		"mode":    "中间人代理 (MITM)",
 	}
+
+	// 系统代理的实际状态（GNOME/KDE/环境变量文件/macOS），Windows 使用进程代理不涉及
+	system, err := proxy.ProxySettings{Hostname: "127.0.0.1", Port: strconv.Itoa(s.port)}.Status()
+	systemStatus := map[string]interface{}{
+		"backends": system,
+		"enabled":  false,
+	}
+	for _, b := range system {
+		if b.PointsToUs {
+			systemStatus["enabled"] = true
+		}
+	}
+	if err != nil {
+		systemStatus["error"] = err.Error()
+	}
+	status["system"] = systemStatus
 	response.Success(w, status)
 }
 
@@ -65,5 +83,6 @@ func (s *ProxyService) Restart(w http.ResponseWriter, r *http.Request) {
 // RegisterRoutes 注册路由
 func (s *ProxyService) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/proxy/status", s.GetStatus)
+	mux.HandleFunc("/api/proxy/status", s.GetStatus)
 	mux.HandleFunc("/api/v1/proxy/restart", s.Restart)
 }
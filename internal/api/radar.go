@@ -2,28 +2,51 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"wx_channel/internal/database"
 	"wx_channel/internal/response"
+	"wx_channel/internal/services"
+	"wx_channel/internal/websocket"
 )
 
 // RadarServiceAPI 处理雷达监控相关的 API
 type RadarServiceAPI struct {
-	repo *database.RadarRepository
+	repo     *database.RadarRepository
+	settings *database.SettingsRepository
+	importer *services.RadarImportService
+	scanner  *services.RadarService // 用于预览和导入时查找昵称，与轮询共用每小时请求额度
 }
 
-// NewRadarServiceAPI 创建雷达服务 API 处理器
-func NewRadarServiceAPI() *RadarServiceAPI {
+// NewRadarServiceAPI 创建雷达服务 API 处理器，hub 用于导入时通过昵称查找账号和预览时拉取视频列表；
+// scanner 为运行中的雷达服务，为 nil 时创建一个不启动轮询的实例
+func NewRadarServiceAPI(hub *websocket.Hub, scanner *services.RadarService) *RadarServiceAPI {
+	repo := database.NewRadarRepository()
+	if scanner == nil {
+		scanner = services.NewRadarService(repo, services.NewQueueService(), hub)
+	}
 	return &RadarServiceAPI{
-		repo: database.NewRadarRepository(),
+		repo:     repo,
+		settings: database.NewSettingsRepository(),
+		importer: services.NewRadarImportService(hub, scanner),
+		scanner:  scanner,
 	}
 }
 
-// GetTargets 获取所有监控目标
+// GetTargets 获取所有监控目标，可按 group 筛选
 func (h *RadarServiceAPI) GetTargets(w http.ResponseWriter, r *http.Request) {
-	targets, err := h.repo.GetAll()
+	var targets []database.RadarTarget
+	var err error
+	if r.URL.Query().Has("group") {
+		targets, err = h.repo.GetByGroup(strings.TrimSpace(r.URL.Query().Get("group")))
+	} else {
+		targets, err = h.repo.GetAll()
+	}
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "获取监控目标失败")
 		return
@@ -39,26 +62,21 @@ func (h *RadarServiceAPI) AddTarget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	target.Username = strings.TrimSpace(target.Username)
-	target.AuthorName = strings.TrimSpace(target.AuthorName)
-
-	if target.Username == "" || target.AuthorName == "" {
-		response.Error(w, http.StatusBadRequest, "账号ID和账号名称不能为空")
+	h.applyGroupDefaults(&target)
+	if err := target.Normalize(); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if target.IntervalMinutes < 5 {
-		target.IntervalMinutes = 5 // 最少5分钟
-	}
-
-	if target.Status == "" {
-		target.Status = database.RadarStatusActive
+	// 提交 backfill 时添加后立即回填历史视频，只接受 max_pages 和 since
+	if target.Backfill != nil {
+		target.Backfill = newManualBackfill(target.Backfill.MaxPages, target.Backfill.Since)
 	}
 
 	if err := h.repo.Add(&target); err != nil {
 		// 判断是否是唯一键冲突
 		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-			response.Error(w, http.StatusConflict, "该账号已在监控列表中")
+			response.Error(w, http.StatusConflict, "该账号或关键词已在监控列表中")
 			return
 		}
 		response.Error(w, http.StatusInternalServerError, "添加监控目标失败")
@@ -81,19 +99,32 @@ func (h *RadarServiceAPI) UpdateTarget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	target.ID = id
-	if target.IntervalMinutes < 5 {
-		target.IntervalMinutes = 5
-	}
 
-	// 保留之前的检测时间
+	// 目标类型不可修改
 	existing, err := h.repo.GetByID(id)
+	if err == nil && existing != nil {
+		target.Type = existing.Type
+	}
+	h.applyGroupDefaults(&target)
+	if err := target.Normalize(); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 保留之前的检测时间；未提交 filter 时保留原有规则，提交空对象表示清除
 	if err == nil && existing != nil {
 		target.LastCheckTime = existing.LastCheckTime
+		target.Backfill = existing.Backfill
+		target.Health, target.ConsecutiveFailures = existing.Health, existing.ConsecutiveFailures
+		target.LastError, target.LastErrorTime = existing.LastError, existing.LastErrorTime
+		if target.Filter == nil {
+			target.Filter = existing.Filter
+		}
 	}
 
 	if err := h.repo.Update(&target); err != nil {
 		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-			response.Error(w, http.StatusConflict, "该账号已被其他记录占用")
+			response.Error(w, http.StatusConflict, "该账号或关键词已被其他记录占用")
 			return
 		}
 		response.Error(w, http.StatusInternalServerError, "更新监控目标失败")
@@ -159,6 +190,22 @@ func (h *RadarServiceAPI) GetRadarLogs(w http.ResponseWriter, r *http.Request) {
 	}
 	id := pathParts[len(pathParts)-2]
 
+	// 带分页参数时返回分页结果（与浏览记录、下载记录相同的 page/pageSize/cursor/skipTotal 约定）
+	q := r.URL.Query()
+	if q.Has("page") || q.Has("pageSize") || q.Has("cursor") {
+		result, err := h.repo.GetLogsPage(id, parsePagination(r))
+		if errors.Is(err, database.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "获取日志失败")
+			return
+		}
+		response.Success(w, result)
+		return
+	}
+
 	logs, err := h.repo.GetLogsByTargetID(id, 50)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "获取日志失败")
@@ -172,38 +219,592 @@ func (h *RadarServiceAPI) GetRadarLogs(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, logs)
 }
 
-// RegisterRoutes 注册雷达相关的 API 路由
-func (h *RadarServiceAPI) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/api/v1/radar/targets", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			h.GetTargets(w, r)
-		case http.MethodPost:
-			h.AddTarget(w, r)
-		default:
-			response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+// parsePagination 从查询字符串中提取分页参数，默认按时间倒序
+func parsePagination(r *http.Request) *database.PaginationParams {
+	q := r.URL.Query()
+	params := &database.PaginationParams{SortDesc: true}
+	params.Page, _ = strconv.Atoi(q.Get("page"))
+	params.PageSize, _ = strconv.Atoi(q.Get("pageSize"))
+	if sortDesc := q.Get("sortDesc"); sortDesc != "" {
+		params.SortDesc = sortDesc == "true" || sortDesc == "1"
+	}
+	if q.Has("cursor") {
+		params.Keyset = true
+		params.Cursor = q.Get("cursor")
+	}
+	params.SkipTotal, _ = strconv.ParseBool(q.Get("skipTotal"))
+	return params
+}
+
+// StartBackfill 发起回填：下一轮检测时从第一页开始向前翻页，可选 max_pages 和 since（RFC3339 或 2006-01-02）
+func (h *RadarServiceAPI) StartBackfill(w http.ResponseWriter, r *http.Request, id string) {
+	target, err := h.repo.GetByID(id)
+	if err != nil || target == nil {
+		response.Error(w, http.StatusNotFound, "监控目标不存在")
+		return
+	}
+	if target.Backfill != nil && target.Backfill.Status == database.RadarBackfillPending {
+		response.Error(w, http.StatusConflict, "该目标正在回填中")
+		return
+	}
+
+	var req struct {
+		MaxPages int    `json:"max_pages"`
+		Since    string `json:"since"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+			return
+		}
+	}
+	since, err := parseTimeParam(req.Since, false)
+	if err != nil || req.MaxPages < 0 {
+		response.Error(w, http.StatusBadRequest, "无效的回填参数")
+		return
+	}
+	var sincePtr *time.Time
+	if !since.IsZero() {
+		sincePtr = &since
+	}
+
+	backfill := newManualBackfill(req.MaxPages, sincePtr)
+	if err := h.repo.UpdateBackfill(id, backfill); err != nil {
+		response.Error(w, http.StatusInternalServerError, "发起回填失败")
+		return
+	}
+	response.Success(w, backfill)
+}
+
+// PreviewTarget 预览已保存目标的一次检测：只拉取第一页，按当前规则和去重逻辑判断每个视频，不加入队列。
+// 请求体可选，其中的 filter 会临时替换目标自己的过滤规则，用于调整规则前试看效果
+func (h *RadarServiceAPI) PreviewTarget(w http.ResponseWriter, r *http.Request, id string) {
+	target, err := h.repo.GetByID(id)
+	if err != nil || target == nil {
+		response.Error(w, http.StatusNotFound, "监控目标不存在")
+		return
+	}
+
+	var req struct {
+		Filter *database.RadarFilter `json:"filter"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+			return
+		}
+	}
+	if req.Filter != nil {
+		if err := req.Filter.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "过滤规则无效: "+err.Error())
+			return
+		}
+		target.Filter = req.Filter
+	}
+	h.preview(w, *target)
+}
+
+// PreviewNewTarget 预览尚未保存的目标，请求体与添加监控目标相同
+func (h *RadarServiceAPI) PreviewNewTarget(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	var target database.RadarTarget
+	if err := json.NewDecoder(r.Body).Decode(&target); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+	h.applyGroupDefaults(&target)
+	if err := target.Normalize(); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.preview(w, target)
+}
+
+func (h *RadarServiceAPI) preview(w http.ResponseWriter, target database.RadarTarget) {
+	result, err := h.scanner.Preview(target)
+	if services.IsRadarBudgetExhausted(err) {
+		response.Error(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadGateway, "预览失败: "+err.Error())
+		return
+	}
+	response.Success(w, result)
+}
+
+// ImportTargets 批量导入监控目标，参数与记录导入相同（format、strategy、dryRun），
+// 冲突判断按账号 username 或关键词
+func (h *RadarServiceAPI) ImportTargets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	q := r.URL.Query()
+	strategy, err := services.ParseImportStrategy(q.Get("strategy"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	dryRun, _ := strconv.ParseBool(q.Get("dryRun"))
+	opts := services.ImportOptions{
+		Format:   services.ExportFormat(strings.ToLower(q.Get("format"))),
+		Strategy: strategy,
+		DryRun:   dryRun,
+	}
+
+	data, err := readImportBody(w, r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "读取导入文件失败: "+err.Error())
+		return
+	}
+	if len(data) == 0 {
+		response.Error(w, http.StatusBadRequest, "导入文件为空")
+		return
+	}
+
+	report, err := h.importer.ImportTargets(data, opts)
+	if services.IsRadarBudgetExhausted(err) {
+		response.Error(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(w, report)
+}
+
+// ExportTargets 导出监控目标（format 为 json 或 csv，默认 csv），可按 group 只导出一个分组
+func (h *RadarServiceAPI) ExportTargets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	format := services.ExportFormatCSV
+	if value := r.URL.Query().Get("format"); value != "" {
+		format = services.ExportFormat(strings.ToLower(value))
+		if format != services.ExportFormatCSV && format != services.ExportFormatJSON {
+			response.Error(w, http.StatusBadRequest, "只支持 json 和 csv 格式")
+			return
 		}
+	}
+	group := strings.TrimSpace(r.URL.Query().Get("group"))
+	streamExport(w, "radar_targets", format, func(out io.Writer) (int, error) {
+		return h.importer.ExportTargets(out, format, group)
 	})
+}
+
+// BulkUpdateTargets 按分组批量暂停、恢复或删除监控目标
+func (h *RadarServiceAPI) BulkUpdateTargets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	var req struct {
+		Group     string `json:"group"`
+		Operation string `json:"operation"` // pause、resume 或 delete
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+	req.Group = strings.TrimSpace(req.Group)
+	if req.Group == "" {
+		response.Error(w, http.StatusBadRequest, "分组不能为空")
+		return
+	}
+
+	var affected int64
+	var err error
+	switch req.Operation {
+	case "pause":
+		affected, err = h.repo.UpdateStatusByGroup(req.Group, database.RadarStatusPaused)
+	case "resume":
+		affected, err = h.repo.UpdateStatusByGroup(req.Group, database.RadarStatusActive)
+	case "delete":
+		affected, err = h.repo.DeleteByGroup(req.Group)
+	default:
+		response.Error(w, http.StatusBadRequest, "无效的操作，应为 pause、resume 或 delete")
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "批量操作失败")
+		return
+	}
+	response.Success(w, map[string]interface{}{"affected": affected})
+}
+
+// applyGroupDefaults 为未指定检测间隔的目标使用所在分组的默认间隔
+func (h *RadarServiceAPI) applyGroupDefaults(target *database.RadarTarget) {
+	name := strings.TrimSpace(target.Group)
+	if name == "" {
+		return
+	}
+	if group, err := h.repo.GetGroup(name); err == nil {
+		group.ApplyDefaults(target)
+	}
+}
+
+// GetGroups 获取所有分组及其设置，包括只出现在监控目标上、没有保存设置的分组
+func (h *RadarServiceAPI) GetGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	groups, err := h.repo.GetGroups()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "获取分组失败")
+		return
+	}
+	response.Success(w, groups)
+}
 
-	mux.HandleFunc("/api/v1/radar/targets/", func(w http.ResponseWriter, r *http.Request) {
-		// 处理 /api/v1/radar/targets/{id} 和 /api/v1/radar/targets/{id}/status 和 /logs
-		path := r.URL.Path
-		if strings.HasSuffix(path, "/status") && r.Method == http.MethodPut {
-			h.UpdateTargetStatus(w, r)
+// SaveGroup 保存分组设置，并把新的优先级应用到队列中该分组尚未完成的项目
+func (h *RadarServiceAPI) SaveGroup(w http.ResponseWriter, r *http.Request, name string) {
+	var group database.RadarGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+	group.Name = name
+	if err := group.Normalize(); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.repo.SaveGroup(&group); err != nil {
+		response.Error(w, http.StatusInternalServerError, "保存分组失败")
+		return
+	}
+	if _, err := database.NewQueueRepository().UpdateGroupPriority(group.Name, group.Priority); err != nil {
+		response.Error(w, http.StatusInternalServerError, "更新队列优先级失败")
+		return
+	}
+	saved, err := h.repo.GetGroup(group.Name)
+	if err != nil || saved == nil {
+		response.Error(w, http.StatusInternalServerError, "获取分组失败")
+		return
+	}
+	response.Success(w, saved)
+}
+
+// DeleteGroup 删除分组设置，分组内的目标保留分组名称，队列中尚未完成的项目恢复为默认优先级
+func (h *RadarServiceAPI) DeleteGroup(w http.ResponseWriter, r *http.Request, name string) {
+	deleted, err := h.repo.DeleteGroup(name)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "删除分组失败")
+		return
+	}
+	if !deleted {
+		response.Error(w, http.StatusNotFound, "分组没有保存的设置")
+		return
+	}
+	if _, err := database.NewQueueRepository().UpdateGroupPriority(name, 0); err != nil {
+		response.Error(w, http.StatusInternalServerError, "更新队列优先级失败")
+		return
+	}
+	response.Success(w, nil)
+}
+
+// handleGroup 处理 /radar/groups/{name}
+func (h *RadarServiceAPI) handleGroup(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	if name == "" {
+		response.Error(w, http.StatusBadRequest, "分组名称不能为空")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		group, err := h.repo.GetGroup(name)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "获取分组失败")
 			return
 		}
-		if strings.HasSuffix(path, "/logs") && r.Method == http.MethodGet {
-			h.GetRadarLogs(w, r)
+		if group == nil {
+			response.Error(w, http.StatusNotFound, "分组没有保存的设置")
 			return
 		}
+		response.Success(w, group)
+	case http.MethodPut:
+		h.SaveGroup(w, r, name)
+	case http.MethodDelete:
+		h.DeleteGroup(w, r, name)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+// GetKeywordHits 获取关键词目标最近搜到的视频
+func (h *RadarServiceAPI) GetKeywordHits(w http.ResponseWriter, r *http.Request, id string) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	hits, err := h.repo.GetKeywordHits(id, limit)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "获取关键词命中记录失败")
+		return
+	}
+	response.Success(w, hits)
+}
+
+// GetLiveSessions 获取所有监控账号的直播场次，live=true 时只返回正在直播的
+func (h *RadarServiceAPI) GetLiveSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	h.listLiveSessions(w, r, "")
+}
+
+// GetTargetLiveSessions 获取监控账号的直播场次
+func (h *RadarServiceAPI) GetTargetLiveSessions(w http.ResponseWriter, r *http.Request, id string) {
+	target, err := h.repo.GetByID(id)
+	if err != nil || target == nil {
+		response.Error(w, http.StatusNotFound, "监控目标不存在")
+		return
+	}
+	if target.IsKeyword() {
+		response.Success(w, []database.RadarLiveSession{})
+		return
+	}
+	h.listLiveSessions(w, r, target.Username)
+}
+
+func (h *RadarServiceAPI) listLiveSessions(w http.ResponseWriter, r *http.Request, username string) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	liveOnly, _ := strconv.ParseBool(q.Get("live"))
+	sessions, err := h.repo.GetLiveSessions(username, liveOnly, limit)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "获取直播记录失败")
+		return
+	}
+	response.Success(w, sessions)
+}
+
+// radarHistoryMaxPoints 账号资料快照一次最多返回的点数
+const radarHistoryMaxPoints = 5000
+
+// GetTargetHistory 获取监控账号资料快照的时间序列，可选 since/until（RFC3339 或 2006-01-02）和 limit
+func (h *RadarServiceAPI) GetTargetHistory(w http.ResponseWriter, r *http.Request, id string) {
+	target, err := h.repo.GetByID(id)
+	if err != nil || target == nil {
+		response.Error(w, http.StatusNotFound, "监控目标不存在")
+		return
+	}
+	q := r.URL.Query()
+	since, err := parseTimeParam(q.Get("since"), false)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "since 格式错误")
+		return
+	}
+	until, err := parseTimeParam(q.Get("until"), true)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "until 格式错误")
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > radarHistoryMaxPoints {
+		limit = radarHistoryMaxPoints
+	}
+
+	points := []database.RadarAccountSnapshot{}
+	if !target.IsKeyword() {
+		if points, err = h.repo.AccountHistory(target.Username, since, until, limit); err != nil {
+			response.Error(w, http.StatusInternalServerError, "获取账号历史数据失败")
+			return
+		}
+	}
+	response.Success(w, map[string]interface{}{
+		"target_id": target.ID,
+		"username":  target.Username,
+		"points":    points,
+	})
+}
+
+// CancelBackfill 取消并清除回填进度
+func (h *RadarServiceAPI) CancelBackfill(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.repo.UpdateBackfill(id, nil); err != nil {
+		response.Error(w, http.StatusInternalServerError, "取消回填失败")
+		return
+	}
+	response.Success(w, nil)
+}
+
+// newManualBackfill 创建手动发起的回填
+func newManualBackfill(maxPages int, since *time.Time) *database.RadarBackfill {
+	if maxPages < 0 {
+		maxPages = 0
+	}
+	return &database.RadarBackfill{
+		Status:   database.RadarBackfillPending,
+		Reason:   database.RadarBackfillManual,
+		MaxPages: maxPages,
+		Since:    since,
+	}
+}
+
+// GetFilter 获取全局过滤规则
+func (h *RadarServiceAPI) GetFilter(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.settings.GetRadarFilter()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "获取过滤规则失败")
+		return
+	}
+	if filter == nil {
+		filter = &database.RadarFilter{}
+	}
+	response.Success(w, filter)
+}
+
+// UpdateFilter 更新全局过滤规则，提交空对象表示清除
+func (h *RadarServiceAPI) UpdateFilter(w http.ResponseWriter, r *http.Request) {
+	var filter database.RadarFilter
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		response.Error(w, http.StatusBadRequest, "过滤规则无效: "+err.Error())
+		return
+	}
+	if err := h.settings.SetRadarFilter(&filter); err != nil {
+		response.Error(w, http.StatusInternalServerError, "保存过滤规则失败")
+		return
+	}
+	response.Success(w, filter)
+}
 
+// GetStatus 获取雷达整体状态：全局断路器和各健康状态的目标数
+func (h *RadarServiceAPI) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+	breaker, err := h.settings.GetRadarBreaker()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "获取断路器状态失败")
+		return
+	}
+	targets, err := h.repo.GetAll()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "获取监控目标失败")
+		return
+	}
+	health := map[string]int{
+		database.RadarHealthHealthy:   0,
+		database.RadarHealthDegraded:  0,
+		database.RadarHealthSuspended: 0,
+	}
+	for _, target := range targets {
+		health[target.Health]++
+	}
+	response.Success(w, map[string]interface{}{
+		"breaker":      breaker,
+		"breaker_open": breaker.IsOpen(time.Now()),
+		"health":       health,
+	})
+}
+
+// handleFilter 处理 /radar/filter
+func (h *RadarServiceAPI) handleFilter(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetFilter(w, r)
+	case http.MethodPut:
+		h.UpdateFilter(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+// handleTargets 处理 /radar/targets
+func (h *RadarServiceAPI) handleTargets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTargets(w, r)
+	case http.MethodPost:
+		h.AddTarget(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+// handleTarget 处理 /radar/targets/{id} 和 /radar/targets/{id}/status、/logs、/hits、/lives、/history、/backfill、/preview
+func (h *RadarServiceAPI) handleTarget(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	if strings.HasSuffix(path, "/status") && r.Method == http.MethodPut {
+		h.UpdateTargetStatus(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/logs") && r.Method == http.MethodGet {
+		h.GetRadarLogs(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/hits") && r.Method == http.MethodGet {
+		pathParts := strings.Split(path, "/")
+		h.GetKeywordHits(w, r, pathParts[len(pathParts)-2])
+		return
+	}
+	if strings.HasSuffix(path, "/history") && r.Method == http.MethodGet {
+		pathParts := strings.Split(path, "/")
+		h.GetTargetHistory(w, r, pathParts[len(pathParts)-2])
+		return
+	}
+	if strings.HasSuffix(path, "/lives") && r.Method == http.MethodGet {
+		pathParts := strings.Split(path, "/")
+		h.GetTargetLiveSessions(w, r, pathParts[len(pathParts)-2])
+		return
+	}
+	if strings.HasSuffix(path, "/preview") && r.Method == http.MethodPost {
+		pathParts := strings.Split(path, "/")
+		h.PreviewTarget(w, r, pathParts[len(pathParts)-2])
+		return
+	}
+	if strings.HasSuffix(path, "/backfill") {
+		pathParts := strings.Split(path, "/")
+		id := pathParts[len(pathParts)-2]
 		switch r.Method {
-		case http.MethodPut:
-			h.UpdateTarget(w, r)
+		case http.MethodPost:
+			h.StartBackfill(w, r, id)
 		case http.MethodDelete:
-			h.DeleteTarget(w, r)
+			h.CancelBackfill(w, r, id)
 		default:
 			response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
 		}
-	})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		h.UpdateTarget(w, r)
+	case http.MethodDelete:
+		h.DeleteTarget(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+// RegisterRoutes 注册雷达相关的 API 路由
+func (h *RadarServiceAPI) RegisterRoutes(mux *http.ServeMux) {
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		mux.HandleFunc(prefix+"/radar/status", h.GetStatus)
+		mux.HandleFunc(prefix+"/radar/filter", h.handleFilter)
+		mux.HandleFunc(prefix+"/radar/targets", h.handleTargets)
+		mux.HandleFunc(prefix+"/radar/targets/import", h.ImportTargets)
+		mux.HandleFunc(prefix+"/radar/targets/export", h.ExportTargets)
+		mux.HandleFunc(prefix+"/radar/targets/bulk", h.BulkUpdateTargets)
+		mux.HandleFunc(prefix+"/radar/targets/preview", h.PreviewNewTarget)
+		mux.HandleFunc(prefix+"/radar/targets/", h.handleTarget)
+		mux.HandleFunc(prefix+"/radar/groups", h.GetGroups)
+		mux.HandleFunc(prefix+"/radar/lives", h.GetLiveSessions)
+		mux.HandleFunc(prefix+"/radar/groups/", h.handleGroup)
+	}
 }
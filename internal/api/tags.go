@@ -0,0 +1,283 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"wx_channel/internal/database"
+	"wx_channel/internal/response"
+	"wx_channel/internal/services"
+)
+
+// TagAPI 处理标签和合集相关的 API
+type TagAPI struct {
+	tags        *services.TagService
+	collections *services.CollectionService
+}
+
+// NewTagAPI 创建标签和合集 API 处理器
+func NewTagAPI() *TagAPI {
+	return &TagAPI{
+		tags:        services.NewTagService(),
+		collections: services.NewCollectionService(),
+	}
+}
+
+// recordIDsRequest 批量操作的请求体，type 为 browse（默认）或 download
+type recordIDsRequest struct {
+	IDs  []string `json:"ids"`
+	Type string   `json:"type"`
+	Tags []string `json:"tags"`
+}
+
+// pathParts 返回 prefix 之后的路径段，兼容 /api/v1 前缀
+func pathParts(path, prefix string) []string {
+	path = strings.TrimPrefix(path, "/api/v1")
+	path = strings.TrimPrefix(path, "/api")
+	path = strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	if path == "" {
+		return nil
+	}
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if unescaped, err := url.PathUnescape(p); err == nil {
+			parts[i] = unescaped
+		}
+	}
+	return parts
+}
+
+// isUniqueViolation 判断是否为唯一键冲突
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// ListTags 获取标签列表，可按 videoId 查询单个视频的标签
+func (h *TagAPI) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tags.List(r.URL.Query().Get("videoId"))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, tags)
+}
+
+// SaveTag 创建（id 为空）或更新标签
+func (h *TagAPI) SaveTag(w http.ResponseWriter, r *http.Request, id string) {
+	var tag database.Tag
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+
+	var err error
+	if id == "" {
+		tag.ID = ""
+		err = h.tags.Create(&tag)
+	} else {
+		tag.ID = id
+		err = h.tags.Update(&tag)
+	}
+	if isUniqueViolation(err) {
+		response.Error(w, http.StatusConflict, "标签名称已存在")
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(w, tag)
+}
+
+// BulkTag 批量添加（apply）或移除（remove）标签
+func (h *TagAPI) BulkTag(w http.ResponseWriter, r *http.Request, remove bool) {
+	var req recordIDsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+	if len(req.IDs) == 0 || len(req.Tags) == 0 {
+		response.Error(w, http.StatusBadRequest, "ids 和 tags 不能为空")
+		return
+	}
+
+	var n int64
+	var err error
+	if remove {
+		n, err = h.tags.Untag(req.Type, req.IDs, req.Tags)
+	} else {
+		n, err = h.tags.Tag(req.Type, req.IDs, req.Tags)
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, map[string]interface{}{"affected": n})
+}
+
+// ListCollections 获取合集列表
+func (h *TagAPI) ListCollections(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.collections.List()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, collections)
+}
+
+// GetCollection 获取合集详情及条目
+func (h *TagAPI) GetCollection(w http.ResponseWriter, r *http.Request, id string) {
+	collection, items, err := h.collections.Get(id)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if collection == nil {
+		response.ErrorWithStatus(w, http.StatusNotFound, http.StatusNotFound, "合集不存在")
+		return
+	}
+	response.Success(w, map[string]interface{}{
+		"collection": collection,
+		"items":      items,
+	})
+}
+
+// SaveCollection 创建（id 为空）或更新合集
+func (h *TagAPI) SaveCollection(w http.ResponseWriter, r *http.Request, id string) {
+	var c database.Collection
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+
+	var err error
+	if id == "" {
+		c.ID = ""
+		err = h.collections.Create(&c)
+	} else {
+		c.ID = id
+		err = h.collections.Update(&c)
+	}
+	if isUniqueViolation(err) {
+		response.Error(w, http.StatusConflict, "合集名称已存在")
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(w, c)
+}
+
+// UpdateCollectionItems 向合集添加或移除记录
+func (h *TagAPI) UpdateCollectionItems(w http.ResponseWriter, r *http.Request, id string, remove bool) {
+	var req recordIDsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+		return
+	}
+	if len(req.IDs) == 0 {
+		response.Error(w, http.StatusBadRequest, "ids 不能为空")
+		return
+	}
+
+	var n int64
+	var err error
+	if remove {
+		n, err = h.collections.RemoveItems(id, req.Type, req.IDs)
+	} else {
+		n, err = h.collections.AddItems(id, req.Type, req.IDs)
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, map[string]interface{}{"affected": n})
+}
+
+// MaterializeCollection 把合集导出为下载目录中的硬链接文件夹或 M3U 播放列表
+func (h *TagAPI) MaterializeCollection(w http.ResponseWriter, r *http.Request, id string) {
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "请求参数解析失败")
+			return
+		}
+	}
+	if mode := r.URL.Query().Get("mode"); mode != "" {
+		req.Mode = mode
+	}
+
+	result, err := h.collections.Materialize(id, req.Mode)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, result)
+}
+
+func (h *TagAPI) handleTags(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r.URL.Path, "/tags")
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		h.ListTags(w, r)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		h.SaveTag(w, r, "")
+	case len(parts) == 1 && parts[0] == "apply" && r.Method == http.MethodPost:
+		h.BulkTag(w, r, false)
+	case len(parts) == 1 && parts[0] == "remove" && r.Method == http.MethodPost:
+		h.BulkTag(w, r, true)
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		h.SaveTag(w, r, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		if err := h.tags.Delete(parts[0]); err != nil {
+			response.ErrorWithStatus(w, http.StatusNotFound, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Success(w, nil)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+func (h *TagAPI) handleCollections(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r.URL.Path, "/collections")
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		h.ListCollections(w, r)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		h.SaveCollection(w, r, "")
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.GetCollection(w, r, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		h.SaveCollection(w, r, parts[0])
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		if err := h.collections.Delete(parts[0]); err != nil {
+			response.ErrorWithStatus(w, http.StatusNotFound, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Success(w, nil)
+	case len(parts) == 2 && parts[1] == "items" && r.Method == http.MethodPost:
+		h.UpdateCollectionItems(w, r, parts[0], false)
+	case len(parts) == 2 && parts[1] == "items" && r.Method == http.MethodDelete:
+		h.UpdateCollectionItems(w, r, parts[0], true)
+	case len(parts) == 2 && parts[1] == "materialize" && r.Method == http.MethodPost:
+		h.MaterializeCollection(w, r, parts[0])
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+// RegisterRoutes 注册标签和合集相关的 API 路由
+func (h *TagAPI) RegisterRoutes(mux *http.ServeMux) {
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		mux.HandleFunc(prefix+"/tags", h.handleTags)
+		mux.HandleFunc(prefix+"/tags/", h.handleTags)
+		mux.HandleFunc(prefix+"/collections", h.handleCollections)
+		mux.HandleFunc(prefix+"/collections/", h.handleCollections)
+	}
+}
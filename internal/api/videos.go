@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"wx_channel/internal/response"
+	"wx_channel/internal/services"
+)
+
+// VideoAPI 处理视频与作者详情 API
+type VideoAPI struct {
+	service *services.VideoService
+}
+
+// NewVideoAPI 创建视频与作者详情 API 处理器
+func NewVideoAPI() *VideoAPI {
+	return &VideoAPI{service: services.NewVideoService()}
+}
+
+// GetVideo 获取视频详情：元数据、作者、浏览/下载/队列记录、标签、合集与评论数
+func (h *VideoAPI) GetVideo(w http.ResponseWriter, r *http.Request, id string) {
+	detail, err := h.service.VideoDetail(id)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if detail == nil {
+		response.ErrorWithStatus(w, http.StatusNotFound, http.StatusNotFound, "视频不存在")
+		return
+	}
+	response.Success(w, detail)
+}
+
+// GetStatsHistory 获取视频互动数据的时间序列，可选 since/until（RFC3339 或 2006-01-02）和 limit
+func (h *VideoAPI) GetStatsHistory(w http.ResponseWriter, r *http.Request, id string) {
+	q := r.URL.Query()
+	since, err := parseTimeParam(q.Get("since"), false)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "since 格式错误")
+		return
+	}
+	until, err := parseTimeParam(q.Get("until"), true)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "until 格式错误")
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	points, err := h.service.StatsHistory(id, since, until, limit)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Success(w, map[string]interface{}{
+		"videoId": id,
+		"points":  points,
+	})
+}
+
+// SetStatsTracked 开启（POST）或关闭（DELETE）视频的互动数据定时重新拉取
+func (h *VideoAPI) SetStatsTracked(w http.ResponseWriter, r *http.Request, id string, tracked bool) {
+	if err := h.service.SetStatsTracked(id, tracked); err != nil {
+		response.ErrorWithStatus(w, http.StatusNotFound, http.StatusNotFound, err.Error())
+		return
+	}
+	response.Success(w, map[string]interface{}{"videoId": id, "statsTracked": tracked})
+}
+
+// parseTimeParam 解析时间参数；日期格式作为结束时间时取当天最后一刻
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// GetAuthor 获取作者详情：资料、统计、视频列表与雷达监控状态
+func (h *VideoAPI) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r.URL.Path, "/authors")
+	if len(parts) != 1 {
+		response.Error(w, http.StatusBadRequest, "缺少作者 ID")
+		return
+	}
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+		return
+	}
+
+	detail, err := h.service.AuthorDetail(parts[0])
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if detail == nil {
+		response.ErrorWithStatus(w, http.StatusNotFound, http.StatusNotFound, "作者不存在")
+		return
+	}
+	response.Success(w, detail)
+}
+
+func (h *VideoAPI) handleVideos(w http.ResponseWriter, r *http.Request) {
+	parts := pathParts(r.URL.Path, "/videos")
+	switch {
+	case len(parts) == 0:
+		response.Error(w, http.StatusBadRequest, "缺少视频 ID")
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.GetVideo(w, r, parts[0])
+	case len(parts) == 3 && parts[1] == "stats" && parts[2] == "history" && r.Method == http.MethodGet:
+		h.GetStatsHistory(w, r, parts[0])
+	case len(parts) == 3 && parts[1] == "stats" && parts[2] == "track" && r.Method == http.MethodPost:
+		h.SetStatsTracked(w, r, parts[0], true)
+	case len(parts) == 3 && parts[1] == "stats" && parts[2] == "track" && r.Method == http.MethodDelete:
+		h.SetStatsTracked(w, r, parts[0], false)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "不允许的请求方法")
+	}
+}
+
+// RegisterRoutes 注册视频与作者详情路由
+func (h *VideoAPI) RegisterRoutes(mux *http.ServeMux) {
+	for _, prefix := range []string{"/api", "/api/v1"} {
+		mux.HandleFunc(prefix+"/videos/", h.handleVideos)
+		mux.HandleFunc(prefix+"/authors/", h.GetAuthor)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -29,7 +30,6 @@ import (
 	"wx_channel/internal/storage"
 	"wx_channel/internal/utils"
 	"wx_channel/internal/websocket"
-	"wx_channel/pkg/certificate"
 	"wx_channel/pkg/proxy"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -60,9 +60,11 @@ type App struct {
 
 	// 服务
 	WSHub          *websocket.Hub
+	WSAPIHandler   *websocket.Handler // /ws/api 处理器
 	SearchService  *api.SearchService
-	RadarService   *services.RadarService  // 自动轮询雷达
-	GopeedService  *services.GopeedService // Add GopeedService
+	RadarService   *services.RadarService       // 自动轮询雷达
+	StatsRepoll    *services.StatsRepollService // 互动数据定时重新拉取
+	GopeedService  *services.GopeedService      // Add GopeedService
 	CloudConnector *cloud.Connector
 
 	// 路由器
@@ -71,6 +73,8 @@ type App struct {
 	// 拦截器
 	requestInterceptors  []router.Interceptor
 	responseInterceptors []router.Interceptor
+
+	systemProxySet atomic.Bool // 本次运行是否修改了系统代理，退出时据此恢复
 }
 
 // 全局变量，用于将 SunnyNet C 风格回调桥接到 App 方法
@@ -92,7 +96,8 @@ func NewApp(cfgParam *config.Config) *App {
 	app.printTitle()
 	utils.LogConfigLoad("config.yaml", true)
 	if app.Cfg.LogFile != "" {
-		_ = utils.InitLoggerWithRotation(utils.INFO, app.Cfg.LogFile, app.Cfg.MaxLogSizeMB)
+		level, _ := utils.ParseLogLevel(app.Cfg.LogLevel)
+		_ = utils.InitLoggerWithRotation(level, app.Cfg.LogFile, app.Cfg.MaxLogSizeMB)
 		app.LogInitMsg = fmt.Sprintf("日志已初始化: %s (最大 %dMB)", app.Cfg.LogFile, app.Cfg.MaxLogSizeMB)
 	}
 
@@ -123,6 +128,10 @@ func (app *App) initDownloadRecords() error {
 		return fmt.Errorf("初始化数据库失败: %v", err)
 	}
 
+	// 叠加控制台保存在数据库中的设置（优先级最高）
+	config.SetDatabaseLoader(database.NewSettingsRepository())
+	app.Cfg = config.Reload()
+
 	// Initialize Gopeed Service
 	app.GopeedService = services.NewGopeedService(downloadsDir)
 	// app.GopeedService.Start() // Removed
@@ -137,6 +146,15 @@ func (app *App) Run() {
 	// 确保端口设置正确
 	app.Sunny.SetPort(app.Port)
 
+	// 上次运行异常退出时系统代理可能仍指向本程序，先恢复原有设置
+	if os_env != "windows" {
+		if recovered, err := proxy.Recover(); err != nil {
+			utils.Warn("恢复上次遗留的系统代理设置失败: %v", err)
+		} else if recovered {
+			utils.Info("已恢复上次异常退出前的系统代理设置")
+		}
+	}
+
 	done := make(chan struct{})
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
@@ -145,12 +163,11 @@ func (app *App) Run() {
 		color.Red("\n正在关闭服务...%v\n\n", sig)
 		utils.LogSystemShutdown(fmt.Sprintf("收到信号: %v", sig))
 		database.Close()
-		if os_env == "darwin" {
-			proxy.DisableProxyInMacOS(proxy.ProxySettings{
-				Device:   "",
-				Hostname: "127.0.0.1",
-				Port:     strconv.Itoa(app.Port),
-			})
+		// macOS 与旧版本一致，退出时总是关闭系统代理；其他平台只恢复本次修改过的设置
+		if app.systemProxySet.Load() || os_env == "darwin" {
+			if err := app.systemProxy().Disable(); err != nil {
+				utils.Warn("恢复系统代理失败: %v", err)
+			}
 		}
 		close(done)
 	}()
@@ -195,10 +212,12 @@ func (app *App) Run() {
 	queueService := services.NewQueueService()
 	radarRepo := database.NewRadarRepository()
 	app.RadarService = services.NewRadarService(radarRepo, queueService, app.WSHub)
+	app.RadarService.OnLiveEvent(handlers.GetWebSocketHub().BroadcastRadarLive)
+	app.StatsRepoll = services.NewStatsRepollService(app.WSHub)
 	app.ConsoleAPIHandler = handlers.NewConsoleAPIHandler(app.Cfg, app.WSHub, app.RadarService)
 
 	// 初始化新的 API 路由器
-	app.APIRouter = router.NewAPIRouter(app.Cfg, app.WSHub, app.Sunny)
+	app.APIRouter = router.NewAPIRouter(app.Cfg, app.WSHub, app.Sunny, app.RadarService)
 
 	// 初始化静态文件处理器
 	app.StaticFileHandler = handlers.NewStaticFileHandler()
@@ -247,36 +266,8 @@ func (app *App) Run() {
 		app.ScriptHandler,
 	}
 
-	existing, err1 := certificate.CheckCertificate("SunnyNet")
-	if err1 != nil {
-		utils.HandleError(err1, "检查证书")
-		utils.Warn("程序将继续运行，但HTTPS功能可能受限...")
-		existing = false
-	} else if !existing {
-		utils.Info("正在安装证书...")
-		err := certificate.InstallCertificate(assets.CertData)
-		time.Sleep(app.Cfg.CertInstallDelay)
-		if err != nil {
-			utils.HandleError(err, "证书安装")
-			utils.Warn("如需完整功能，请手动安装证书或以管理员身份运行程序。")
-
-			if app.FileManager != nil {
-				downloadsDir, err := utils.ResolveDownloadDir(app.Cfg.DownloadsDir)
-				if err == nil {
-					certPath := filepath.Join(downloadsDir, app.Cfg.CertFile)
-					if err := utils.EnsureDir(downloadsDir); err == nil {
-						if err := os.WriteFile(certPath, assets.CertData, 0644); err == nil {
-							utils.Info("证书文件已保存到: %s", certPath)
-						}
-					}
-				}
-			}
-		} else {
-			utils.Info("✓ 证书安装成功！")
-		}
-	} else {
-		utils.Info("✓ 证书已存在，无需重新安装。")
-	}
+	// 使用本机生成的根证书，首次运行时生成并安装
+	app.setupCertificate()
 
 	// 1. 立即启动核心驱动
 	sunnyErr := app.Sunny.Start().Error
@@ -287,6 +278,19 @@ func (app *App) Run() {
 	}
 	app.Sunny.SetGoCallback(GlobalHttpCallback, nil, nil, nil)
 
+	// 非 Windows 平台按配置 system_proxy 通过系统代理接管流量，退出时自动恢复
+	if os_env != "windows" {
+		if !app.Cfg.SystemProxy {
+			utils.Info("未自动设置系统代理（system_proxy: false），请手动将 HTTP/HTTPS 代理设置为 127.0.0.1:%d", app.Port)
+		} else if err := app.systemProxy().Enable(); err != nil {
+			utils.Warn("设置系统代理失败: %v", err)
+			utils.Warn("请手动将 HTTP/HTTPS 代理设置为 127.0.0.1:%d", app.Port)
+		} else {
+			app.systemProxySet.Store(true)
+			utils.Info("✓ 系统代理已指向 127.0.0.1:%d", app.Port)
+		}
+	}
+
 	// 2. 立即渲染界面面板 (不再受网络连接阻塞)
 	utils.PrintSeparator()
 	color.Blue("📡 服务状态信息")
@@ -306,6 +310,7 @@ func (app *App) Run() {
 	utils.Info("✓ WebSocket Hub 已启动")
 
 	wsPort := app.Port + 1
+	app.WSAPIHandler = websocket.NewHandler(app.WSHub, app.Cfg.AllowedOrigins, app.Cfg.SecretToken)
 	go app.startWebSocketServer(wsPort)
 
 	// 启动 Prometheus 监控服务器（如果启用）
@@ -322,6 +327,11 @@ func (app *App) Run() {
 		utils.Info("云端管理功能已禁用 (cloud_enabled: false)")
 	}
 
+	// 配置热加载：监听配置文件，变更后实时应用安全项，其余项标记为待重启
+	config.MarkRunning(app.Cfg)
+	config.OnChange(app.applyConfigChange)
+	config.WatchFile()
+
 	utils.Info("🔍 请打开需要下载的视频号页面进行下载")
 
 	// 启动对标雷达服务（默认关闭，按配置启用）
@@ -331,6 +341,10 @@ func (app *App) Run() {
 	} else {
 		utils.Info("雷达服务未启用 (radar_enabled: false)")
 	}
+	if app.Cfg.StatsRepollEnabled {
+		app.StatsRepoll.Start()
+		utils.Info("✓ 互动数据重新拉取已启用，间隔 %s", app.Cfg.StatsRepollInterval)
+	}
 
 	// 4. 【异步】处理 Windows 进程注入和连通性检查 (不阻塞主线程)
 	go func() {
@@ -374,6 +388,57 @@ func (app *App) Run() {
 	if app.RadarService != nil {
 		app.RadarService.Stop()
 	}
+	if app.StatsRepoll != nil {
+		app.StatsRepoll.Stop()
+	}
+}
+
+// applyConfigChange 将热加载的配置应用到启动时复制了配置值的组件
+// 处理器中通过 config.Get() 动态读取的配置项无需在此处理
+func (app *App) applyConfigChange(old, cfg *config.Config, changed []string) {
+	app.Cfg = cfg
+
+	for _, key := range changed {
+		switch key {
+		case "log_level":
+			if level, ok := utils.ParseLogLevel(cfg.LogLevel); ok {
+				utils.GetLogger().SetLevel(level)
+			}
+		case "allowed_origins":
+			if app.APIRouter != nil {
+				app.APIRouter.SetAllowedOrigins(cfg.AllowedOrigins)
+			}
+			if app.WSAPIHandler != nil {
+				app.WSAPIHandler.SetAllowedOrigins(cfg.AllowedOrigins)
+			}
+		case "upload_chunk_concurrency", "upload_merge_concurrency":
+			if app.UploadHandler != nil {
+				app.UploadHandler.SetConcurrency(cfg.UploadChunkConcurrency, cfg.UploadMergeConcurrency)
+			}
+		case "compression_enabled", "compression_threshold":
+			if app.CloudConnector != nil {
+				app.CloudConnector.SetCompression(cfg.CompressionEnabled, cfg.CompressionThreshold)
+			}
+		case "radar_enabled":
+			if app.RadarService == nil {
+				continue
+			}
+			if cfg.RadarEnabled {
+				app.RadarService.Start()
+			} else {
+				app.RadarService.Stop()
+			}
+		case "stats_repoll_enabled":
+			if app.StatsRepoll == nil {
+				continue
+			}
+			if cfg.StatsRepollEnabled {
+				app.StatsRepoll.Start()
+			} else {
+				app.StatsRepoll.Stop()
+			}
+		}
+	}
 }
 
 // GlobalHttpCallback 桥接到单例 app 实例
@@ -490,8 +555,7 @@ func (app *App) startWebSocketServer(wsPort int) {
 		mux.Handle("/api/", app.APIRouter)
 	}
 
-	wsHandler := websocket.NewHandler(app.WSHub, app.Cfg.AllowedOrigins, app.Cfg.SecretToken)
-	mux.HandleFunc("/ws/api", wsHandler.ServeHTTP)
+	mux.HandleFunc("/ws/api", app.WSAPIHandler.ServeHTTP)
 
 	mux.HandleFunc("/ws/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -561,3 +625,11 @@ func (app *App) configureLoadBalancer() {
 
 	app.WSHub.SetSelector(selector)
 }
+
+// systemProxy 指向本程序代理端口的系统代理设置
+func (app *App) systemProxy() proxy.ProxySettings {
+	return proxy.ProxySettings{
+		Hostname: "127.0.0.1",
+		Port:     strconv.Itoa(app.Port),
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/qtgolang/SunnyNet/Api"
+
+	"wx_channel/internal/utils"
+	"wx_channel/pkg/certificate"
+)
+
+// setupCertificate 加载（首次运行时生成）本机根证书，交给 SunnyNet 签发站点证书，并确保已安装到系统
+func (app *App) setupCertificate() {
+	caDir := certificate.DefaultCADir()
+	ca, created, err := certificate.LoadOrCreateCA(caDir)
+	if err != nil {
+		utils.HandleError(err, "加载根证书")
+		utils.Warn("程序将继续运行，但HTTPS功能可能受限...")
+		return
+	}
+	if created {
+		utils.Info("✓ 已生成本机专用根证书: %s", certificate.CertPath(caDir))
+	}
+	utils.Info("根证书指纹 (SHA-256): %s", ca.Fingerprint())
+
+	if err := applyCA(app, caDir); err != nil {
+		utils.HandleError(err, "加载根证书到代理核心")
+	}
+
+	// 旧版本安装的是所有用户共用的 SunnyNet 根证书，私钥已公开，必须移除
+	if legacy, _ := certificate.CheckCertificate(certificate.LegacyCertName); legacy {
+		if err := certificate.RemoveCertificate(certificate.LegacyCertName); err != nil {
+			utils.Warn("移除旧版公共根证书 %s 失败，请手动删除: %v", certificate.LegacyCertName, err)
+		} else {
+			utils.Info("✓ 已移除旧版公共根证书 %s", certificate.LegacyCertName)
+		}
+	}
+
+	existing, err := certificate.CheckCertificate(ca.Name())
+	if err != nil {
+		utils.HandleError(err, "检查证书")
+		utils.Warn("程序将继续运行，但HTTPS功能可能受限...")
+		return
+	}
+	if existing {
+		utils.Info("✓ 证书已存在，无需重新安装。")
+		return
+	}
+
+	utils.Info("正在安装证书...")
+	err = certificate.InstallCertificate(ca.CertPEM)
+	time.Sleep(app.Cfg.CertInstallDelay)
+	if err != nil {
+		utils.HandleError(err, "证书安装")
+		utils.Warn("如需完整功能，请手动安装证书或以管理员身份运行程序。")
+
+		if app.FileManager != nil {
+			downloadsDir, err := utils.ResolveDownloadDir(app.Cfg.DownloadsDir)
+			if err == nil {
+				certPath := filepath.Join(downloadsDir, app.Cfg.CertFile)
+				if err := utils.EnsureDir(downloadsDir); err == nil {
+					if err := os.WriteFile(certPath, ca.CertPEM, 0644); err == nil {
+						utils.Info("证书文件已保存到: %s", certPath)
+					}
+				}
+			}
+		}
+		return
+	}
+	utils.Info("✓ 证书安装成功！")
+}
+
+// applyCA 通过 SunnyNet 证书管理器加载根证书和私钥，替换其内置的默认证书
+func applyCA(app *App, caDir string) error {
+	manager := Api.CreateCertificate()
+	if !Api.LoadX509KeyPair(manager, certificate.CertPath(caDir), certificate.KeyPath(caDir)) {
+		Api.RemoveCertificate(manager)
+		return fmt.Errorf("SunnyNet 无法加载根证书: %s", certificate.CertPath(caDir))
+	}
+	return app.Sunny.SetCert(manager).Error
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"wx_channel/internal/config"
@@ -45,6 +46,10 @@ type Connector struct {
 
 	// 同步推送器
 	syncPusher *SyncPusher
+
+	// 压缩设置（支持热更新）
+	compressionEnabled   atomic.Bool
+	compressionThreshold atomic.Int64
 }
 
 // NewConnector 创建云端连接器
@@ -81,10 +86,17 @@ func NewConnector(cfg *config.Config, localHub *hubws.Hub) *Connector {
 		}
 		c.clientID = fmt.Sprintf("%s-%d", hostname, time.Now().Unix()%10000)
 	}
+	c.SetCompression(cfg.CompressionEnabled, cfg.CompressionThreshold)
 
 	return c
 }
 
+// SetCompression 热更新消息压缩开关与阈值
+func (c *Connector) SetCompression(enabled bool, threshold int) {
+	c.compressionEnabled.Store(enabled)
+	c.compressionThreshold.Store(int64(threshold))
+}
+
 type localCapabilitySummary struct {
 	PagePath            string
 	Href                string
@@ -488,7 +500,7 @@ func (c *Connector) send(msg CloudMessage) error {
 	messageType := websocket.MessageText
 
 	// 2. 如果启用压缩且数据大于阈值，则压缩
-	if c.cfg.CompressionEnabled && originalSize > c.cfg.CompressionThreshold {
+	if c.compressionEnabled.Load() && int64(originalSize) > c.compressionThreshold.Load() {
 		compressed, err := c.compressData(data)
 		if err == nil && len(compressed) < originalSize {
 			// 压缩成功且有效果
@@ -8,10 +8,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"wx_channel/internal/utils"
 	"wx_channel/internal/version"
+	"wx_channel/pkg/certificate"
 
 	"github.com/spf13/viper"
 )
@@ -57,6 +59,7 @@ type Config struct {
 	// 日志配置
 	LogFile      string `mapstructure:"log_file"`
 	MaxLogSizeMB int    `mapstructure:"max_log_size_mb"`
+	LogLevel     string `mapstructure:"log_level"` // debug, info, warn, error
 
 	// 保存功能开关
 	SavePageSnapshot bool `mapstructure:"save_page_snapshot"`
@@ -86,6 +89,31 @@ type Config struct {
 
 	// 功能开关
 	RadarEnabled bool `mapstructure:"radar_enabled"`
+
+	// 雷达调度：错开检测时间，避免集中请求被微信拒绝
+	RadarJitter       time.Duration `mapstructure:"radar_jitter"`        // 每次计算下次检测时间时附加的随机延迟上限
+	RadarQuietHours   string        `mapstructure:"radar_quiet_hours"`   // 免打扰时段（HH:MM-HH:MM），期间不请求 feed_list
+	RadarHourlyBudget int           `mapstructure:"radar_hourly_budget"` // 每小时最多调用 feed_list 的次数，0 表示不限
+
+	// 雷达断路器：多个目标接连失败时暂停所有请求
+	RadarBreakerThreshold int           `mapstructure:"radar_breaker_threshold"` // 接连失败的目标数达到该值时打开，0 表示不启用
+	RadarBreakerCooldown  time.Duration `mapstructure:"radar_breaker_cooldown"`  // 首次打开的冷却时间，连续打开时翻倍
+
+	// 雷达回填：沿 feed_list 分页向前拉取历史视频
+	RadarBackfillMaxPages  int           `mapstructure:"radar_backfill_max_pages"`  // 单次回填的默认页数上限
+	RadarBackfillPageDelay time.Duration `mapstructure:"radar_backfill_page_delay"` // 两页之间的间隔
+
+	// 雷达直播检测：监控账号开播时记录场次并通知
+	RadarLiveSearch   bool   `mapstructure:"radar_live_search"`   // 视频列表没有直播状态时按昵称搜索直播确认，计入每小时请求额度
+	RadarLiveWebhook  string `mapstructure:"radar_live_webhook"`  // 开播和下播时 POST 通知的地址，为空表示不通知
+	RadarLiveRecorder string `mapstructure:"radar_live_recorder"` // 开播时执行的录制命令，可用 {url}、{file}、{author}，为空表示不录制
+
+	// 非 Windows 平台启动时是否把系统代理指向本程序（退出时恢复原有设置）
+	SystemProxy bool `mapstructure:"system_proxy"`
+
+	// 互动数据重新拉取：定时通过 feed_profile 刷新被跟踪视频的点赞/评论等数据
+	StatsRepollEnabled  bool          `mapstructure:"stats_repoll_enabled"`
+	StatsRepollInterval time.Duration `mapstructure:"stats_repoll_interval"`
 }
 
 // HubSyncConfig Hub同步配置
@@ -96,7 +124,13 @@ type HubSyncConfig struct {
 	PushBatchSize int           `mapstructure:"push_batch_size"` // 推送批量大小
 }
 
-var globalConfig *Config
+var (
+	globalConfig *Config
+	globalMu     sync.RWMutex
+)
+
+// decodeErr 记录最近一次加载时的解码错误（如端口写成了字符串），供 Check 使用
+var decodeErr error
 
 // DatabaseConfigLoader 数据库配置加载器接口
 type DatabaseConfigLoader interface {
@@ -116,16 +150,24 @@ func SetDatabaseLoader(loader DatabaseConfigLoader) {
 // Load 加载配置
 // 优先级：数据库配置 > 环境变量 > 配置文件 > 默认值
 func Load() *Config {
+	globalMu.Lock()
+	defer globalMu.Unlock()
 	if globalConfig == nil {
 		globalConfig = loadConfig()
 	}
 	return globalConfig
 }
 
-// Reload 重新加载配置
+// Reload 重新加载配置，并通知 OnChange 订阅者
 func Reload() *Config {
+	globalMu.Lock()
+	old := globalConfig
 	globalConfig = loadConfig()
-	return globalConfig
+	cfg := globalConfig
+	globalMu.Unlock()
+
+	notifyChange(old, cfg)
+	return cfg
 }
 
 // loadConfig 执行实际的配置加载逻辑
@@ -135,6 +177,7 @@ func loadConfig() *Config {
 
 	// 配置环境变量自动加载
 	viper.SetEnvPrefix("WX_CHANNEL")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_")) // hub_sync.enabled -> WX_CHANNEL_HUB_SYNC_ENABLED
 	viper.AutomaticEnv()
 	// 替换环境变量中的点号，但这通常用于嵌套结构，这里是扁平的
 	// 如果需要支持 WX_CHANNEL_DOWNLOAD_DIR 映射到 download_dir，
@@ -173,8 +216,9 @@ func loadConfig() *Config {
 	// 这里我们先 Unmarshal 到 struct
 
 	config := &Config{}
-	if err := viper.Unmarshal(config); err != nil {
-		fmt.Printf("Unable to decode into struct: %v\n", err)
+	decodeErr = viper.Unmarshal(config)
+	if decodeErr != nil {
+		fmt.Printf("Unable to decode into struct: %v\n", decodeErr)
 	}
 
 	// 数据库加载覆盖（保持最高优先级）
@@ -199,7 +243,7 @@ func setDefaults() {
 	viper.SetDefault("version", version.Current)
 	viper.SetDefault("download_dir", "downloads")
 	viper.SetDefault("records_file", "download_records.csv")
-	viper.SetDefault("cert_file", "SunnyRoot.cer")
+	viper.SetDefault("cert_file", certificate.ExportFileName)
 
 	viper.SetDefault("max_retries", 3)
 	viper.SetDefault("chunk_size", 2<<20)       // 2MB
@@ -221,6 +265,7 @@ func setDefaults() {
 
 	viper.SetDefault("log_file", "logs/wx_channel.log")
 	viper.SetDefault("max_log_size_mb", 5)
+	viper.SetDefault("log_level", "info")
 
 	viper.SetDefault("save_page_snapshot", false)
 	viper.SetDefault("save_search_data", false)
@@ -248,6 +293,19 @@ func setDefaults() {
 
 	// 功能默认值
 	viper.SetDefault("radar_enabled", false)
+	viper.SetDefault("radar_jitter", 2*time.Minute)
+	viper.SetDefault("radar_quiet_hours", "")
+	viper.SetDefault("radar_hourly_budget", 0)
+	viper.SetDefault("radar_breaker_threshold", 3)
+	viper.SetDefault("radar_breaker_cooldown", 30*time.Minute)
+	viper.SetDefault("radar_backfill_max_pages", 20)
+	viper.SetDefault("radar_backfill_page_delay", 5*time.Second)
+	viper.SetDefault("radar_live_search", false)
+	viper.SetDefault("radar_live_webhook", "")
+	viper.SetDefault("radar_live_recorder", "")
+	viper.SetDefault("system_proxy", false)
+	viper.SetDefault("stats_repoll_enabled", false)
+	viper.SetDefault("stats_repoll_interval", 6*time.Hour)
 }
 
 // GetMachineID 获取或生成唯一的机器 ID (稳定硬件特征码)
@@ -417,9 +475,11 @@ func generateHardwareIDLegacy() string {
 // 注意：这部分逻辑仍然需要手动处理，因为 viper 不支持直接从自定义 DB 接口加载覆盖
 // 除非我们实现一个 viper 的 remote provider
 func loadFromDatabase(config *Config) {
+	resetDatabaseSources()
 	if dbLoader == nil {
 		return
 	}
+	defer recordDatabaseSources()
 
 	// 下载目录
 	if val, err := dbLoader.Get("download_dir"); err == nil && val != "" {
@@ -491,10 +551,13 @@ func loadFromDatabase(config *Config) {
 
 // Get 获取全局配置
 func Get() *Config {
-	if globalConfig == nil {
+	globalMu.RLock()
+	cfg := globalConfig
+	globalMu.RUnlock()
+	if cfg == nil {
 		return Load()
 	}
-	return globalConfig
+	return cfg
 }
 
 // SetPort 设置端口
@@ -502,6 +565,7 @@ func (c *Config) SetPort(port int) {
 	c.Port = port
 	// 更新 viper 中的值以便保持一致（可选）
 	viper.Set("port", port)
+	markOverride("port")
 }
 
 // GetDownloadsDir 获取下载目录
@@ -29,7 +29,15 @@ func setupIsolatedTestEnv(t *testing.T) {
 
 	tmpDir := t.TempDir()
 	t.Setenv("HOME", tmpDir)
-	t.Chdir(tmpDir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("无法获取工作目录: %v", err)
+	}
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("无法切换工作目录: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
 }
 
 func TestLoad_Defaults(t *testing.T) {
@@ -86,3 +94,115 @@ func TestSetPort(t *testing.T) {
 	cfg.SetPort(9090)
 	assert.Equal(t, 9090, cfg.Port)
 }
+
+func TestEffective_Provenance(t *testing.T) {
+	setupIsolatedTestEnv(t)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+download_dir: "/tmp/from-file"
+cloud_secret: "s3cret"
+`)
+	if err := os.WriteFile(configFile, content, 0644); err != nil {
+		t.Fatalf("无法创建配置文件: %v", err)
+	}
+	viper.SetConfigFile(configFile)
+	t.Setenv("WX_CHANNEL_LOG_FILE", "env.log")
+	t.Setenv("WX_CHANNEL_HUB_SYNC_PUSH_BATCH_SIZE", "50")
+
+	cfg := Load()
+	assert.Equal(t, 50, cfg.HubSync.PushBatchSize)
+
+	sources := map[string]EffectiveValue{}
+	for _, ev := range Effective(false) {
+		sources[ev.Key] = ev
+	}
+
+	assert.Equal(t, SourceFile, sources["download_dir"].Source)
+	assert.Equal(t, SourceEnv, sources["log_file"].Source)
+	assert.Equal(t, SourceEnv, sources["hub_sync.push_batch_size"].Source)
+	assert.Equal(t, SourceDefault, sources["chunk_size"].Source)
+	assert.Equal(t, RedactedValue, sources["cloud_secret"].Value)
+
+	ev, ok := Lookup("cloud_secret", true)
+	assert.True(t, ok)
+	assert.Equal(t, "s3cret", ev.Value)
+}
+
+func TestCheck_UnknownKeysAndBadValues(t *testing.T) {
+	setupIsolatedTestEnv(t)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+download_concurency: 3
+download_concurrency: -1
+`)
+	if err := os.WriteFile(configFile, content, 0644); err != nil {
+		t.Fatalf("无法创建配置文件: %v", err)
+	}
+	viper.SetConfigFile(configFile)
+	Load()
+
+	err := Check()
+	var verr *ValidationError
+	if assert.ErrorAs(t, err, &verr) {
+		joined := strings.Join(verr.Problems, "\n")
+		assert.Contains(t, joined, "download_concurency: unknown key")
+		assert.Contains(t, joined, "download_concurrency: must be >= 1, got -1")
+	}
+}
+
+func TestSetFileValue(t *testing.T) {
+	setupIsolatedTestEnv(t)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	viper.SetConfigFile(configFile)
+	Load()
+
+	assert.Error(t, SetFileValue(configFile, "download_concurrency", "-2"))
+	assert.Error(t, SetFileValue(configFile, "no_such_key", "1"))
+	assert.NoError(t, SetFileValue(configFile, "download_concurrency", "7"))
+	assert.NoError(t, SetFileValue(configFile, "hub_sync.push_interval", "90s"))
+
+	cfg := Reload()
+	assert.Equal(t, 7, cfg.DownloadConcurrency)
+	assert.Equal(t, 90*time.Second, cfg.HubSync.PushInterval)
+	assert.Equal(t, SourceFile, SourceOf("download_concurrency"))
+
+	unknown, err := UnknownKeys(configFile)
+	assert.NoError(t, err)
+	assert.Empty(t, unknown)
+}
+
+func TestReloadIfValid_NotifiesAndTracksPendingRestart(t *testing.T) {
+	setupIsolatedTestEnv(t)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("port: 2025\ndownload_concurrency: 2\n"), 0644); err != nil {
+		t.Fatalf("无法创建配置文件: %v", err)
+	}
+	viper.SetConfigFile(configFile)
+	MarkRunning(Load())
+	t.Cleanup(func() { MarkRunning(nil) })
+
+	var got []string
+	OnChange(func(old, new *Config, changed []string) { got = changed })
+
+	if err := os.WriteFile(configFile, []byte("port: 3000\ndownload_concurrency: 4\n"), 0644); err != nil {
+		t.Fatalf("无法更新配置文件: %v", err)
+	}
+	changed, err := ReloadIfValid()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"port", "download_concurrency"}, changed)
+	assert.ElementsMatch(t, changed, got)
+	assert.Equal(t, 4, Get().DownloadConcurrency)
+	assert.Equal(t, []string{"port"}, PendingRestart())
+
+	// 非法配置不生效，保留旧值
+	if err := os.WriteFile(configFile, []byte("port: 3000\ndownload_concurrency: -1\n"), 0644); err != nil {
+		t.Fatalf("无法更新配置文件: %v", err)
+	}
+	_, err = ReloadIfValid()
+	assert.Error(t, err)
+	assert.Equal(t, 4, Get().DownloadConcurrency)
+}
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Source 配置值的来源层
+type Source string
+
+const (
+	SourceDefault  Source = "default"  // 内置默认值
+	SourceFile     Source = "file"     // 配置文件 (config.yaml)
+	SourceEnv      Source = "env"      // WX_CHANNEL_* 环境变量
+	SourceOverride Source = "override" // 运行时覆盖（命令行参数等）
+	SourceDatabase Source = "database" // 数据库 settings 表（优先级最高）
+)
+
+// RedactedValue 敏感配置项的脱敏占位
+const RedactedValue = "******"
+
+// EffectiveValue 单个配置项的生效值及其来源
+type EffectiveValue struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source Source      `json:"source"`
+	Env    string      `json:"env"`
+	Secret bool        `json:"secret,omitempty"`
+}
+
+// secretKeys 需要脱敏展示的配置项
+var secretKeys = map[string]bool{
+	"secret_token":      true,
+	"web_console_token": true,
+	"cloud_secret":      true,
+	"bind_token":        true,
+}
+
+// databaseKeys 配置键 -> settings 表中的键，与 loadFromDatabase 保持一致
+var databaseKeys = map[string]string{
+	"download_dir":            "download_dir",
+	"chunk_size":              "chunk_size",
+	"max_retries":             "max_retries",
+	"download_concurrency":    "concurrent_limit",
+	"log_file":                "log_file",
+	"max_log_size_mb":         "max_log_size_mb",
+	"save_page_snapshot":      "save_page_snapshot",
+	"save_search_data":        "save_search_data",
+	"save_page_js":            "save_page_js",
+	"show_log_button":         "show_log_button",
+	"enable_log_interception": "enable_log_interception",
+	"cloud_enabled":           "cloud_enabled",
+	"cloud_hub_url":           "cloud_hub_url",
+	"cloud_secret":            "cloud_secret",
+	"machine_id":              "machine_id",
+	"radar_enabled":           "radar_enabled",
+}
+
+// extraKeys 不属于 Config 结构体但合法的键（由命令行参数绑定）
+var extraKeys = map[string]bool{
+	"dev": true,
+}
+
+var (
+	provenanceMu    sync.RWMutex
+	databaseSources = map[string]bool{}
+	overrideSources = map[string]bool{}
+)
+
+func resetDatabaseSources() {
+	provenanceMu.Lock()
+	databaseSources = map[string]bool{}
+	provenanceMu.Unlock()
+}
+
+// recordDatabaseSources 记录哪些配置项实际被数据库覆盖
+func recordDatabaseSources() {
+	provenanceMu.Lock()
+	defer provenanceMu.Unlock()
+	for key, dbKey := range databaseKeys {
+		if val, err := dbLoader.Get(dbKey); err == nil && val != "" {
+			databaseSources[key] = true
+		}
+	}
+}
+
+func markOverride(key string) {
+	provenanceMu.Lock()
+	overrideSources[key] = true
+	provenanceMu.Unlock()
+}
+
+// configField 配置键与结构体字段的对应关系
+type configField struct {
+	key   string
+	index []int
+}
+
+var (
+	fieldsOnce sync.Once
+	fields     []configField
+)
+
+// configFields 通过 mapstructure 标签遍历 Config，嵌套结构体展开为 a.b 形式
+func configFields() []configField {
+	fieldsOnce.Do(func() {
+		fields = collectFields(reflect.TypeOf(Config{}), "", nil)
+	})
+	return fields
+}
+
+func collectFields(t reflect.Type, prefix string, parent []int) []configField {
+	var result []configField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		key := prefix + tag
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Duration(0)) {
+			result = append(result, collectFields(f.Type, key+".", index)...)
+			continue
+		}
+		result = append(result, configField{key: key, index: index})
+	}
+	return result
+}
+
+// Keys 返回所有已知的配置键
+func Keys() []string {
+	keys := make([]string, 0, len(configFields()))
+	for _, f := range configFields() {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+// IsKnownKey 判断配置键是否合法
+func IsKnownKey(key string) bool {
+	key = strings.ToLower(key)
+	if extraKeys[key] {
+		return true
+	}
+	_, ok := lookupField(key)
+	return ok
+}
+
+// IsSecretKey 判断配置键是否为敏感项
+func IsSecretKey(key string) bool {
+	return secretKeys[strings.ToLower(key)]
+}
+
+func lookupField(key string) (configField, bool) {
+	for _, f := range configFields() {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return configField{}, false
+}
+
+// EnvName 返回配置键对应的环境变量名
+func EnvName(key string) string {
+	return "WX_CHANNEL_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// SourceOf 返回配置键当前值的来源层
+// 优先级：数据库 > 运行时覆盖 > 环境变量 > 配置文件 > 默认值
+func SourceOf(key string) Source {
+	provenanceMu.RLock()
+	fromDB, fromOverride := databaseSources[key], overrideSources[key]
+	provenanceMu.RUnlock()
+
+	switch {
+	case fromDB:
+		return SourceDatabase
+	case fromOverride:
+		return SourceOverride
+	}
+	if _, ok := os.LookupEnv(EnvName(key)); ok {
+		return SourceEnv
+	}
+	if viper.InConfig(key) {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// Effective 列出所有配置项的生效值和来源，敏感项默认脱敏
+func Effective(showSecrets bool) []EffectiveValue {
+	cfg := Get()
+	v := reflect.ValueOf(cfg).Elem()
+
+	result := make([]EffectiveValue, 0, len(configFields()))
+	for _, f := range configFields() {
+		result = append(result, effectiveValue(f, v, showSecrets))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Lookup 获取单个配置项的生效值和来源
+func Lookup(key string, showSecrets bool) (EffectiveValue, bool) {
+	f, ok := lookupField(strings.ToLower(key))
+	if !ok {
+		return EffectiveValue{}, false
+	}
+	return effectiveValue(f, reflect.ValueOf(Get()).Elem(), showSecrets), true
+}
+
+func effectiveValue(f configField, v reflect.Value, showSecrets bool) EffectiveValue {
+	ev := EffectiveValue{
+		Key:    f.key,
+		Value:  displayValue(v.FieldByIndex(f.index).Interface()),
+		Source: SourceOf(f.key),
+		Env:    EnvName(f.key),
+		Secret: secretKeys[f.key],
+	}
+	if ev.Secret && !showSecrets {
+		if s, _ := ev.Value.(string); s != "" {
+			ev.Value = RedactedValue
+		}
+	}
+	return ev
+}
+
+// displayValue 将时长转换为可读字符串，其余类型原样返回
+func displayValue(val interface{}) interface{} {
+	if d, ok := val.(time.Duration); ok {
+		return d.String()
+	}
+	return val
+}
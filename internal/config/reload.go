@@ -0,0 +1,198 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"wx_channel/internal/utils"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// ChangeFunc 配置变更回调，changed 为值发生变化的配置键
+type ChangeFunc func(old, new *Config, changed []string)
+
+// restartKeys 修改后需要重启才能生效的配置项（端口、证书、监听服务等在启动时绑定）
+// 其余配置项要么每次使用时读取 config.Get()，要么由 OnChange 订阅者实时应用
+var restartKeys = map[string]bool{
+	"port":                     true,
+	"default_port":             true,
+	"cert_file":                true,
+	"cert_install_delay":       true,
+	"download_dir":             true,
+	"records_file":             true,
+	"secret_token":             true,
+	"log_file":                 true,
+	"max_log_size_mb":          true,
+	"metrics_enabled":          true,
+	"metrics_port":             true,
+	"cloud_enabled":            true,
+	"cloud_hub_url":            true,
+	"cloud_secret":             true,
+	"machine_id":               true,
+	"bind_token":               true,
+	"load_balancer_strategy":   true,
+	"hub_sync.enabled":         true,
+	"hub_sync.push_enabled":    true,
+	"hub_sync.push_interval":   true,
+	"hub_sync.push_batch_size": true,
+}
+
+// reloadDebounce 编辑器保存时往往触发多次写事件，合并后再重新加载
+const reloadDebounce = 500 * time.Millisecond
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   []ChangeFunc
+
+	runningMu     sync.RWMutex
+	runningConfig *Config
+
+	watchOnce sync.Once
+)
+
+// OnChange 注册配置变更回调
+func OnChange(fn ChangeFunc) {
+	subscribersMu.Lock()
+	subscribers = append(subscribers, fn)
+	subscribersMu.Unlock()
+}
+
+// RequiresRestart 判断配置项修改后是否需要重启
+func RequiresRestart(key string) bool {
+	return restartKeys[key]
+}
+
+// Diff 返回两份配置中取值不同的配置键
+func Diff(a, b *Config) []string {
+	if a == nil || b == nil {
+		return nil
+	}
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+
+	var changed []string
+	for _, f := range configFields() {
+		if !reflect.DeepEqual(va.FieldByIndex(f.index).Interface(), vb.FieldByIndex(f.index).Interface()) {
+			changed = append(changed, f.key)
+		}
+	}
+	return changed
+}
+
+func notifyChange(old, cfg *Config) {
+	changed := Diff(old, cfg)
+	if len(changed) == 0 {
+		return
+	}
+
+	subscribersMu.Lock()
+	fns := append([]ChangeFunc(nil), subscribers...)
+	subscribersMu.Unlock()
+
+	for _, fn := range fns {
+		fn(old, cfg, changed)
+	}
+}
+
+// MarkRunning 记录当前进程启动时实际生效的配置，用于计算待重启项
+func MarkRunning(cfg *Config) {
+	runningMu.Lock()
+	runningConfig = cfg
+	runningMu.Unlock()
+}
+
+// PendingRestart 返回已修改但需要重启才能生效的配置项
+func PendingRestart() []string {
+	runningMu.RLock()
+	running := runningConfig
+	runningMu.RUnlock()
+	if running == nil {
+		return []string{}
+	}
+
+	pending := []string{}
+	for _, key := range Diff(running, Get()) {
+		if restartKeys[key] {
+			pending = append(pending, key)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// ReloadIfValid 重新加载配置；新配置校验不通过时保留旧配置并返回错误
+func ReloadIfValid() ([]string, error) {
+	globalMu.Lock()
+	old := globalConfig
+	candidate := loadConfig()
+	if err := checkConfig(candidate); err != nil {
+		globalMu.Unlock()
+		return nil, err
+	}
+	globalConfig = candidate
+	globalMu.Unlock()
+
+	notifyChange(old, candidate)
+	return Diff(old, candidate), nil
+}
+
+// NotifyDatabaseChanged 数据库中的设置被修改后调用，重新合并各配置层
+func NotifyDatabaseChanged() {
+	reloadFrom("数据库设置")
+}
+
+// WatchFile 监听配置文件，文件修改后自动热加载（只会启动一次）
+func WatchFile() {
+	if viper.ConfigFileUsed() == "" {
+		return
+	}
+
+	watchOnce.Do(func() {
+		var (
+			mu    sync.Mutex
+			timer *time.Timer
+		)
+		viper.OnConfigChange(func(e fsnotify.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDebounce, func() {
+				reloadFrom("配置文件")
+			})
+		})
+		viper.WatchConfig()
+		utils.Info("✓ 已开启配置热加载: %s", viper.ConfigFileUsed())
+	})
+}
+
+func reloadFrom(source string) {
+	changed, err := ReloadIfValid()
+	if err != nil {
+		utils.Warn("%s变更未生效，继续使用原配置: %v", source, err)
+		return
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	var live, restart []string
+	for _, key := range changed {
+		if restartKeys[key] {
+			restart = append(restart, key)
+		} else {
+			live = append(live, key)
+		}
+	}
+	if len(live) > 0 {
+		utils.Info("%s已热加载: %s", source, strings.Join(live, ", "))
+	}
+	if len(restart) > 0 {
+		utils.Warn("%s中以下配置需重启后生效: %s", source, strings.Join(restart, ", "))
+	}
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"wx_channel/internal/utils"
+
+	"github.com/spf13/viper"
+)
+
+// ValidationError 汇总配置校验发现的所有问题
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// validLoadBalancerStrategies 与 app.configureLoadBalancer 支持的策略一致
+var validLoadBalancerStrategies = map[string]bool{
+	"roundrobin": true,
+	"leastconn":  true,
+	"weighted":   true,
+	"random":     true,
+}
+
+// Validate 校验配置取值，返回 *ValidationError
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		add("port: must be between 1 and 65535, got %d", c.Port)
+	}
+	if c.DefaultPort < 0 || c.DefaultPort > 65535 {
+		add("default_port: must be between 0 and 65535, got %d", c.DefaultPort)
+	}
+	if strings.TrimSpace(c.DownloadsDir) == "" {
+		add("download_dir: must not be empty")
+	}
+
+	positiveInts := []struct {
+		key   string
+		value int
+	}{
+		{"upload_chunk_concurrency", c.UploadChunkConcurrency},
+		{"upload_merge_concurrency", c.UploadMergeConcurrency},
+		{"download_concurrency", c.DownloadConcurrency},
+		{"download_connections", c.DownloadConnections},
+		{"radar_backfill_max_pages", c.RadarBackfillMaxPages},
+	}
+	for _, p := range positiveInts {
+		if p.value < 1 {
+			add("%s: must be >= 1, got %d", p.key, p.value)
+		}
+	}
+
+	nonNegativeInts := []struct {
+		key   string
+		value int
+	}{
+		{"max_retries", c.MaxRetries},
+		{"download_retry_count", c.DownloadRetryCount},
+		{"max_log_size_mb", c.MaxLogSizeMB},
+		{"compression_threshold", c.CompressionThreshold},
+		{"radar_hourly_budget", c.RadarHourlyBudget},
+		{"radar_breaker_threshold", c.RadarBreakerThreshold},
+	}
+	for _, p := range nonNegativeInts {
+		if p.value < 0 {
+			add("%s: must be >= 0, got %d", p.key, p.value)
+		}
+	}
+
+	positiveSizes := []struct {
+		key   string
+		value int64
+	}{
+		{"chunk_size", c.ChunkSize},
+		{"max_upload_size", c.MaxUploadSize},
+		{"buffer_size", c.BufferSize},
+	}
+	for _, p := range positiveSizes {
+		if p.value <= 0 {
+			add("%s: must be > 0, got %d", p.key, p.value)
+		}
+	}
+
+	if c.CertInstallDelay < 0 {
+		add("cert_install_delay: must not be negative, got %s", c.CertInstallDelay)
+	}
+	if c.SaveDelay < 0 {
+		add("save_delay: must not be negative, got %s", c.SaveDelay)
+	}
+	if c.RadarJitter < 0 {
+		add("radar_jitter: must not be negative, got %s", c.RadarJitter)
+	}
+	if c.RadarQuietHours != "" {
+		if _, err := utils.ParseTimeWindow(c.RadarQuietHours); err != nil {
+			add("radar_quiet_hours: %v", err)
+		}
+	}
+	if c.RadarBreakerThreshold > 0 && c.RadarBreakerCooldown <= 0 {
+		add("radar_breaker_cooldown: must be > 0 when radar_breaker_threshold is set, got %s", c.RadarBreakerCooldown)
+	}
+	if c.RadarBackfillPageDelay < 0 {
+		add("radar_backfill_page_delay: must not be negative, got %s", c.RadarBackfillPageDelay)
+	}
+	if c.RadarLiveWebhook != "" {
+		if u, err := url.Parse(c.RadarLiveWebhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("radar_live_webhook: must be an http(s) URL, got %q", c.RadarLiveWebhook)
+		}
+	}
+	if c.DownloadTimeout <= 0 {
+		add("download_timeout: must be > 0, got %s", c.DownloadTimeout)
+	}
+
+	if _, ok := utils.ParseLogLevel(c.LogLevel); !ok {
+		add("log_level: must be one of debug, info, warn, error, got %q", c.LogLevel)
+	}
+	if c.LoadBalancerStrategy != "" && !validLoadBalancerStrategies[c.LoadBalancerStrategy] {
+		add("load_balancer_strategy: must be one of roundrobin, leastconn, weighted, random, got %q", c.LoadBalancerStrategy)
+	}
+	if c.MetricsEnabled && (c.MetricsPort < 1 || c.MetricsPort > 65535) {
+		add("metrics_port: must be between 1 and 65535, got %d", c.MetricsPort)
+	}
+	if c.CloudEnabled {
+		u, err := url.Parse(c.CloudHubURL)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			add("cloud_hub_url: must be a ws:// or wss:// URL when cloud_enabled is true, got %q", c.CloudHubURL)
+		}
+	}
+	if c.HubSync.PushEnabled {
+		if c.HubSync.PushInterval <= 0 {
+			add("hub_sync.push_interval: must be > 0, got %s", c.HubSync.PushInterval)
+		}
+		if c.HubSync.PushBatchSize < 1 {
+			add("hub_sync.push_batch_size: must be >= 1, got %d", c.HubSync.PushBatchSize)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// UnknownKeys 返回配置文件中无法识别的键（拼写错误等）
+func UnknownKeys(configFile string) ([]string, error) {
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		if !IsKnownKey(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// Check 对当前加载的配置做完整校验：解码错误、配置文件中的未知键、取值范围
+func Check() error {
+	return checkConfig(Get())
+}
+
+func checkConfig(cfg *Config) error {
+	var problems []string
+	if decodeErr != nil {
+		problems = append(problems, decodeErr.Error())
+	}
+	if file := viper.ConfigFileUsed(); file != "" {
+		if _, statErr := os.Stat(file); statErr == nil {
+			unknown, err := UnknownKeys(file)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", file, err))
+			}
+			for _, key := range unknown {
+				problems = append(problems, fmt.Sprintf("%s: unknown key in %s", key, file))
+			}
+		}
+	}
+
+	var verr *ValidationError
+	if err := cfg.Validate(); errors.As(err, &verr) {
+		problems = append(problems, verr.Problems...)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// SetFileValue 校验后将单个配置项写入配置文件（只写该项，不会把默认值或环境变量落盘）
+func SetFileValue(configFile, key, raw string) error {
+	key = strings.ToLower(key)
+	f, ok := lookupField(key)
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+
+	// 按 viper 的解码规则（弱类型、时长字符串）把原始值转换到字段类型
+	candidate := *Get()
+	tmp := viper.New()
+	tmp.Set(key, raw)
+	if err := tmp.Unmarshal(&candidate); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	// 只拦截与本次修改相关的问题，避免其他既有问题阻止修复
+	var verr *ValidationError
+	if err := candidate.Validate(); errors.As(err, &verr) {
+		var related []string
+		for _, p := range verr.Problems {
+			if strings.HasPrefix(p, key+":") {
+				related = append(related, p)
+			}
+		}
+		if len(related) > 0 {
+			return &ValidationError{Problems: related}
+		}
+	}
+
+	typed := displayValue(reflect.ValueOf(candidate).FieldByIndex(f.index).Interface())
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	if _, err := os.Stat(configFile); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", configFile, err)
+		}
+	}
+	v.Set(key, typed)
+	if err := v.WriteConfigAs(configFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", configFile, err)
+	}
+	return nil
+}
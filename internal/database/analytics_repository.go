@@ -0,0 +1,200 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// 作者统计排序字段
+const (
+	AuthorSortVideos     = "videos"
+	AuthorSortDownloads  = "downloads"
+	AuthorSortBytes      = "bytes"
+	AuthorSortEngagement = "engagement"
+	AuthorSortRecent     = "recent"
+)
+
+// authorSortColumns 排序字段对应的 ORDER BY 子句
+var authorSortColumns = map[string]string{
+	AuthorSortVideos:     "video_count DESC",
+	AuthorSortDownloads:  "downloaded_count DESC",
+	AuthorSortBytes:      "stored_bytes DESC",
+	AuthorSortEngagement: "avg_likes DESC",
+	AuthorSortRecent:     "last_published_at DESC",
+}
+
+// AuthorAnalytics 表示单个作者的聚合统计
+type AuthorAnalytics struct {
+	AuthorID         string  `json:"authorId"`
+	AuthorName       string  `json:"authorName"`
+	VideoCount       int64   `json:"videoCount"`      // 见过的视频数
+	BrowseCount      int64   `json:"browseCount"`     // 浏览过的视频数
+	DownloadedCount  int64   `json:"downloadedCount"` // 已下载完成的视频数
+	StoredBytes      int64   `json:"storedBytes"`     // 已下载文件总大小
+	AvgLikes         float64 `json:"avgLikes"`        // 平均互动数据只统计采集到互动数据的视频
+	AvgComments      float64 `json:"avgComments"`
+	AvgFavs          float64 `json:"avgFavs"`
+	AvgForwards      float64 `json:"avgForwards"`
+	PublishedCount   int64   `json:"publishedCount"`   // 已知发布时间的视频数
+	FirstPublishedAt int64   `json:"firstPublishedAt"` // Unix 秒
+	LastPublishedAt  int64   `json:"lastPublishedAt"`
+	AvgIntervalHours float64 `json:"avgIntervalHours"` // 平均发布间隔
+	PostsPerWeek     float64 `json:"postsPerWeek"`
+}
+
+// ActivityHeatmap 表示浏览时间按星期和小时分布的热力图
+type ActivityHeatmap struct {
+	Cells    [7][24]int64 `json:"cells"`    // cells[weekday][hour]，weekday 0 为周日
+	Weekdays [7]int64     `json:"weekdays"` // 按星期汇总
+	Hours    [24]int64    `json:"hours"`    // 按小时汇总
+	Total    int64        `json:"total"`
+}
+
+// AnalyticsRepository 处理统计分析的聚合查询
+type AnalyticsRepository struct {
+	db *sql.DB
+}
+
+// NewAnalyticsRepository 创建一个新的 AnalyticsRepository
+func NewAnalyticsRepository() *AnalyticsRepository {
+	return &AnalyticsRepository{db: GetDB()}
+}
+
+// authorAnalyticsQuery 以 videos 表为主按作者聚合（走 idx_videos_author_id）；
+// 下载数据先按 video_id 聚合（走 idx_download_records_status_video 覆盖索引）再关联
+const authorAnalyticsQuery = `
+	SELECT v.author_id, MAX(v.author_name),
+		COUNT(*) AS video_count,
+		COUNT(b.id),
+		COUNT(d.video_id) AS downloaded_count,
+		COALESCE(SUM(d.bytes), 0) AS stored_bytes,
+		COALESCE(SUM(v.like_count) * 1.0 / NULLIF(SUM(v.engaged), 0), 0) AS avg_likes,
+		COALESCE(SUM(v.comment_count) * 1.0 / NULLIF(SUM(v.engaged), 0), 0),
+		COALESCE(SUM(v.fav_count) * 1.0 / NULLIF(SUM(v.engaged), 0), 0),
+		COALESCE(SUM(v.forward_count) * 1.0 / NULLIF(SUM(v.engaged), 0), 0),
+		COUNT(NULLIF(v.published_at, 0)),
+		COALESCE(MIN(NULLIF(v.published_at, 0)), 0),
+		MAX(v.published_at) AS last_published_at
+	FROM (
+		SELECT id, author_id, author_name, like_count, comment_count, fav_count, forward_count, published_at,
+			(like_count > 0 OR comment_count > 0 OR fav_count > 0 OR forward_count > 0) AS engaged
+		FROM videos WHERE author_id != ''
+	) v
+	LEFT JOIN browse_history b ON b.id = v.id
+	LEFT JOIN (
+		SELECT video_id, SUM(file_size) AS bytes FROM download_records
+		WHERE status = 'completed' GROUP BY video_id
+	) d ON d.video_id = v.id`
+
+// AuthorAnalytics 返回按作者聚合的统计；authorID 非空时只统计该作者
+func (r *AnalyticsRepository) AuthorAnalytics(authorID, sort string, limit, offset int) ([]AuthorAnalytics, error) {
+	query := authorAnalyticsQuery
+	var args []interface{}
+	if authorID != "" {
+		query += " WHERE v.author_id = ?"
+		args = append(args, authorID)
+	}
+	order, ok := authorSortColumns[sort]
+	if !ok {
+		order = authorSortColumns[AuthorSortVideos]
+	}
+	query += " GROUP BY v.author_id ORDER BY " + order + ", v.author_id LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query author analytics: %w", err)
+	}
+	defer rows.Close()
+
+	authors := []AuthorAnalytics{}
+	for rows.Next() {
+		var a AuthorAnalytics
+		if err := rows.Scan(&a.AuthorID, &a.AuthorName, &a.VideoCount, &a.BrowseCount, &a.DownloadedCount,
+			&a.StoredBytes, &a.AvgLikes, &a.AvgComments, &a.AvgFavs, &a.AvgForwards,
+			&a.PublishedCount, &a.FirstPublishedAt, &a.LastPublishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan author analytics: %w", err)
+		}
+		a.fillCadence()
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+// CountAuthors 返回有视频记录的作者数
+func (r *AnalyticsRepository) CountAuthors() (int64, error) {
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(DISTINCT author_id) FROM videos WHERE author_id != ''").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count authors: %w", err)
+	}
+	return count, nil
+}
+
+// fillCadence 根据首末发布时间计算平均发布间隔和每周发布数
+func (a *AuthorAnalytics) fillCadence() {
+	if a.PublishedCount < 2 || a.LastPublishedAt <= a.FirstPublishedAt {
+		return
+	}
+	span := float64(a.LastPublishedAt - a.FirstPublishedAt)
+	intervals := float64(a.PublishedCount - 1)
+	a.AvgIntervalHours = span / intervals / 3600
+	a.PostsPerWeek = intervals / (span / (7 * 24 * 3600))
+}
+
+// TopVideos 按点赞数返回表现最好的视频，authorID 为空时不限作者
+func (r *AnalyticsRepository) TopVideos(authorID string, limit int) ([]Video, error) {
+	query := "SELECT " + videoColumns + " FROM videos WHERE like_count > 0"
+	var args []interface{}
+	if authorID != "" {
+		query += " AND author_id = ?"
+		args = append(args, authorID)
+	}
+	query += " ORDER BY like_count DESC, comment_count DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top videos: %w", err)
+	}
+	defer rows.Close()
+	return scanVideos(rows)
+}
+
+// ActivityHeatmap 统计 since 之后的浏览时间分布，authorID 非空时只统计该作者
+func (r *AnalyticsRepository) ActivityHeatmap(authorID string, since time.Time) (*ActivityHeatmap, error) {
+	// browse_time 带时区存储，strftime 会先换算为 UTC，需要 localtime 换回本地时间
+	query := `
+		SELECT CAST(strftime('%w', browse_time, 'localtime') AS INTEGER),
+			CAST(strftime('%H', browse_time, 'localtime') AS INTEGER), COUNT(*)
+		FROM browse_history WHERE browse_time >= ?`
+	args := []interface{}{since.Local()}
+	if authorID != "" {
+		query += " AND author_id = ?"
+		args = append(args, authorID)
+	}
+	query += " GROUP BY 1, 2"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	heatmap := &ActivityHeatmap{}
+	for rows.Next() {
+		var weekday, hour int
+		var count int64
+		if err := rows.Scan(&weekday, &hour, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan activity heatmap: %w", err)
+		}
+		if weekday < 0 || weekday > 6 || hour < 0 || hour > 23 {
+			continue
+		}
+		heatmap.Cells[weekday][hour] += count
+		heatmap.Weekdays[weekday] += count
+		heatmap.Hours[hour] += count
+		heatmap.Total += count
+	}
+	return heatmap, rows.Err()
+}
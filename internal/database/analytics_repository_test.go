@@ -0,0 +1,98 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyticsRepository_AuthorAnalytics(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	videos := NewVideoRepository()
+	day := int64(24 * 3600)
+	base := int64(1767225600)
+	for i, v := range []Video{
+		{ID: "a1", AuthorID: "alice", AuthorName: "Alice", LikeCount: 100, CommentCount: 10, PublishedAt: base},
+		{ID: "a2", AuthorID: "alice", AuthorName: "Alice", LikeCount: 300, CommentCount: 30, PublishedAt: base + 2*day},
+		{ID: "a3", AuthorID: "alice", AuthorName: "Alice", PublishedAt: base + 4*day},
+		{ID: "b1", AuthorID: "bob", AuthorName: "Bob", LikeCount: 5},
+	} {
+		v := v
+		if err := videos.Upsert(&v); err != nil {
+			t.Fatalf("upsert %d: %v", i, err)
+		}
+	}
+	if err := NewBrowseHistoryRepository().Create(&BrowseRecord{ID: "a1", Title: "a1", Author: "Alice", AuthorID: "alice", BrowseTime: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	downloads := NewDownloadRecordRepository()
+	for _, d := range []DownloadRecord{
+		{ID: "d1", VideoID: "a1", Title: "a1", AuthorID: "alice", FileSize: 1000, Status: DownloadStatusCompleted, DownloadTime: time.Now()},
+		{ID: "d2", VideoID: "a2", Title: "a2", AuthorID: "alice", FileSize: 500, Status: DownloadStatusFailed, DownloadTime: time.Now()},
+	} {
+		d := d
+		if err := downloads.Create(&d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewAnalyticsRepository()
+	authors, err := repo.AuthorAnalytics("", AuthorSortVideos, 10, 0)
+	if err != nil {
+		t.Fatalf("AuthorAnalytics: %v", err)
+	}
+	if len(authors) != 2 || authors[0].AuthorID != "alice" {
+		t.Fatalf("unexpected authors: %+v", authors)
+	}
+	a := authors[0]
+	if a.VideoCount != 3 || a.BrowseCount != 1 || a.DownloadedCount != 1 || a.StoredBytes != 1000 {
+		t.Errorf("unexpected counts: %+v", a)
+	}
+	// 平均互动只统计有互动数据的 2 个视频
+	if a.AvgLikes != 200 || a.AvgComments != 20 {
+		t.Errorf("unexpected engagement: likes=%v comments=%v", a.AvgLikes, a.AvgComments)
+	}
+	if a.PublishedCount != 3 || a.AvgIntervalHours != 48 || a.PostsPerWeek != 3.5 {
+		t.Errorf("unexpected cadence: %+v", a)
+	}
+
+	if authors, _ = repo.AuthorAnalytics("bob", AuthorSortBytes, 10, 0); len(authors) != 1 || authors[0].PostsPerWeek != 0 {
+		t.Errorf("expected bob only, got %+v", authors)
+	}
+
+	top, err := repo.TopVideos("alice", 1)
+	if err != nil || len(top) != 1 || top[0].ID != "a2" {
+		t.Errorf("unexpected top videos: %+v, %v", top, err)
+	}
+}
+
+func TestAnalyticsRepository_ActivityHeatmap(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	browse := NewBrowseHistoryRepository()
+	// 2026-03-02 是周一
+	monday := time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local)
+	for i, ts := range []time.Time{monday, monday.Add(10 * time.Minute), monday.Add(24*time.Hour + 12*time.Hour)} {
+		id := string(rune('a' + i))
+		if err := browse.Create(&BrowseRecord{ID: id, Title: id, AuthorID: "alice", BrowseTime: ts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	heatmap, err := NewAnalyticsRepository().ActivityHeatmap("", monday.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ActivityHeatmap: %v", err)
+	}
+	if heatmap.Total != 3 || heatmap.Cells[1][9] != 2 || heatmap.Cells[2][21] != 1 {
+		t.Errorf("unexpected heatmap: total=%d mon9=%d tue21=%d", heatmap.Total, heatmap.Cells[1][9], heatmap.Cells[2][21])
+	}
+	if heatmap.Weekdays[1] != 2 || heatmap.Hours[21] != 1 {
+		t.Errorf("unexpected totals: %+v %+v", heatmap.Weekdays, heatmap.Hours)
+	}
+
+	if heatmap, _ = NewAnalyticsRepository().ActivityHeatmap("bob", time.Time{}); heatmap.Total != 0 {
+		t.Errorf("expected empty heatmap for bob, got %d", heatmap.Total)
+	}
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Author 表示一个视频号作者，ID 为视频号 username
+type Author struct {
+	ID        string    `json:"id"`
+	Nickname  string    `json:"nickname"`
+	AvatarURL string    `json:"avatarUrl"`
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// AuthorStats 汇总某个作者在本地的数据
+type AuthorStats struct {
+	VideoCount    int64 `json:"videoCount"`
+	BrowseCount   int64 `json:"browseCount"`
+	DownloadCount int64 `json:"downloadCount"`
+	TotalLikes    int64 `json:"totalLikes"`
+	TotalComments int64 `json:"totalComments"`
+}
+
+// AuthorRepository 处理作者数据库操作
+type AuthorRepository struct {
+	db *sql.DB
+}
+
+// NewAuthorRepository 创建一个新的 AuthorRepository
+func NewAuthorRepository() *AuthorRepository {
+	return &AuthorRepository{db: GetDB()}
+}
+
+// Upsert 写入作者信息，已存在时只用非空字段覆盖
+func (r *AuthorRepository) Upsert(a *Author) error {
+	if a.ID == "" {
+		return fmt.Errorf("author id is required")
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO authors (id, nickname, avatar_url, signature) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET `+authorMergeSet,
+		a.ID, a.Nickname, a.AvatarURL, a.Signature,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert author: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据 username 获取作者
+func (r *AuthorRepository) GetByID(id string) (*Author, error) {
+	a := &Author{}
+	err := r.db.QueryRow(`
+		SELECT id, nickname, avatar_url, signature, created_at, updated_at FROM authors WHERE id = ?
+	`, id).Scan(&a.ID, &a.Nickname, &a.AvatarURL, &a.Signature, &a.CreatedAt, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+	return a, nil
+}
+
+// Stats 统计作者的视频数、浏览数、下载数和互动总数
+func (r *AuthorRepository) Stats(id string) (*AuthorStats, error) {
+	stats := &AuthorStats{}
+	err := r.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(like_count), 0), COALESCE(SUM(comment_count), 0),
+			(SELECT COUNT(*) FROM browse_history WHERE author_id = ?),
+			(SELECT COUNT(*) FROM download_records WHERE author_id = ? AND status = ?)
+		FROM videos WHERE author_id = ?
+	`, id, id, DownloadStatusCompleted, id).Scan(&stats.VideoCount, &stats.TotalLikes, &stats.TotalComments,
+		&stats.BrowseCount, &stats.DownloadCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author stats: %w", err)
+	}
+	return stats, nil
+}
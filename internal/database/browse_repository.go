@@ -22,19 +22,21 @@ func (r *BrowseHistoryRepository) Create(record *BrowseRecord) error {
 	now := time.Now()
 	record.CreatedAt = now
 	record.UpdatedAt = now
+	fillViewStats(record)
 
 	query := `
 		INSERT INTO browse_history (
 			id, title, author, author_id, duration, size, resolution, cover_url, video_url,
 			decrypt_key, browse_time, like_count, comment_count, fav_count, forward_count, page_url,
-			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			first_seen, view_count, sources, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := r.db.Exec(query,
 		record.ID, record.Title, record.Author, record.AuthorID,
 		record.Duration, record.Size, record.Resolution, record.CoverURL, record.VideoURL,
 		record.DecryptKey, record.BrowseTime, record.LikeCount, record.CommentCount,
-		record.FavCount, record.ForwardCount, record.PageURL, record.CreatedAt, record.UpdatedAt,
+		record.FavCount, record.ForwardCount, record.PageURL,
+		record.FirstSeen, record.ViewCount, strings.Join(record.Sources, ","), record.CreatedAt, record.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create browse record: %w", err)
@@ -42,29 +44,114 @@ func (r *BrowseHistoryRepository) Create(record *BrowseRecord) error {
 	return nil
 }
 
-// GetByID 根据 ID 获取浏览记录
-func (r *BrowseHistoryRepository) GetByID(id string) (*BrowseRecord, error) {
+// RecordView 记录一次观看：记录不存在时新建；已存在时观看次数加一、更新最后看到的时间（browse_time）
+// 并合并页面来源，其余字段只用非空值和正数覆盖，已有的解密密钥保持不变。返回累计观看次数
+func (r *BrowseHistoryRepository) RecordView(record *BrowseRecord) (int64, error) {
+	now := time.Now()
+	if record.BrowseTime.IsZero() {
+		record.BrowseTime = now
+	}
+	record.CreatedAt = now
+	record.UpdatedAt = now
+	record.FirstSeen = record.BrowseTime
+	record.ViewCount = 1
+	record.Sources = nil
+	if source := PageSource(record.PageURL); source != "" {
+		record.Sources = []string{source}
+	}
+	source := strings.Join(record.Sources, ",")
+
 	query := `
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
-		FROM browse_history WHERE id = ?
+		INSERT INTO browse_history (
+			id, title, author, author_id, duration, size, resolution, cover_url, video_url,
+			decrypt_key, browse_time, like_count, comment_count, fav_count, forward_count, page_url,
+			first_seen, view_count, sources, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = COALESCE(NULLIF(excluded.title, ''), browse_history.title),
+			author = COALESCE(NULLIF(excluded.author, ''), browse_history.author),
+			author_id = COALESCE(NULLIF(excluded.author_id, ''), browse_history.author_id),
+			duration = COALESCE(NULLIF(excluded.duration, 0), browse_history.duration),
+			size = COALESCE(NULLIF(excluded.size, 0), browse_history.size),
+			resolution = COALESCE(NULLIF(excluded.resolution, ''), browse_history.resolution),
+			cover_url = COALESCE(NULLIF(excluded.cover_url, ''), browse_history.cover_url),
+			video_url = COALESCE(NULLIF(excluded.video_url, ''), browse_history.video_url),
+			decrypt_key = COALESCE(NULLIF(browse_history.decrypt_key, ''), excluded.decrypt_key),
+			browse_time = excluded.browse_time,
+			like_count = CASE WHEN excluded.like_count > 0 THEN excluded.like_count ELSE browse_history.like_count END,
+			comment_count = CASE WHEN excluded.comment_count > 0 THEN excluded.comment_count ELSE browse_history.comment_count END,
+			fav_count = CASE WHEN excluded.fav_count > 0 THEN excluded.fav_count ELSE browse_history.fav_count END,
+			forward_count = CASE WHEN excluded.forward_count > 0 THEN excluded.forward_count ELSE browse_history.forward_count END,
+			page_url = COALESCE(NULLIF(excluded.page_url, ''), browse_history.page_url),
+			first_seen = COALESCE(browse_history.first_seen, excluded.first_seen),
+			view_count = browse_history.view_count + 1,
+			sources = CASE
+				WHEN excluded.sources = '' OR instr(',' || browse_history.sources || ',', ',' || excluded.sources || ',') > 0 THEN browse_history.sources
+				WHEN browse_history.sources = '' THEN excluded.sources
+				ELSE browse_history.sources || ',' || excluded.sources
+			END,
+			updated_at = excluded.updated_at
+		RETURNING view_count
 	`
-	record := &BrowseRecord{}
-	err := r.db.QueryRow(query, id).Scan(
-		&record.ID, &record.Title, &record.Author, &record.AuthorID,
-		&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-		&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-		&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-	)
+	var viewCount int64
+	err := r.db.QueryRow(query,
+		record.ID, record.Title, record.Author, record.AuthorID,
+		record.Duration, record.Size, record.Resolution, record.CoverURL, record.VideoURL,
+		record.DecryptKey, record.BrowseTime, record.LikeCount, record.CommentCount,
+		record.FavCount, record.ForwardCount, record.PageURL,
+		record.FirstSeen, source, record.CreatedAt, record.UpdatedAt,
+	).Scan(&viewCount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record browse view: %w", err)
+	}
+	return viewCount, nil
+}
+
+// FindUnidentified 查找没有视频 ID 的浏览记录（ID 以 browse_ 开头）中标题、作者和时长都相同的一条，
+// 用于把同一视频的多次观看合并到一行；不存在时返回空字符串
+func (r *BrowseHistoryRepository) FindUnidentified(title, author string, duration int64) (string, error) {
+	var id string
+	err := r.db.QueryRow(`
+		SELECT id FROM browse_history
+		WHERE id LIKE 'browse\_%' ESCAPE '\' AND title = ? AND author = ? AND duration = ?
+		ORDER BY browse_time DESC LIMIT 1
+	`, title, author, duration).Scan(&id)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return "", nil
 	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find browse record: %w", err)
+	}
+	return id, nil
+}
+
+// fillViewStats 补全首次看到时间、观看次数和来源的默认值
+func fillViewStats(record *BrowseRecord) {
+	if record.FirstSeen.IsZero() {
+		record.FirstSeen = record.BrowseTime
+	}
+	if record.ViewCount < 1 {
+		record.ViewCount = 1
+	}
+	if len(record.Sources) == 0 {
+		if source := PageSource(record.PageURL); source != "" {
+			record.Sources = []string{source}
+		}
+	}
+}
+
+// GetByID 根据 ID 获取浏览记录
+func (r *BrowseHistoryRepository) GetByID(id string) (*BrowseRecord, error) {
+	rows, err := r.db.Query("SELECT "+browseRecordColumns+" FROM browse_history WHERE id = ?", id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get browse record: %w", err)
 	}
-	return record, nil
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanBrowseRecord(rows)
 }
 
 // Update 更新现有的浏览记录
@@ -94,6 +181,46 @@ func (r *BrowseHistoryRepository) Update(record *BrowseRecord) error {
 	return nil
 }
 
+// Import 写入导入的浏览记录，已存在时整条覆盖；保留记录中的创建和更新时间
+func (r *BrowseHistoryRepository) Import(record *BrowseRecord) error {
+	now := time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+	fillViewStats(record)
+
+	query := `
+		INSERT INTO browse_history (
+			id, title, author, author_id, duration, size, resolution, cover_url, video_url,
+			decrypt_key, browse_time, like_count, comment_count, fav_count, forward_count, page_url,
+			first_seen, view_count, sources, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title, author = excluded.author, author_id = excluded.author_id,
+			duration = excluded.duration, size = excluded.size, resolution = excluded.resolution,
+			cover_url = excluded.cover_url, video_url = excluded.video_url, decrypt_key = excluded.decrypt_key,
+			browse_time = excluded.browse_time, like_count = excluded.like_count,
+			comment_count = excluded.comment_count, fav_count = excluded.fav_count,
+			forward_count = excluded.forward_count, page_url = excluded.page_url,
+			first_seen = excluded.first_seen, view_count = excluded.view_count, sources = excluded.sources,
+			created_at = excluded.created_at, updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query,
+		record.ID, record.Title, record.Author, record.AuthorID,
+		record.Duration, record.Size, record.Resolution, record.CoverURL, record.VideoURL,
+		record.DecryptKey, record.BrowseTime, record.LikeCount, record.CommentCount,
+		record.FavCount, record.ForwardCount, record.PageURL,
+		record.FirstSeen, record.ViewCount, strings.Join(record.Sources, ","), record.CreatedAt, record.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to import browse record: %w", err)
+	}
+	return nil
+}
+
 // Delete 根据 ID 删除浏览记录
 func (r *BrowseHistoryRepository) Delete(id string) error {
 	query := "DELETE FROM browse_history WHERE id = ?"
@@ -138,147 +265,188 @@ func (r *BrowseHistoryRepository) Clear() error {
 	return nil
 }
 
+// browseRecordColumns 是查询浏览记录时 SELECT 的列，与 scanBrowseRecord 对应
+const browseRecordColumns = `id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
+	decrypt_key, browse_time, like_count, comment_count,
+	COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
+	first_seen, view_count, sources, created_at, updated_at`
+
 // List 获取分页和排序的浏览记录
 func (r *BrowseHistoryRepository) List(params *PaginationParams) (*PagedResult[BrowseRecord], error) {
-	// Set defaults
-	if params.Page < 1 {
-		params.Page = 1
-	}
-	if params.PageSize < 1 {
-		params.PageSize = 20
-	}
-	if params.PageSize > 100 {
-		params.PageSize = 100
-	}
-	if params.SortBy == "" {
-		params.SortBy = "browse_time"
-	}
+	filter := &FilterParams{PaginationParams: *params}
+	result, err := r.ListFiltered(filter)
+	*params = filter.PaginationParams
+	return result, err
+}
 
-	// Validate sort column
+// ListFiltered 获取符合过滤条件的分页浏览记录，支持日期范围、全文检索、结构化条件和游标分页
+func (r *BrowseHistoryRepository) ListFiltered(params *FilterParams) (*PagedResult[BrowseRecord], error) {
 	validColumns := map[string]bool{
 		"browse_time": true, "title": true, "author": true,
 		"duration": true, "size": true, "created_at": true,
+		"like_count": true, "comment_count": true, "fav_count": true,
 	}
 	if !validColumns[params.SortBy] {
 		params.SortBy = "browse_time"
 	}
 
-	// Count total
-	var total int64
-	err := r.db.QueryRow("SELECT COUNT(*) FROM browse_history").Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count browse records: %w", err)
-	}
-
-	// Build query
-	sortOrder := "DESC"
-	if !params.SortDesc {
-		sortOrder = "ASC"
-	}
-	offset := (params.Page - 1) * params.PageSize
+	whereClause, args := browseWhere(params)
+	query := &pageQuery[BrowseRecord]{
+		table:   "browse_history",
+		columns: browseRecordColumns,
+		where:   whereClause,
+		args:    args,
+		sortBy:  params.SortBy,
+		order:   timeOrder("browse_time", bindTime),
+		noun:    "browse records",
+		scan:    scanBrowseRecord,
+		cursor: func(record *BrowseRecord) pageCursor {
+			return pageCursor{Time: record.BrowseTime, ID: record.ID}
+		},
+	}
+	return query.run(r.db, &params.PaginationParams)
+}
 
-	query := fmt.Sprintf(`
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
+// FindAll 获取符合过滤条件的全部浏览记录（不分页），用于导出
+func (r *BrowseHistoryRepository) FindAll(params *FilterParams) ([]BrowseRecord, error) {
+	whereClause, args := browseWhere(params)
+	query := `
+		SELECT ` + browseRecordColumns + `
 		FROM browse_history
-		ORDER BY %s %s
-		LIMIT ? OFFSET ?
-	`, params.SortBy, sortOrder)
+		` + whereClause + `
+		ORDER BY browse_time DESC
+	`
 
-	rows, err := r.db.Query(query, params.PageSize, offset)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list browse records: %w", err)
 	}
 	defer rows.Close()
 
-	var records []BrowseRecord
-	for rows.Next() {
-		var record BrowseRecord
-		err := rows.Scan(
-			&record.ID, &record.Title, &record.Author, &record.AuthorID,
-			&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-			&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-			&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan browse record: %w", err)
-		}
-		records = append(records, record)
-	}
-
-	if records == nil {
-		records = []BrowseRecord{}
-	}
-
-	return NewPagedResult(records, total, params.Page, params.PageSize), nil
+	return scanBrowseRecords(rows)
 }
 
-// Search 根据标题或作者搜索浏览记录
-func (r *BrowseHistoryRepository) Search(query string, params *PaginationParams) (*PagedResult[BrowseRecord], error) {
-	// Set defaults
-	if params.Page < 1 {
-		params.Page = 1
-	}
-	if params.PageSize < 1 {
-		params.PageSize = 20
+// browseWhere 根据过滤参数构造 WHERE 子句
+func browseWhere(params *FilterParams) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if params.StartDate != nil {
+		conditions = append(conditions, "browse_time >= ?")
+		args = append(args, *params.StartDate)
+	}
+	if params.EndDate != nil {
+		conditions = append(conditions, "browse_time <= ?")
+		args = append(args, *params.EndDate)
+	}
+	if params.Query != "" {
+		if match, ok := useFullText(params.Query); ok {
+			conditions = append(conditions, "rowid IN (SELECT rowid FROM browse_history_fts WHERE browse_history_fts MATCH ?)")
+			args = append(args, match)
+		} else {
+			conditions = append(conditions, "(title LIKE ? OR author LIKE ?)")
+			searchPattern := "%" + params.Query + "%"
+			args = append(args, searchPattern, searchPattern)
+		}
 	}
-	if params.PageSize > 100 {
-		params.PageSize = 100
+	if params.Filter != nil {
+		conds, filterArgs := params.Filter.conditions(browseColumns)
+		conditions = append(conditions, conds...)
+		args = append(args, filterArgs...)
 	}
 
-	searchPattern := "%" + query + "%"
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
 
-	// Count total
-	var total int64
-	err := r.db.QueryRow(
-		"SELECT COUNT(*) FROM browse_history WHERE title LIKE ? OR author LIKE ?",
-		searchPattern, searchPattern,
-	).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count search results: %w", err)
+// ForEach 按浏览时间倒序逐条读取记录，用于流式导出；ids 非空时按 ID 读取，否则按 params 过滤（nil 表示全部）
+func (r *BrowseHistoryRepository) ForEach(ids []string, params *FilterParams, fn func(*BrowseRecord) error) error {
+	var whereClause string
+	var args []interface{}
+	if len(ids) > 0 {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		whereClause = "WHERE id IN (" + strings.Join(placeholders, ",") + ")"
+	} else if params != nil {
+		whereClause, args = browseWhere(params)
 	}
 
-	// Build query
-	offset := (params.Page - 1) * params.PageSize
-	sqlQuery := `
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
+	query := `
+		SELECT ` + browseRecordColumns + `
 		FROM browse_history
-		WHERE title LIKE ? OR author LIKE ?
+		` + whereClause + `
 		ORDER BY browse_time DESC
-		LIMIT ? OFFSET ?
 	`
-
-	rows, err := r.db.Query(sqlQuery, searchPattern, searchPattern, params.PageSize, offset)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search browse records: %w", err)
+		return fmt.Errorf("failed to list browse records: %w", err)
 	}
 	defer rows.Close()
 
-	var records []BrowseRecord
 	for rows.Next() {
-		var record BrowseRecord
-		err := rows.Scan(
-			&record.ID, &record.Title, &record.Author, &record.AuthorID,
-			&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-			&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-			&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-		)
+		record, err := scanBrowseRecord(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan browse record: %w", err)
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
 		}
-		records = append(records, record)
 	}
+	return rows.Err()
+}
 
-	if records == nil {
-		records = []BrowseRecord{}
+// scanBrowseRecord 扫描一行浏览记录
+func scanBrowseRecord(rows *sql.Rows) (*BrowseRecord, error) {
+	record := &BrowseRecord{}
+	var firstSeen sql.NullTime
+	var sources string
+	err := rows.Scan(
+		&record.ID, &record.Title, &record.Author, &record.AuthorID,
+		&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
+		&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
+		&record.FavCount, &record.ForwardCount, &record.PageURL,
+		&firstSeen, &record.ViewCount, &sources, &record.CreatedAt, &record.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan browse record: %w", err)
+	}
+	record.FirstSeen = record.BrowseTime
+	if firstSeen.Valid {
+		record.FirstSeen = firstSeen.Time
 	}
+	record.Sources = []string{}
+	if sources != "" {
+		record.Sources = strings.Split(sources, ",")
+	}
+	return record, nil
+}
 
-	return NewPagedResult(records, total, params.Page, params.PageSize), nil
+// scanBrowseRecords 扫描浏览记录查询结果
+func scanBrowseRecords(rows *sql.Rows) ([]BrowseRecord, error) {
+	records := []BrowseRecord{}
+	for rows.Next() {
+		record, err := scanBrowseRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+// Search 根据标题或作者搜索浏览记录，按浏览时间倒序
+func (r *BrowseHistoryRepository) Search(query string, params *PaginationParams) (*PagedResult[BrowseRecord], error) {
+	filter := &FilterParams{PaginationParams: *params, Query: query}
+	filter.SortBy = "browse_time"
+	filter.SortDesc = true
+	result, err := r.ListFiltered(filter)
+	*params = filter.PaginationParams
+	return result, err
 }
 
 // Count 返回浏览记录的总数
@@ -298,10 +466,7 @@ func (r *BrowseHistoryRepository) GetRecent(limit int) ([]BrowseRecord, error) {
 	}
 
 	query := `
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
+		SELECT ` + browseRecordColumns + `
 		FROM browse_history
 		ORDER BY browse_time DESC
 		LIMIT ?
@@ -313,26 +478,7 @@ func (r *BrowseHistoryRepository) GetRecent(limit int) ([]BrowseRecord, error) {
 	}
 	defer rows.Close()
 
-	var records []BrowseRecord
-	for rows.Next() {
-		var record BrowseRecord
-		err := rows.Scan(
-			&record.ID, &record.Title, &record.Author, &record.AuthorID,
-			&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-			&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-			&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan browse record: %w", err)
-		}
-		records = append(records, record)
-	}
-
-	if records == nil {
-		records = []BrowseRecord{}
-	}
-
-	return records, nil
+	return scanBrowseRecords(rows)
 }
 
 // DeleteBefore 删除指定日期前的所有记录
@@ -347,10 +493,7 @@ func (r *BrowseHistoryRepository) DeleteBefore(date time.Time) (int64, error) {
 // GetAll 获取所有浏览记录（用于导出）
 func (r *BrowseHistoryRepository) GetAll() ([]BrowseRecord, error) {
 	query := `
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
+		SELECT ` + browseRecordColumns + `
 		FROM browse_history
 		ORDER BY browse_time DESC
 	`
@@ -361,26 +504,7 @@ func (r *BrowseHistoryRepository) GetAll() ([]BrowseRecord, error) {
 	}
 	defer rows.Close()
 
-	var records []BrowseRecord
-	for rows.Next() {
-		var record BrowseRecord
-		err := rows.Scan(
-			&record.ID, &record.Title, &record.Author, &record.AuthorID,
-			&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-			&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-			&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan browse record: %w", err)
-		}
-		records = append(records, record)
-	}
-
-	if records == nil {
-		records = []BrowseRecord{}
-	}
-
-	return records, nil
+	return scanBrowseRecords(rows)
 }
 
 // GetByIDs 根据 ID 获取浏览记录
@@ -397,10 +521,7 @@ func (r *BrowseHistoryRepository) GetByIDs(ids []string) ([]BrowseRecord, error)
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
+		SELECT `+browseRecordColumns+`
 		FROM browse_history
 		WHERE id IN (%s)
 		ORDER BY browse_time DESC
@@ -412,26 +533,7 @@ func (r *BrowseHistoryRepository) GetByIDs(ids []string) ([]BrowseRecord, error)
 	}
 	defer rows.Close()
 
-	var records []BrowseRecord
-	for rows.Next() {
-		var record BrowseRecord
-		err := rows.Scan(
-			&record.ID, &record.Title, &record.Author, &record.AuthorID,
-			&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-			&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-			&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan browse record: %w", err)
-		}
-		records = append(records, record)
-	}
-
-	if records == nil {
-		records = []BrowseRecord{}
-	}
-
-	return records, nil
+	return scanBrowseRecords(rows)
 }
 
 // GetRecordsSince 获取指定时间之后的浏览记录（用于增量同步）
@@ -444,10 +546,7 @@ func (r *BrowseHistoryRepository) GetRecordsSince(since time.Time, limit int) ([
 	}
 
 	query := `
-		SELECT id, title, author, author_id, duration, size, COALESCE(resolution, '') as resolution, cover_url, video_url,
-			decrypt_key, browse_time, like_count, comment_count, 
-			COALESCE(fav_count, 0) as fav_count, COALESCE(forward_count, 0) as forward_count, page_url,
-			created_at, updated_at
+		SELECT ` + browseRecordColumns + `
 		FROM browse_history
 		WHERE updated_at > ?
 		ORDER BY updated_at ASC
@@ -460,26 +559,7 @@ func (r *BrowseHistoryRepository) GetRecordsSince(since time.Time, limit int) ([
 	}
 	defer rows.Close()
 
-	var records []BrowseRecord
-	for rows.Next() {
-		var record BrowseRecord
-		err := rows.Scan(
-			&record.ID, &record.Title, &record.Author, &record.AuthorID,
-			&record.Duration, &record.Size, &record.Resolution, &record.CoverURL, &record.VideoURL,
-			&record.DecryptKey, &record.BrowseTime, &record.LikeCount, &record.CommentCount,
-			&record.FavCount, &record.ForwardCount, &record.PageURL, &record.CreatedAt, &record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan browse record: %w", err)
-		}
-		records = append(records, record)
-	}
-
-	if records == nil {
-		records = []BrowseRecord{}
-	}
-
-	return records, nil
+	return scanBrowseRecords(rows)
 }
 
 // GetLatestTimestamp 获取最新记录的时间戳（用于增量同步）
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBrowseRecordView(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBrowseHistoryRepository()
+	first := time.Date(2025, 6, 1, 8, 0, 0, 0, time.Local)
+	n, err := repo.RecordView(&BrowseRecord{
+		ID: "v1", Title: "标题", Author: "作者", DecryptKey: "111", LikeCount: 10,
+		BrowseTime: first, PageURL: "https://channels.weixin.qq.com/web/pages/feed?id=1",
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("first view: n=%d err=%v", n, err)
+	}
+
+	// 再次看到：空字段和 0 不覆盖，已有密钥保留，来源合并
+	last := first.Add(time.Hour)
+	for _, page := range []string{"https://channels.weixin.qq.com/web/pages/home", "https://channels.weixin.qq.com/web/pages/feed"} {
+		if n, err = repo.RecordView(&BrowseRecord{ID: "v1", DecryptKey: "222", BrowseTime: last, PageURL: page}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n != 3 {
+		t.Errorf("expected view count 3, got %d", n)
+	}
+
+	got, err := repo.GetByID("v1")
+	if err != nil || got == nil {
+		t.Fatalf("GetByID: %v %v", got, err)
+	}
+	if got.Title != "标题" || got.Author != "作者" || got.LikeCount != 10 || got.DecryptKey != "111" {
+		t.Errorf("expected existing fields to be kept, got %+v", got)
+	}
+	if !got.FirstSeen.Equal(first) || !got.BrowseTime.Equal(last) || got.ViewCount != 3 {
+		t.Errorf("unexpected first/last seen or count: %v %v %d", got.FirstSeen, got.BrowseTime, got.ViewCount)
+	}
+	if fmt.Sprint(got.Sources) != "[feed home]" {
+		t.Errorf("unexpected sources %v", got.Sources)
+	}
+
+	// 来源过滤包含之前各次观看的来源
+	filter, _, _ := ParseRecordQuery("source:feed")
+	result, err := repo.ListFiltered(&FilterParams{Filter: filter})
+	if err != nil || result.Total != 1 {
+		t.Errorf("expected v1 to match source:feed, got %+v %v", result, err)
+	}
+}
+
+func TestMigrationMergesUnidentifiedBrowseRecords(t *testing.T) {
+	all := migrations
+	defer func() { migrations = all }()
+
+	// 先迁移到合并前的版本，写入旧格式的重复记录
+	for i, m := range all {
+		if m.Version == 22 {
+			migrations = all[:i]
+		}
+	}
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insert := `INSERT INTO browse_history (id, title, author, author_id, cover_url, video_url, decrypt_key, duration, browse_time, page_url, created_at, updated_at)
+		VALUES (?, ?, ?, '', '', '', '', ?, ?, ?, ?, ?)`
+	base := time.Date(2025, 6, 1, 8, 0, 0, 0, time.Local)
+	rows := []struct {
+		id, title string
+		offset    time.Duration
+		page      string
+	}{
+		{"browse_1", "同一个视频", 0, "/web/pages/home"},
+		{"browse_2", "同一个视频", time.Hour, "/web/pages/feed"},
+		{"browse_3", "同一个视频", 2 * time.Hour, "/web/pages/feed"},
+		{"browse_4", "另一个视频", 0, "/web/pages/profile"},
+		{"real", "有 ID 的视频", 0, "/web/pages/home"},
+		{"browse_5", "有 ID 的视频", time.Hour, "/web/pages/s?q=1"},
+	}
+	for _, r := range rows {
+		at := base.Add(r.offset)
+		if _, err := db.Exec(insert, r.id, r.title, "作者", 1000, at, r.page, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrations = all
+	if err := runMigrations(); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+
+	repo := NewBrowseHistoryRepository()
+	if n, _ := repo.Count(); n != 3 {
+		t.Fatalf("expected 3 records after merge, got %d", n)
+	}
+
+	merged, err := repo.GetByID("browse_3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged == nil || merged.ViewCount != 3 || !merged.FirstSeen.Equal(base) || fmt.Sprint(merged.Sources) != "[home feed]" {
+		t.Errorf("unexpected merged record %+v", merged)
+	}
+	single, _ := repo.GetByID("browse_4")
+	if single == nil || single.ViewCount != 1 || fmt.Sprint(single.Sources) != "[profile]" {
+		t.Errorf("unexpected single record %+v", single)
+	}
+	real, _ := repo.GetByID("real")
+	if real == nil || real.ViewCount != 2 || fmt.Sprint(real.Sources) != "[home search]" {
+		t.Errorf("expected generated record merged into the one with a video ID, got %+v", real)
+	}
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"wx_channel/internal/utils"
+)
+
+// Collection 表示一个命名合集
+type Collection struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ItemCount   int64     `json:"itemCount"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CollectionItem 表示合集中的一个视频，标题等信息取自 videos 表，文件路径取自最近一次完成的下载
+type CollectionItem struct {
+	VideoID  string    `json:"videoId"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"addedAt"`
+	Title    string    `json:"title"`
+	Author   string    `json:"author"`
+	CoverURL string    `json:"coverUrl"`
+	Duration int64     `json:"duration"`
+	FilePath string    `json:"filePath"` // 为空表示尚未下载
+}
+
+// CollectionRepository 处理合集数据库操作
+type CollectionRepository struct {
+	db *sql.DB
+}
+
+// NewCollectionRepository 创建一个新的 CollectionRepository
+func NewCollectionRepository() *CollectionRepository {
+	return &CollectionRepository{db: GetDB()}
+}
+
+const collectionColumns = `
+	c.id, c.name, c.description, c.created_at, c.updated_at,
+	(SELECT COUNT(*) FROM collection_items ci WHERE ci.collection_id = c.id)
+`
+
+func scanCollection(scanner interface{ Scan(...interface{}) error }) (*Collection, error) {
+	c := &Collection{}
+	if err := scanner.Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt, &c.ItemCount); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// List 获取所有合集
+func (r *CollectionRepository) List() ([]Collection, error) {
+	rows, err := r.db.Query("SELECT " + collectionColumns + " FROM collections c ORDER BY c.name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, *c)
+	}
+	return collections, rows.Err()
+}
+
+// GetByID 根据 ID 获取合集，不存在时返回 nil
+func (r *CollectionRepository) GetByID(id string) (*Collection, error) {
+	c, err := scanCollection(r.db.QueryRow("SELECT "+collectionColumns+" FROM collections c WHERE c.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return c, nil
+}
+
+// Create 创建合集，名称不区分大小写且唯一
+func (r *CollectionRepository) Create(c *Collection) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("collection name is required")
+	}
+	if c.ID == "" {
+		c.ID = utils.RandomString(12)
+	}
+	now := time.Now()
+	c.CreatedAt, c.UpdatedAt = now, now
+	if _, err := r.db.Exec("INSERT INTO collections (id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		c.ID, c.Name, c.Description, c.CreatedAt, c.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	return nil
+}
+
+// Update 修改合集名称和描述
+func (r *CollectionRepository) Update(c *Collection) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("collection name is required")
+	}
+	c.UpdatedAt = time.Now()
+	result, err := r.db.Exec("UPDATE collections SET name = ?, description = ?, updated_at = ? WHERE id = ?",
+		c.Name, c.Description, c.UpdatedAt, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update collection: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("collection not found: %s", c.ID)
+	}
+	return nil
+}
+
+// Delete 删除合集（不影响视频记录）
+func (r *CollectionRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM collections WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("collection not found: %s", id)
+	}
+	return nil
+}
+
+// ListByVideo 获取包含指定视频的合集
+func (r *CollectionRepository) ListByVideo(videoID string) ([]Collection, error) {
+	rows, err := r.db.Query("SELECT "+collectionColumns+` FROM collections c
+		WHERE c.id IN (SELECT collection_id FROM collection_items WHERE video_id = ?) ORDER BY c.name`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections by video: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, *c)
+	}
+	return collections, rows.Err()
+}
+
+// AddItems 把视频追加到合集末尾，已在合集中的视频保持原位置，返回新增数量
+func (r *CollectionRepository) AddItems(collectionID string, videoIDs []string) (int64, error) {
+	if len(videoIDs) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var next int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(position), -1) + 1 FROM collection_items WHERE collection_id = ?",
+		collectionID).Scan(&next); err != nil {
+		return 0, fmt.Errorf("failed to get collection position: %w", err)
+	}
+
+	var added int64
+	now := time.Now()
+	for _, videoID := range videoIDs {
+		result, err := tx.Exec(`INSERT OR IGNORE INTO collection_items (collection_id, video_id, position, added_at)
+			VALUES (?, ?, ?, ?)`, collectionID, videoID, next, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add collection item: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added += n
+			next++
+		}
+	}
+	if _, err := tx.Exec("UPDATE collections SET updated_at = ? WHERE id = ?", now, collectionID); err != nil {
+		return 0, fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit collection items: %w", err)
+	}
+	return added, nil
+}
+
+// RemoveItems 从合集中移除视频，返回移除数量
+func (r *CollectionRepository) RemoveItems(collectionID string, videoIDs []string) (int64, error) {
+	if len(videoIDs) == 0 {
+		return 0, nil
+	}
+	args := []interface{}{collectionID}
+	for _, id := range videoIDs {
+		args = append(args, id)
+	}
+	result, err := r.db.Exec(fmt.Sprintf("DELETE FROM collection_items WHERE collection_id = ? AND video_id IN (%s)",
+		placeholders(len(videoIDs))), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove collection items: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Items 按顺序获取合集中的视频
+func (r *CollectionRepository) Items(collectionID string) ([]CollectionItem, error) {
+	rows, err := r.db.Query(`
+		SELECT ci.video_id, ci.position, ci.added_at,
+			COALESCE(v.title, ''), COALESCE(v.author_name, ''), COALESCE(v.cover_url, ''),
+			COALESCE(v.duration, 0), COALESCE(d.file_path, '')
+		FROM collection_items ci
+		LEFT JOIN videos v ON v.id = ci.video_id
+		LEFT JOIN download_records d ON d.id = (
+			SELECT id FROM download_records
+			WHERE video_id = ci.video_id AND status = 'completed'
+			ORDER BY download_time DESC LIMIT 1
+		)
+		WHERE ci.collection_id = ?
+		ORDER BY ci.position
+	`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []CollectionItem{}
+	for rows.Next() {
+		var item CollectionItem
+		if err := rows.Scan(&item.VideoID, &item.Position, &item.AddedAt, &item.Title, &item.Author,
+			&item.CoverURL, &item.Duration, &item.FilePath); err != nil {
+			return nil, fmt.Errorf("failed to scan collection item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// inCollectionCondition 生成"视频属于指定合集（按名称）"的条件
+func inCollectionCondition(column, name string) (string, []interface{}) {
+	return column + " IN (SELECT ci.video_id FROM collection_items ci JOIN collections c ON c.id = ci.collection_id WHERE c.name = ?)",
+		[]interface{}{strings.TrimSpace(name)}
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CommentRecord 表示采集到的一条评论（一级或二级）
+type CommentRecord struct {
+	ID          string    `json:"id"`
+	VideoID     string    `json:"videoId"`
+	VideoTitle  string    `json:"videoTitle"`
+	ParentID    string    `json:"parentId"` // 二级评论所属的一级评论 ID
+	Nickname    string    `json:"nickname"`
+	Content     string    `json:"content"`
+	LikeCount   int64     `json:"likeCount"`
+	CommentTime time.Time `json:"commentTime"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// CommentRepository 处理评论数据库操作
+type CommentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository 创建一个新的 CommentRepository
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{db: GetDB()}
+}
+
+// SaveBatch 批量保存评论，已存在的评论（相同 ID）会被更新
+func (r *CommentRepository) SaveBatch(records []CommentRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO comments (id, video_id, video_title, parent_id, nickname, content, like_count, comment_time, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			video_title = excluded.video_title,
+			nickname = excluded.nickname,
+			content = excluded.content,
+			like_count = excluded.like_count
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare comment insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for i := range records {
+		c := &records[i]
+		c.CreatedAt = now
+		if c.CommentTime.IsZero() {
+			c.CommentTime = now
+		}
+		if _, err := stmt.Exec(c.ID, c.VideoID, c.VideoTitle, c.ParentID, c.Nickname, c.Content,
+			c.LikeCount, c.CommentTime, c.CreatedAt); err != nil {
+			return fmt.Errorf("failed to save comment %s: %w", c.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comments: %w", err)
+	}
+	return nil
+}
+
+// ListByVideo 获取某个视频的全部评论
+func (r *CommentRepository) ListByVideo(videoID string) ([]CommentRecord, error) {
+	rows, err := r.db.Query(`
+		SELECT id, video_id, video_title, parent_id, nickname, content, like_count, comment_time, created_at
+		FROM comments
+		WHERE video_id = ?
+		ORDER BY comment_time ASC
+	`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	defer rows.Close()
+
+	records := []CommentRecord{}
+	for rows.Next() {
+		var c CommentRecord
+		if err := rows.Scan(&c.ID, &c.VideoID, &c.VideoTitle, &c.ParentID, &c.Nickname, &c.Content,
+			&c.LikeCount, &c.CommentTime, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		records = append(records, c)
+	}
+	return records, rows.Err()
+}
+
+// Count 返回评论总数
+func (r *CommentRepository) Count() (int64, error) {
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM comments").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+	return count, nil
+}
+
+// CountByVideo 返回某个视频已采集的评论数
+func (r *CommentRepository) CountByVideo(videoID string) (int64, error) {
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM comments WHERE video_id = ?", videoID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+	return count, nil
+}
@@ -17,6 +17,10 @@ var (
 	initMu      sync.Mutex
 )
 
+// dsnOptions 连接参数
+// _recursive_triggers: INSERT OR REPLACE 删除旧行时同样触发 DELETE 触发器，保证全文索引同步
+const dsnOptions = "?_foreign_keys=on&_journal_mode=WAL&_recursive_triggers=on"
+
 // Config 包含数据库配置
 type Config struct {
 	DBPath string
@@ -40,7 +44,7 @@ func Initialize(cfg *Config) error {
 
 	// 打开数据库连接
 	var err error
-	db, err = sql.Open("sqlite3", cfg.DBPath+"?_foreign_keys=on&_journal_mode=WAL")
+	db, err = sql.Open("sqlite3", cfg.DBPath+dsnOptions)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -18,7 +18,7 @@ func setupTestDB(t *testing.T) func() {
 	dbPath := filepath.Join(tmpDir, "test.db")
 
 	// 直接打开数据库进行测试（绕过 once）
-	testDB, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on&_journal_mode=WAL")
+	testDB, err := sql.Open("sqlite3", dbPath+dsnOptions)
 	if err != nil {
 		os.RemoveAll(tmpDir)
 		t.Fatalf("Failed to open database: %v", err)
@@ -316,3 +316,82 @@ func TestSettingsRepository(t *testing.T) {
 		t.Error("Expected validation error for high concurrent limit")
 	}
 }
+
+func TestRepositoryImport(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	browse := NewBrowseHistoryRepository()
+	updated := time.Date(2025, 6, 1, 8, 0, 0, 0, time.Local)
+	record := &BrowseRecord{
+		ID: "imp1", Title: "old", Author: "a", BrowseTime: updated,
+		CreatedAt: updated.Add(-time.Hour), UpdatedAt: updated,
+	}
+	if err := browse.Import(record); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	got, _ := browse.GetByID("imp1")
+	if got == nil || !got.UpdatedAt.Equal(updated) || !got.CreatedAt.Equal(updated.Add(-time.Hour)) {
+		t.Fatalf("expected imported timestamps to be kept, got %+v", got)
+	}
+
+	// 已存在时整条覆盖
+	record.Title = "new"
+	record.LikeCount = 9
+	if err := browse.Import(record); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = browse.GetByID("imp1"); got.Title != "new" || got.LikeCount != 9 {
+		t.Errorf("expected record to be overwritten, got %+v", got)
+	}
+
+	downloads := NewDownloadRecordRepository()
+	d := &DownloadRecord{ID: "d1", VideoID: "imp1", Title: "new", Status: DownloadStatusCompleted, FileSize: 42, UpdatedAt: updated}
+	if err := downloads.Import(d); err != nil {
+		t.Fatal(err)
+	}
+	d.FileSize = 84
+	if err := downloads.Import(d); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := downloads.GetByID("d1"); got == nil || got.FileSize != 84 || !got.UpdatedAt.Equal(updated) {
+		t.Errorf("unexpected imported download: %+v", got)
+	}
+}
+
+func TestRepositoryForEach(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBrowseHistoryRepository()
+	for _, r := range []*BrowseRecord{
+		{ID: "e1", Title: "猫咪日常", Author: "a", BrowseTime: time.Now()},
+		{ID: "e2", Title: "狗狗日常", Author: "b", BrowseTime: time.Now()},
+		{ID: "e3", Title: "猫咪合集", Author: "c", BrowseTime: time.Now()},
+	} {
+		if err := repo.Create(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect := func(ids []string, params *FilterParams) []string {
+		var got []string
+		if err := repo.ForEach(ids, params, func(r *BrowseRecord) error {
+			got = append(got, r.ID)
+			return nil
+		}); err != nil {
+			t.Fatalf("ForEach: %v", err)
+		}
+		return got
+	}
+
+	if got := collect(nil, nil); len(got) != 3 {
+		t.Errorf("expected all 3 records, got %v", got)
+	}
+	if got := collect([]string{"e2", "missing"}, nil); len(got) != 1 || got[0] != "e2" {
+		t.Errorf("expected only e2, got %v", got)
+	}
+	if got := collect(nil, &FilterParams{Query: "猫咪"}); len(got) != 2 {
+		t.Errorf("expected 2 records matching query, got %v", got)
+	}
+}
@@ -17,7 +17,7 @@ func NewDownloadRecordRepository() *DownloadRecordRepository {
 	return &DownloadRecordRepository{db: GetDB()}
 }
 
-// Create 插入新的下载记录
+// Create 插入新的下载记录，未指定作者 ID 时从 videos 表补全
 func (r *DownloadRecordRepository) Create(record *DownloadRecord) error {
 	now := time.Now()
 	record.CreatedAt = now
@@ -25,18 +25,19 @@ func (r *DownloadRecordRepository) Create(record *DownloadRecord) error {
 
 	query := `
 		INSERT OR REPLACE INTO download_records (
-			id, video_id, title, author, cover_url, duration, file_size, file_path,
+			id, video_id, title, author, author_id, cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), (SELECT author_id FROM videos WHERE id = ?), ''),
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := r.db.Exec(query,
-		record.ID, record.VideoID, record.Title, record.Author, record.CoverURL,
+		record.ID, record.VideoID, record.Title, record.Author, record.AuthorID, record.VideoID, record.CoverURL,
 		record.Duration, record.FileSize, record.FilePath, record.Format,
 		record.Resolution, record.Status, record.DownloadTime,
 		record.ErrorMessage,
-		record.LikeCount, record.CommentCount, record.ForwardCount, record.FavCount,
+		record.LikeCount, record.CommentCount, record.ForwardCount, record.FavCount, record.Group,
 		record.CreatedAt, record.UpdatedAt,
 	)
 	if err != nil {
@@ -48,20 +49,20 @@ func (r *DownloadRecordRepository) Create(record *DownloadRecord) error {
 // GetByID 根据 ID 获取下载记录
 func (r *DownloadRecordRepository) GetByID(id string) (*DownloadRecord, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records WHERE id = ?
 	`
 	record := &DownloadRecord{}
 	var filePath, format, resolution, errorMessage, coverURL sql.NullString
 	err := r.db.QueryRow(query, id).Scan(
-		&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
+		&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
 		&record.Duration, &record.FileSize, &filePath, &format,
 		&resolution, &record.Status, &record.DownloadTime,
 		&errorMessage,
-		&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
+		&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
 		&record.CreatedAt, &record.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -81,20 +82,20 @@ func (r *DownloadRecordRepository) GetByID(id string) (*DownloadRecord, error) {
 // GetByVideoID 根据 VideoID 获取下载记录
 func (r *DownloadRecordRepository) GetByVideoID(videoID string) (*DownloadRecord, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records WHERE video_id = ? LIMIT 1
 	`
 	record := &DownloadRecord{}
 	var filePath, format, resolution, errorMessage, coverURL sql.NullString
 	err := r.db.QueryRow(query, videoID).Scan(
-		&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
+		&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
 		&record.Duration, &record.FileSize, &filePath, &format,
 		&resolution, &record.Status, &record.DownloadTime,
 		&errorMessage,
-		&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
+		&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
 		&record.CreatedAt, &record.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -111,19 +112,35 @@ func (r *DownloadRecordRepository) GetByVideoID(videoID string) (*DownloadRecord
 	return record, nil
 }
 
+// ListByVideoID 获取某个视频的全部下载记录，按下载时间倒序
+func (r *DownloadRecordRepository) ListByVideoID(videoID string) ([]DownloadRecord, error) {
+	rows, err := r.db.Query(`
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+			format, resolution, status, download_time, error_message,
+			like_count, comment_count, forward_count, fav_count, group_name,
+			created_at, updated_at
+		FROM download_records WHERE video_id = ? ORDER BY download_time DESC
+	`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list download records by video id: %w", err)
+	}
+	defer rows.Close()
+	return scanDownloadRecords(rows)
+}
+
 // Update 更新现有的下载记录
 func (r *DownloadRecordRepository) Update(record *DownloadRecord) error {
 	record.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE download_records SET
-			video_id = ?, title = ?, author = ?, cover_url = ?, duration = ?, file_size = ?,
+			video_id = ?, title = ?, author = ?, author_id = ?, cover_url = ?, duration = ?, file_size = ?,
 			file_path = ?, format = ?, resolution = ?, status = ?,
 			download_time = ?, error_message = ?, updated_at = ?
 		WHERE id = ?
 	`
 	result, err := r.db.Exec(query,
-		record.VideoID, record.Title, record.Author, record.CoverURL, record.Duration,
+		record.VideoID, record.Title, record.Author, record.AuthorID, record.CoverURL, record.Duration,
 		record.FileSize, record.FilePath, record.Format, record.Resolution,
 		record.Status, record.DownloadTime, record.ErrorMessage,
 		record.UpdatedAt, record.ID,
@@ -138,6 +155,47 @@ func (r *DownloadRecordRepository) Update(record *DownloadRecord) error {
 	return nil
 }
 
+// Import 写入导入的下载记录，已存在时整条覆盖；保留记录中的创建和更新时间，未指定作者 ID 时从 videos 表补全
+func (r *DownloadRecordRepository) Import(record *DownloadRecord) error {
+	now := time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = now
+	}
+
+	query := `
+		INSERT INTO download_records (
+			id, video_id, title, author, author_id, cover_url, duration, file_size, file_path,
+			format, resolution, status, download_time, error_message,
+			like_count, comment_count, forward_count, fav_count, group_name,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), (SELECT author_id FROM videos WHERE id = ?), ''),
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			video_id = excluded.video_id, title = excluded.title, author = excluded.author,
+			author_id = excluded.author_id, cover_url = excluded.cover_url, duration = excluded.duration,
+			file_size = excluded.file_size, file_path = excluded.file_path, format = excluded.format,
+			resolution = excluded.resolution, status = excluded.status, download_time = excluded.download_time,
+			error_message = excluded.error_message, like_count = excluded.like_count,
+			comment_count = excluded.comment_count, forward_count = excluded.forward_count,
+			fav_count = excluded.fav_count, group_name = excluded.group_name, created_at = excluded.created_at, updated_at = excluded.updated_at
+	`
+	_, err := r.db.Exec(query,
+		record.ID, record.VideoID, record.Title, record.Author, record.AuthorID, record.VideoID, record.CoverURL,
+		record.Duration, record.FileSize, record.FilePath, record.Format,
+		record.Resolution, record.Status, record.DownloadTime,
+		record.ErrorMessage,
+		record.LikeCount, record.CommentCount, record.ForwardCount, record.FavCount, record.Group,
+		record.CreatedAt, record.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to import download record: %w", err)
+	}
+	return nil
+}
+
 // Delete 根据 ID 删除下载记录
 func (r *DownloadRecordRepository) Delete(id string) error {
 	query := "DELETE FROM download_records WHERE id = ?"
@@ -182,32 +240,65 @@ func (r *DownloadRecordRepository) Clear() error {
 	return nil
 }
 
-// List 获取分页、过滤和排序的下载记录
-func (r *DownloadRecordRepository) List(params *FilterParams) (*PagedResult[DownloadRecord], error) {
-	// Set defaults
-	if params.Page < 1 {
-		params.Page = 1
-	}
-	if params.PageSize < 1 {
-		params.PageSize = 20
-	}
-	if params.PageSize > 100 {
-		params.PageSize = 100
-	}
-	if params.SortBy == "" {
-		params.SortBy = "download_time"
-	}
+// downloadRecordColumns 是查询下载记录时 SELECT 的列，与 scanDownloadRecord 对应
+const downloadRecordColumns = `id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+	format, resolution, status, download_time, error_message,
+	like_count, comment_count, forward_count, fav_count, group_name,
+	created_at, updated_at`
 
+// List 获取分页、过滤和排序的下载记录，支持游标分页
+func (r *DownloadRecordRepository) List(params *FilterParams) (*PagedResult[DownloadRecord], error) {
 	// Validate sort column
 	validColumns := map[string]bool{
 		"download_time": true, "title": true, "author": true,
 		"file_size": true, "status": true, "created_at": true,
+		"duration": true, "like_count": true, "comment_count": true, "fav_count": true,
 	}
 	if !validColumns[params.SortBy] {
 		params.SortBy = "download_time"
 	}
 
-	// Build WHERE clause
+	whereClause, args := downloadWhere(params)
+	query := &pageQuery[DownloadRecord]{
+		table:   "download_records",
+		columns: downloadRecordColumns,
+		where:   whereClause,
+		args:    args,
+		sortBy:  params.SortBy,
+		order:   timeOrder("download_time", bindTime),
+		noun:    "download records",
+		scan:    scanDownloadRecord,
+		cursor: func(record *DownloadRecord) pageCursor {
+			return pageCursor{Time: record.DownloadTime, ID: record.ID}
+		},
+	}
+	return query.run(r.db, &params.PaginationParams)
+}
+
+// FindAll 获取符合过滤条件的全部下载记录（不分页），用于导出
+func (r *DownloadRecordRepository) FindAll(params *FilterParams) ([]DownloadRecord, error) {
+	whereClause, args := downloadWhere(params)
+	query := fmt.Sprintf(`
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+			format, resolution, status, download_time, error_message,
+			like_count, comment_count, forward_count, fav_count, group_name,
+			created_at, updated_at
+		FROM download_records
+		%s
+		ORDER BY download_time DESC
+	`, whereClause)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list download records: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDownloadRecords(rows)
+}
+
+// downloadWhere 根据过滤参数构造 WHERE 子句
+func downloadWhere(params *FilterParams) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 
@@ -224,77 +315,104 @@ func (r *DownloadRecordRepository) List(params *FilterParams) (*PagedResult[Down
 		args = append(args, params.Status)
 	}
 	if params.Query != "" {
-		conditions = append(conditions, "(title LIKE ? OR author LIKE ?)")
-		searchPattern := "%" + params.Query + "%"
-		args = append(args, searchPattern, searchPattern)
+		if match, ok := useFullText(params.Query); ok {
+			conditions = append(conditions, "rowid IN (SELECT rowid FROM download_records_fts WHERE download_records_fts MATCH ?)")
+			args = append(args, match)
+		} else {
+			conditions = append(conditions, "(title LIKE ? OR author LIKE ?)")
+			searchPattern := "%" + params.Query + "%"
+			args = append(args, searchPattern, searchPattern)
+		}
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if params.Filter != nil {
+		conds, filterArgs := params.Filter.conditions(downloadColumns)
+		conditions = append(conditions, conds...)
+		args = append(args, filterArgs...)
 	}
 
-	// Count total
-	var total int64
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM download_records %s", whereClause)
-	err := r.db.QueryRow(countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count download records: %w", err)
+	if len(conditions) == 0 {
+		return "", args
 	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
 
-	// Build query
-	sortOrder := "DESC"
-	if !params.SortDesc {
-		sortOrder = "ASC"
+// ForEach 按下载时间倒序逐条读取记录，用于流式导出；ids 非空时按 ID 读取，否则按 params 过滤（nil 表示全部）
+func (r *DownloadRecordRepository) ForEach(ids []string, params *FilterParams, fn func(*DownloadRecord) error) error {
+	var whereClause string
+	var args []interface{}
+	if len(ids) > 0 {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		whereClause = "WHERE id IN (" + strings.Join(placeholders, ",") + ")"
+	} else if params != nil {
+		whereClause, args = downloadWhere(params)
 	}
-	offset := (params.Page - 1) * params.PageSize
 
 	query := fmt.Sprintf(`
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records
 		%s
-		ORDER BY %s %s
-		LIMIT ? OFFSET ?
-	`, whereClause, params.SortBy, sortOrder)
-
-	args = append(args, params.PageSize, offset)
+		ORDER BY download_time DESC
+	`, whereClause)
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list download records: %w", err)
+		return fmt.Errorf("failed to list download records: %w", err)
 	}
 	defer rows.Close()
 
-	var records []DownloadRecord
 	for rows.Next() {
-		var record DownloadRecord
-		var filePath, format, resolution, errorMessage, coverURL sql.NullString
-		err := rows.Scan(
-			&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
-			&record.Duration, &record.FileSize, &filePath, &format,
-			&resolution, &record.Status, &record.DownloadTime,
-			&errorMessage,
-			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
-			&record.CreatedAt, &record.UpdatedAt,
-		)
+		record, err := scanDownloadRecord(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan download record: %w", err)
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
 		}
-		record.CoverURL = coverURL.String
-		record.FilePath = filePath.String
-		record.Format = format.String
-		record.Resolution = resolution.String
-		record.ErrorMessage = errorMessage.String
-		records = append(records, record)
 	}
+	return rows.Err()
+}
 
-	if records == nil {
-		records = []DownloadRecord{}
+// scanDownloadRecord 扫描一行下载记录
+func scanDownloadRecord(rows *sql.Rows) (*DownloadRecord, error) {
+	record := &DownloadRecord{}
+	var filePath, format, resolution, errorMessage, coverURL sql.NullString
+	err := rows.Scan(
+		&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
+		&record.Duration, &record.FileSize, &filePath, &format,
+		&resolution, &record.Status, &record.DownloadTime,
+		&errorMessage,
+		&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
+		&record.CreatedAt, &record.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan download record: %w", err)
 	}
+	record.CoverURL = coverURL.String
+	record.FilePath = filePath.String
+	record.Format = format.String
+	record.Resolution = resolution.String
+	record.ErrorMessage = errorMessage.String
+	return record, nil
+}
 
-	return NewPagedResult(records, total, params.Page, params.PageSize), nil
+// scanDownloadRecords 扫描下载记录查询结果
+func scanDownloadRecords(rows *sql.Rows) ([]DownloadRecord, error) {
+	records := []DownloadRecord{}
+	for rows.Next() {
+		record, err := scanDownloadRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
 }
 
 // Count 返回下载记录的总数
@@ -338,9 +456,9 @@ func (r *DownloadRecordRepository) GetRecent(limit int) ([]DownloadRecord, error
 	}
 
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records
 		ORDER BY download_time DESC
@@ -358,11 +476,11 @@ func (r *DownloadRecordRepository) GetRecent(limit int) ([]DownloadRecord, error
 		var record DownloadRecord
 		var filePath, format, resolution, errorMessage, coverURL sql.NullString
 		err := rows.Scan(
-			&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
+			&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
 			&record.Duration, &record.FileSize, &filePath, &format,
 			&resolution, &record.Status, &record.DownloadTime,
 			&errorMessage,
-			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
+			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
 			&record.CreatedAt, &record.UpdatedAt,
 		)
 		if err != nil {
@@ -395,9 +513,9 @@ func (r *DownloadRecordRepository) DeleteBefore(date time.Time) (int64, error) {
 // GetAll 获取所有下载记录（用于导出）
 func (r *DownloadRecordRepository) GetAll() ([]DownloadRecord, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records
 		ORDER BY download_time DESC
@@ -414,11 +532,11 @@ func (r *DownloadRecordRepository) GetAll() ([]DownloadRecord, error) {
 		var record DownloadRecord
 		var filePath, format, resolution, errorMessage, coverURL sql.NullString
 		err := rows.Scan(
-			&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
+			&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
 			&record.Duration, &record.FileSize, &filePath, &format,
 			&resolution, &record.Status, &record.DownloadTime,
 			&errorMessage,
-			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
+			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
 			&record.CreatedAt, &record.UpdatedAt,
 		)
 		if err != nil {
@@ -453,9 +571,9 @@ func (r *DownloadRecordRepository) GetByIDs(ids []string) ([]DownloadRecord, err
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records
 		WHERE id IN (%s)
@@ -473,11 +591,11 @@ func (r *DownloadRecordRepository) GetByIDs(ids []string) ([]DownloadRecord, err
 		var record DownloadRecord
 		var filePath, format, resolution, errorMessage, coverURL sql.NullString
 		err := rows.Scan(
-			&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
+			&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
 			&record.Duration, &record.FileSize, &filePath, &format,
 			&resolution, &record.Status, &record.DownloadTime,
 			&errorMessage,
-			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
+			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
 			&record.CreatedAt, &record.UpdatedAt,
 		)
 		if err != nil {
@@ -549,9 +667,9 @@ func (r *DownloadRecordRepository) GetRecordsSince(since time.Time, limit int) (
 	}
 
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, duration, file_size, file_path,
 			format, resolution, status, download_time, error_message,
-			like_count, comment_count, forward_count, fav_count,
+			like_count, comment_count, forward_count, fav_count, group_name,
 			created_at, updated_at
 		FROM download_records
 		WHERE updated_at > ?
@@ -570,11 +688,11 @@ func (r *DownloadRecordRepository) GetRecordsSince(since time.Time, limit int) (
 		var record DownloadRecord
 		var filePath, format, resolution, errorMessage, coverURL sql.NullString
 		err := rows.Scan(
-			&record.ID, &record.VideoID, &record.Title, &record.Author, &coverURL,
+			&record.ID, &record.VideoID, &record.Title, &record.Author, &record.AuthorID, &coverURL,
 			&record.Duration, &record.FileSize, &filePath, &format,
 			&resolution, &record.Status, &record.DownloadTime,
 			&errorMessage,
-			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount,
+			&record.LikeCount, &record.CommentCount, &record.ForwardCount, &record.FavCount, &record.Group,
 			&record.CreatedAt, &record.UpdatedAt,
 		)
 		if err != nil {
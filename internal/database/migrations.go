@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Migration 表示数据库迁移
@@ -9,6 +10,8 @@ type Migration struct {
 	Version     int
 	Description string
 	Up          string
+	// Requires 依赖的 SQLite 可选模块（如 "fts5"），当前构建不支持时跳过，待支持后再执行
+	Requires string
 }
 
 // migrations 按顺序包含所有数据库迁移
@@ -283,8 +286,527 @@ CREATE INDEX IF NOT EXISTS idx_radar_logs_check_time ON radar_logs(check_time);
 		Description: "Add video_list column to radar_logs for per-video details",
 		Up:          `ALTER TABLE radar_logs ADD COLUMN video_list TEXT DEFAULT '';`,
 	},
+	{
+		Version:     15,
+		Description: "Create comments table for captured comment text",
+		Up: `
+-- Comments table (采集的评论，含二级评论)
+CREATE TABLE IF NOT EXISTS comments (
+    id TEXT PRIMARY KEY,
+    video_id TEXT NOT NULL,
+    video_title TEXT NOT NULL DEFAULT '',
+    parent_id TEXT NOT NULL DEFAULT '',
+    nickname TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    like_count INTEGER DEFAULT 0,
+    comment_time DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_video_id ON comments(video_id);
+`,
+	},
+	{
+		Version:     16,
+		Description: "Create FTS5 full-text indexes for browse history, downloads and comments",
+		Requires:    "fts5",
+		Up: `
+-- trigram 分词对中文按三字切分，无需词典；外部内容表只存索引，内容从原表读取
+CREATE VIRTUAL TABLE IF NOT EXISTS browse_history_fts USING fts5(
+    title, author, content='browse_history', content_rowid='rowid', tokenize='trigram'
+);
+CREATE TRIGGER IF NOT EXISTS browse_history_fts_ai AFTER INSERT ON browse_history BEGIN
+    INSERT INTO browse_history_fts(rowid, title, author) VALUES (new.rowid, new.title, new.author);
+END;
+CREATE TRIGGER IF NOT EXISTS browse_history_fts_ad AFTER DELETE ON browse_history BEGIN
+    INSERT INTO browse_history_fts(browse_history_fts, rowid, title, author) VALUES ('delete', old.rowid, old.title, old.author);
+END;
+CREATE TRIGGER IF NOT EXISTS browse_history_fts_au AFTER UPDATE OF title, author ON browse_history BEGIN
+    INSERT INTO browse_history_fts(browse_history_fts, rowid, title, author) VALUES ('delete', old.rowid, old.title, old.author);
+    INSERT INTO browse_history_fts(rowid, title, author) VALUES (new.rowid, new.title, new.author);
+END;
+INSERT INTO browse_history_fts(browse_history_fts) VALUES ('rebuild');
+
+CREATE VIRTUAL TABLE IF NOT EXISTS download_records_fts USING fts5(
+    title, author, content='download_records', content_rowid='rowid', tokenize='trigram'
+);
+CREATE TRIGGER IF NOT EXISTS download_records_fts_ai AFTER INSERT ON download_records BEGIN
+    INSERT INTO download_records_fts(rowid, title, author) VALUES (new.rowid, new.title, new.author);
+END;
+CREATE TRIGGER IF NOT EXISTS download_records_fts_ad AFTER DELETE ON download_records BEGIN
+    INSERT INTO download_records_fts(download_records_fts, rowid, title, author) VALUES ('delete', old.rowid, old.title, old.author);
+END;
+CREATE TRIGGER IF NOT EXISTS download_records_fts_au AFTER UPDATE OF title, author ON download_records BEGIN
+    INSERT INTO download_records_fts(download_records_fts, rowid, title, author) VALUES ('delete', old.rowid, old.title, old.author);
+    INSERT INTO download_records_fts(rowid, title, author) VALUES (new.rowid, new.title, new.author);
+END;
+INSERT INTO download_records_fts(download_records_fts) VALUES ('rebuild');
+
+CREATE VIRTUAL TABLE IF NOT EXISTS comments_fts USING fts5(
+    content, nickname, video_title, content='comments', content_rowid='rowid', tokenize='trigram'
+);
+CREATE TRIGGER IF NOT EXISTS comments_fts_ai AFTER INSERT ON comments BEGIN
+    INSERT INTO comments_fts(rowid, content, nickname, video_title) VALUES (new.rowid, new.content, new.nickname, new.video_title);
+END;
+CREATE TRIGGER IF NOT EXISTS comments_fts_ad AFTER DELETE ON comments BEGIN
+    INSERT INTO comments_fts(comments_fts, rowid, content, nickname, video_title) VALUES ('delete', old.rowid, old.content, old.nickname, old.video_title);
+END;
+CREATE TRIGGER IF NOT EXISTS comments_fts_au AFTER UPDATE OF content, nickname, video_title ON comments BEGIN
+    INSERT INTO comments_fts(comments_fts, rowid, content, nickname, video_title) VALUES ('delete', old.rowid, old.content, old.nickname, old.video_title);
+    INSERT INTO comments_fts(rowid, content, nickname, video_title) VALUES (new.rowid, new.content, new.nickname, new.video_title);
+END;
+INSERT INTO comments_fts(comments_fts) VALUES ('rebuild');
+`,
+	},
+	{
+		Version:     17,
+		Description: "Create tags and collections tables linked to video IDs",
+		Up: `
+-- 用户自定义标签，按视频 ID 关联（浏览记录与下载记录共用）
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE,
+    color TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(name);
+
+CREATE TABLE IF NOT EXISTS video_tags (
+    video_id TEXT NOT NULL,
+    tag_id TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (video_id, tag_id),
+    FOREIGN KEY(tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_video_tags_tag_id ON video_tags(tag_id);
+
+-- 命名合集，条目有序
+CREATE TABLE IF NOT EXISTS collections (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_name ON collections(name);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    collection_id TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, video_id),
+    FOREIGN KEY(collection_id) REFERENCES collections(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_collection_items_video_id ON collection_items(video_id);
+`,
+	},
+	{
+		Version:     18,
+		Description: "Create normalised videos and authors tables referenced by records",
+		Up: `
+-- 视频元数据唯一来源，id 即视频 ID；浏览、下载、队列写入时由触发器合并更新
+CREATE TABLE IF NOT EXISTS videos (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    author_id TEXT NOT NULL DEFAULT '',
+    author_name TEXT NOT NULL DEFAULT '',
+    cover_url TEXT NOT NULL DEFAULT '',
+    duration INTEGER DEFAULT 0,
+    size INTEGER DEFAULT 0,
+    resolution TEXT NOT NULL DEFAULT '',
+    file_format TEXT NOT NULL DEFAULT '',
+    like_count INTEGER DEFAULT 0,
+    comment_count INTEGER DEFAULT 0,
+    fav_count INTEGER DEFAULT 0,
+    forward_count INTEGER DEFAULT 0,
+    page_url TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_videos_author_id ON videos(author_id);
+
+-- 作者，id 为视频号 username
+CREATE TABLE IF NOT EXISTS authors (
+    id TEXT PRIMARY KEY,
+    nickname TEXT NOT NULL DEFAULT '',
+    avatar_url TEXT NOT NULL DEFAULT '',
+    signature TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE download_records ADD COLUMN author_id TEXT DEFAULT '';
+ALTER TABLE download_queue ADD COLUMN author_id TEXT DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_download_records_video_id ON download_records(video_id);
+
+CREATE TRIGGER IF NOT EXISTS browse_history_videos_ai AFTER INSERT ON browse_history BEGIN
+` + browseVideoUpsert + `
+END;
+CREATE TRIGGER IF NOT EXISTS browse_history_videos_au AFTER UPDATE ON browse_history BEGIN
+` + browseVideoUpsert + `
+END;
+CREATE TRIGGER IF NOT EXISTS download_records_videos_ai AFTER INSERT ON download_records BEGIN
+` + downloadVideoUpsert + `
+END;
+CREATE TRIGGER IF NOT EXISTS download_records_videos_au AFTER UPDATE ON download_records BEGIN
+` + downloadVideoUpsert + `
+END;
+CREATE TRIGGER IF NOT EXISTS download_queue_videos_ai AFTER INSERT ON download_queue BEGIN
+` + queueVideoUpsert + `
+END;
+
+-- 迁移已有数据：队列、下载、浏览依次合并，浏览记录的信息最全放在最后
+INSERT INTO videos (id, title, author_name, cover_url, duration, size, resolution)
+SELECT video_id, title, author, COALESCE(cover_url, ''), COALESCE(duration, 0), COALESCE(total_size, 0), COALESCE(resolution, '')
+FROM download_queue WHERE video_id <> '' ORDER BY added_time
+ON CONFLICT(id) DO UPDATE SET ` + videoMergeSet + `;
+INSERT INTO videos (id, title, author_name, cover_url, duration, size, resolution, like_count, comment_count, fav_count, forward_count)
+SELECT video_id, title, author, COALESCE(cover_url, ''), COALESCE(duration, 0), COALESCE(file_size, 0), COALESCE(resolution, ''),
+    COALESCE(like_count, 0), COALESCE(comment_count, 0), COALESCE(fav_count, 0), COALESCE(forward_count, 0)
+FROM download_records WHERE video_id <> '' ORDER BY download_time
+ON CONFLICT(id) DO UPDATE SET ` + videoMergeSet + `;
+INSERT INTO videos (id, title, author_id, author_name, cover_url, duration, size, resolution, file_format,
+    like_count, comment_count, fav_count, forward_count, page_url, created_at)
+SELECT id, title, COALESCE(author_id, ''), author, COALESCE(cover_url, ''), COALESCE(duration, 0), COALESCE(size, 0),
+    COALESCE(resolution, ''), COALESCE(file_format, ''), COALESCE(like_count, 0), COALESCE(comment_count, 0),
+    COALESCE(fav_count, 0), COALESCE(forward_count, 0), COALESCE(page_url, ''), created_at
+FROM browse_history WHERE true
+ON CONFLICT(id) DO UPDATE SET ` + videoMergeSet + `;
+
+INSERT INTO authors (id, nickname)
+SELECT author_id, MAX(author) FROM browse_history WHERE COALESCE(author_id, '') <> '' GROUP BY author_id
+ON CONFLICT(id) DO NOTHING;
+INSERT INTO authors (id, nickname)
+SELECT username, author_name FROM radar_targets WHERE true
+ON CONFLICT(id) DO UPDATE SET nickname = COALESCE(NULLIF(authors.nickname, ''), excluded.nickname);
+
+UPDATE download_records SET author_id = COALESCE((SELECT v.author_id FROM videos v WHERE v.id = download_records.video_id), '');
+UPDATE download_queue SET author_id = COALESCE((SELECT v.author_id FROM videos v WHERE v.id = download_queue.video_id), '');
+`,
+	},
+	{
+		Version:     19,
+		Description: "Create append-only video_stats_snapshots table and re-poll tracking",
+		Up: `
+-- 互动数据快照，只追加不修改，用于趋势分析
+CREATE TABLE IF NOT EXISTS video_stats_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    video_id TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    like_count INTEGER DEFAULT 0,
+    comment_count INTEGER DEFAULT 0,
+    fav_count INTEGER DEFAULT 0,
+    forward_count INTEGER DEFAULT 0,
+    captured_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_video_stats_snapshots_video ON video_stats_snapshots(video_id, captured_at);
+
+-- nonce_id 供 feed_profile 重新拉取使用；stats_tracked 标记参与定时重新拉取的视频
+ALTER TABLE videos ADD COLUMN nonce_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE videos ADD COLUMN stats_tracked INTEGER NOT NULL DEFAULT 0;
+
+-- 已有的互动数据作为第一个快照
+INSERT INTO video_stats_snapshots (video_id, source, like_count, comment_count, fav_count, forward_count, captured_at)
+SELECT id, 'migration', like_count, comment_count, fav_count, forward_count, updated_at FROM videos
+WHERE like_count > 0 OR comment_count > 0 OR fav_count > 0 OR forward_count > 0;
+`,
+	},
+	{
+		Version:     20,
+		Description: "Add published_at to videos and indexes for author analytics",
+		Up: `
+-- 视频发布时间（Unix 秒，0 表示未知），用于计算作者发布频率
+ALTER TABLE videos ADD COLUMN published_at INTEGER NOT NULL DEFAULT 0;
+
+-- 作者统计与活跃度热力图使用的索引
+CREATE INDEX IF NOT EXISTS idx_browse_history_author_id ON browse_history(author_id, browse_time);
+CREATE INDEX IF NOT EXISTS idx_download_records_status_video ON download_records(status, video_id, file_size);
+`,
+	},
+	{
+		Version:     21,
+		Description: "Add composite indexes for keyset pagination",
+		Up: `
+-- 键集分页按 (时间, id) 定位，单列时间索引由复合索引替代
+DROP INDEX IF EXISTS idx_browse_history_browse_time;
+CREATE INDEX IF NOT EXISTS idx_browse_history_time_id ON browse_history(browse_time, id);
+DROP INDEX IF EXISTS idx_download_records_download_time;
+CREATE INDEX IF NOT EXISTS idx_download_records_time_id ON download_records(download_time, id);
+DROP INDEX IF EXISTS idx_download_queue_priority;
+CREATE INDEX IF NOT EXISTS idx_download_queue_order ON download_queue(priority DESC, added_time, id);
+DROP INDEX IF EXISTS idx_radar_logs_target_id;
+CREATE INDEX IF NOT EXISTS idx_radar_logs_target_time ON radar_logs(target_id, check_time, id);
+`,
+	},
+	{
+		Version:     22,
+		Description: "Track first seen, view count and page sources on browse_history and merge duplicates",
+		Up: `
+-- 每个视频一行：browse_time 为最后一次看到的时间，first_seen 为首次看到的时间
+ALTER TABLE browse_history ADD COLUMN first_seen DATETIME;
+ALTER TABLE browse_history ADD COLUMN view_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE browse_history ADD COLUMN sources TEXT NOT NULL DEFAULT '';
+
+UPDATE browse_history SET
+    first_seen = CASE WHEN created_at IS NOT NULL AND created_at < browse_time THEN created_at ELSE browse_time END,
+    sources = CASE
+        WHEN page_url LIKE '%/web/pages/account/like%' THEN 'like'
+        WHEN page_url LIKE '%/web/pages/account%' THEN 'account'
+        WHEN page_url LIKE '%/web/pages/profile%' THEN 'profile'
+        WHEN page_url LIKE '%/web/pages/feed%' THEN 'feed'
+        WHEN page_url LIKE '%/web/pages/home%' THEN 'home'
+        WHEN page_url LIKE '%/web/pages/s%' THEN 'search'
+        ELSE ''
+    END;
+
+-- 没有视频 ID 的记录（ID 以 browse_ 开头）每次观看都会新建一行，
+-- 按标题、作者和时长合并到同组中的一行：优先有视频 ID 的记录，其次最近浏览的
+CREATE TEMP TABLE browse_merge AS
+SELECT b.id AS id, (
+    SELECT k.id FROM browse_history k
+    WHERE k.title = b.title AND k.author = b.author AND k.duration = b.duration
+    ORDER BY k.id LIKE 'browse\_%' ESCAPE '\', k.browse_time DESC, k.id
+    LIMIT 1
+) AS keep_id
+FROM browse_history b
+WHERE b.id LIKE 'browse\_%' ESCAPE '\';
+DELETE FROM browse_merge WHERE keep_id = id;
+
+UPDATE browse_history SET
+    view_count = view_count + (
+        SELECT COUNT(*) FROM browse_merge m WHERE m.keep_id = browse_history.id
+    ),
+    first_seen = MIN(first_seen, (
+        SELECT MIN(d.first_seen) FROM browse_history d JOIN browse_merge m ON m.id = d.id
+        WHERE m.keep_id = browse_history.id
+    )),
+    sources = COALESCE((
+        SELECT group_concat(s, ',') FROM (
+            SELECT d.sources AS s FROM browse_history d
+            WHERE d.sources <> '' AND (d.id = browse_history.id
+                OR d.id IN (SELECT m.id FROM browse_merge m WHERE m.keep_id = browse_history.id))
+            GROUP BY d.sources ORDER BY MIN(d.first_seen)
+        )
+    ), '')
+WHERE id IN (SELECT keep_id FROM browse_merge);
+
+DELETE FROM browse_history WHERE id IN (SELECT id FROM browse_merge);
+DROP TABLE browse_merge;
+`,
+	},
+	{
+		Version:     23,
+		Description: "Add filter rules to radar_targets",
+		Up: `
+-- 监控目标的过滤规则（JSON，见 RadarFilter），为空表示不过滤
+ALTER TABLE radar_targets ADD COLUMN filter_rules TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		Version:     24,
+		Description: "Add backfill progress to radar_targets and mode to radar_logs",
+		Up: `
+-- 回填进度（JSON，见 RadarBackfill），为空表示没有回填
+ALTER TABLE radar_targets ADD COLUMN backfill TEXT NOT NULL DEFAULT '';
+-- 日志类型：scan 定时检测，backfill 回填
+ALTER TABLE radar_logs ADD COLUMN mode TEXT NOT NULL DEFAULT 'scan';
+`,
+	},
+	{
+		Version:     25,
+		Description: "Add cron schedule and next run time to radar_targets",
+		Up: `
+-- schedule 为 cron 表达式，为空时按 interval_minutes；next_run_time 为计划的下次检测时间（RFC3339）
+ALTER TABLE radar_targets ADD COLUMN schedule TEXT NOT NULL DEFAULT '';
+ALTER TABLE radar_targets ADD COLUMN next_run_time TEXT;
+`,
+	},
+	{
+		Version:     26,
+		Description: "Add health state and last error to radar_targets",
+		Up: `
+-- health: healthy / degraded / suspended，consecutive_failures 为连续失败次数
+ALTER TABLE radar_targets ADD COLUMN health TEXT NOT NULL DEFAULT 'healthy';
+ALTER TABLE radar_targets ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE radar_targets ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE radar_targets ADD COLUMN last_error_time TEXT;
+`,
+	},
+	{
+		Version:     27,
+		Description: "Add keyword radar targets and keyword hits",
+		Up: `
+-- type: account 监控账号，keyword 按关键词搜索视频；action: download 加入下载队列，notify 仅记录
+ALTER TABLE radar_targets ADD COLUMN type TEXT NOT NULL DEFAULT 'account';
+ALTER TABLE radar_targets ADD COLUMN keyword TEXT NOT NULL DEFAULT '';
+ALTER TABLE radar_targets ADD COLUMN action TEXT NOT NULL DEFAULT 'download';
+DROP INDEX IF EXISTS idx_radar_targets_username;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_radar_targets_username ON radar_targets(username) WHERE type = 'account';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_radar_targets_keyword ON radar_targets(keyword) WHERE type = 'keyword';
+
+-- 关键词搜到的视频，每个关键词每个视频一条，用于去重并记录视频由哪个关键词发现
+CREATE TABLE IF NOT EXISTS radar_keyword_hits (
+    keyword TEXT NOT NULL,
+    video_id TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    author_id TEXT NOT NULL DEFAULT '',
+    author_name TEXT NOT NULL DEFAULT '',
+    found_at TEXT NOT NULL,
+    PRIMARY KEY (keyword, video_id),
+    FOREIGN KEY(target_id) REFERENCES radar_targets(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_radar_keyword_hits_target ON radar_keyword_hits(target_id, found_at);
+CREATE INDEX IF NOT EXISTS idx_radar_keyword_hits_video ON radar_keyword_hits(video_id);
+`,
+	},
+	{
+		Version:     28,
+		Description: "Add group to radar_targets",
+		Up: `
+-- 分组名称，用于批量导入导出和批量暂停/恢复/删除，为空表示未分组
+ALTER TABLE radar_targets ADD COLUMN group_name TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_radar_targets_group ON radar_targets(group_name);
+`,
+	},
+	{
+		Version:     29,
+		Description: "Add radar group settings and record group on queue items and downloads",
+		Up: `
+-- 分组设置：下载子目录模板、队列优先级、默认检测间隔和过滤规则；只有名称没有设置的分组不在此表中
+CREATE TABLE IF NOT EXISTS radar_groups (
+    name TEXT PRIMARY KEY,
+    folder TEXT NOT NULL DEFAULT '',
+    priority INTEGER NOT NULL DEFAULT 0,
+    interval_minutes INTEGER NOT NULL DEFAULT 0,
+    filter_rules TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- 队列项目记录来源分组：sub_dir 为下载目录下的相对子目录（为空时按作者分目录），group_priority 优先于 priority 排序
+ALTER TABLE download_queue ADD COLUMN group_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE download_queue ADD COLUMN sub_dir TEXT NOT NULL DEFAULT '';
+ALTER TABLE download_queue ADD COLUMN group_priority INTEGER NOT NULL DEFAULT 0;
+DROP INDEX IF EXISTS idx_download_queue_order;
+CREATE INDEX IF NOT EXISTS idx_download_queue_order ON download_queue(group_priority DESC, priority DESC, added_time, id);
+ALTER TABLE download_records ADD COLUMN group_name TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_download_records_group ON download_records(group_name);
+`,
+	},
+	{
+		Version:     30,
+		Description: "Add radar live sessions",
+		Up: `
+-- 监控账号的直播场次：检测到开播时新建，检测到下播时写入 end_time，end_time 为空表示仍在直播
+CREATE TABLE IF NOT EXISTS radar_live_sessions (
+    id TEXT PRIMARY KEY,
+    target_id TEXT NOT NULL,
+    username TEXT NOT NULL,
+    author_name TEXT NOT NULL DEFAULT '',
+    live_id TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    stream_url TEXT NOT NULL DEFAULT '',
+    cover_url TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT '',
+    record_file TEXT NOT NULL DEFAULT '',
+    start_time TEXT NOT NULL,
+    end_time TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_radar_live_sessions_username ON radar_live_sessions(username, end_time);
+CREATE INDEX IF NOT EXISTS idx_radar_live_sessions_start ON radar_live_sessions(start_time DESC);
+`,
+	},
+	{
+		Version:     31,
+		Description: "Add radar account snapshots",
+		Up: `
+-- 监控账号的资料快照，每次检测追加一条，用于绘制账号增长趋势；粉丝数和作品数在返回数据中没有时为 NULL
+CREATE TABLE IF NOT EXISTS radar_account_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    target_id TEXT NOT NULL,
+    username TEXT NOT NULL,
+    nickname TEXT NOT NULL DEFAULT '',
+    avatar_url TEXT NOT NULL DEFAULT '',
+    fans_count INTEGER,
+    feeds_count INTEGER,
+    posts_7d INTEGER NOT NULL DEFAULT 0,
+    posts_30d INTEGER NOT NULL DEFAULT 0,
+    last_published_at INTEGER NOT NULL DEFAULT 0,
+    captured_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_radar_account_snapshots_username ON radar_account_snapshots(username, captured_at);
+`,
+	},
+	{
+		Version:     32,
+		Description: "Add stats repoll attempt time to videos",
+		Up: `
+-- 上次尝试重新拉取互动数据的时间（UTC RFC3339，无论成功与否），为空表示从未尝试
+ALTER TABLE videos ADD COLUMN stats_attempted_at TEXT;
+`,
+	},
 }
 
+// videoMergeSet 是写入 videos 时的合并规则：文本字段只接受非空值，统计数字只接受正数，
+// 避免信息较少的来源（如队列）覆盖已知数据
+const videoMergeSet = `
+    title = COALESCE(NULLIF(excluded.title, ''), videos.title),
+    author_id = COALESCE(NULLIF(excluded.author_id, ''), videos.author_id),
+    author_name = COALESCE(NULLIF(excluded.author_name, ''), videos.author_name),
+    cover_url = COALESCE(NULLIF(excluded.cover_url, ''), videos.cover_url),
+    duration = COALESCE(NULLIF(excluded.duration, 0), videos.duration),
+    size = COALESCE(NULLIF(excluded.size, 0), videos.size),
+    resolution = COALESCE(NULLIF(excluded.resolution, ''), videos.resolution),
+    file_format = COALESCE(NULLIF(excluded.file_format, ''), videos.file_format),
+    like_count = CASE WHEN excluded.like_count > 0 THEN excluded.like_count ELSE videos.like_count END,
+    comment_count = CASE WHEN excluded.comment_count > 0 THEN excluded.comment_count ELSE videos.comment_count END,
+    fav_count = CASE WHEN excluded.fav_count > 0 THEN excluded.fav_count ELSE videos.fav_count END,
+    forward_count = CASE WHEN excluded.forward_count > 0 THEN excluded.forward_count ELSE videos.forward_count END,
+    page_url = COALESCE(NULLIF(excluded.page_url, ''), videos.page_url),
+    updated_at = CURRENT_TIMESTAMP`
+
+// videoUpsertMergeSet 是 VideoRepository.Upsert 的合并规则，在 videoMergeSet 基础上合并后续迁移新增的列；
+// videoMergeSet 已用于迁移 18 的触发器，不能再修改
+const videoUpsertMergeSet = videoMergeSet + `,
+    nonce_id = COALESCE(NULLIF(excluded.nonce_id, ''), videos.nonce_id),
+    published_at = COALESCE(NULLIF(excluded.published_at, 0), videos.published_at)`
+
+// authorMergeSet 是写入 authors 时的合并规则
+const authorMergeSet = `
+    nickname = COALESCE(NULLIF(excluded.nickname, ''), authors.nickname),
+    avatar_url = COALESCE(NULLIF(excluded.avatar_url, ''), authors.avatar_url),
+    signature = COALESCE(NULLIF(excluded.signature, ''), authors.signature),
+    updated_at = CURRENT_TIMESTAMP`
+
+const browseVideoUpsert = `
+    INSERT INTO videos (id, title, author_id, author_name, cover_url, duration, size, resolution, file_format,
+        like_count, comment_count, fav_count, forward_count, page_url)
+    VALUES (new.id, new.title, COALESCE(new.author_id, ''), new.author, COALESCE(new.cover_url, ''),
+        COALESCE(new.duration, 0), COALESCE(new.size, 0), COALESCE(new.resolution, ''), COALESCE(new.file_format, ''),
+        COALESCE(new.like_count, 0), COALESCE(new.comment_count, 0), COALESCE(new.fav_count, 0),
+        COALESCE(new.forward_count, 0), COALESCE(new.page_url, ''))
+    ON CONFLICT(id) DO UPDATE SET ` + videoMergeSet + `;
+    INSERT INTO authors (id, nickname) SELECT new.author_id, new.author WHERE COALESCE(new.author_id, '') <> ''
+    ON CONFLICT(id) DO UPDATE SET nickname = COALESCE(NULLIF(excluded.nickname, ''), authors.nickname), updated_at = CURRENT_TIMESTAMP;`
+
+const downloadVideoUpsert = `
+    INSERT INTO videos (id, title, author_id, author_name, cover_url, duration, size, resolution,
+        like_count, comment_count, fav_count, forward_count)
+    SELECT new.video_id, new.title, COALESCE(new.author_id, ''), new.author, COALESCE(new.cover_url, ''),
+        COALESCE(new.duration, 0), COALESCE(new.file_size, 0), COALESCE(new.resolution, ''),
+        COALESCE(new.like_count, 0), COALESCE(new.comment_count, 0), COALESCE(new.fav_count, 0), COALESCE(new.forward_count, 0)
+    WHERE new.video_id <> ''
+    ON CONFLICT(id) DO UPDATE SET ` + videoMergeSet + `;`
+
+const queueVideoUpsert = `
+    INSERT INTO videos (id, title, author_id, author_name, cover_url, duration, size, resolution)
+    SELECT new.video_id, new.title, COALESCE(new.author_id, ''), new.author, COALESCE(new.cover_url, ''),
+        COALESCE(new.duration, 0), COALESCE(new.total_size, 0), COALESCE(new.resolution, '')
+    WHERE new.video_id <> ''
+    ON CONFLICT(id) DO UPDATE SET ` + videoMergeSet + `;`
+
 // runMigrations 执行所有待处理的迁移
 func runMigrations() error {
 	// 如果不存在则创建迁移表
@@ -298,16 +820,20 @@ func runMigrations() error {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
-	// 获取当前版本
-	var currentVersion int
-	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&currentVersion)
+	// 获取已应用的版本（依赖可选模块的迁移可能被跳过，不能只看最大版本号）
+	applied, err := appliedVersions()
 	if err != nil {
 		return fmt.Errorf("failed to get current schema version: %w", err)
 	}
 
 	// 运行待处理的迁移
 	for _, m := range migrations {
-		if m.Version > currentVersion {
+		if !applied[m.Version] {
+			if m.Requires != "" && !hasModule(m.Requires) {
+				fmt.Printf("Skipped migration %d: SQLite module %s not available in this build\n", m.Version, m.Requires)
+				continue
+			}
+
 			// 开启事务
 			tx, err := db.Begin()
 			if err != nil {
@@ -340,6 +866,31 @@ func runMigrations() error {
 	return nil
 }
 
+func appliedVersions() (map[int]bool, error) {
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// hasModule 检查当前 SQLite 构建是否包含可选模块（fts5 需要 sqlite_fts5 构建标签）
+func hasModule(name string) bool {
+	var used int
+	err := db.QueryRow("SELECT sqlite_compileoption_used(?)", "ENABLE_"+strings.ToUpper(name)).Scan(&used)
+	return err == nil && used == 1
+}
+
 // GetSchemaVersion 返回当前架构版本
 func GetSchemaVersion() (int, error) {
 	var version int
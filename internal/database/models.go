@@ -23,6 +23,9 @@ type BrowseRecord struct {
 	FavCount     int64     `json:"favCount"`
 	ForwardCount int64     `json:"forwardCount"`
 	PageURL      string    `json:"pageUrl"`
+	FirstSeen    time.Time `json:"firstSeen"` // 首次看到的时间，BrowseTime 为最后一次看到的时间
+	ViewCount    int64     `json:"viewCount"` // 累计观看次数
+	Sources      []string  `json:"sources"`   // 看到过该视频的页面来源，见 PageSource
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
@@ -33,6 +36,7 @@ type DownloadRecord struct {
 	VideoID      string    `json:"videoId"`
 	Title        string    `json:"title"`
 	Author       string    `json:"author"`
+	AuthorID     string    `json:"authorId"`
 	CoverURL     string    `json:"coverUrl"` // 封面图片 URL
 	Duration     int64     `json:"duration"`
 	FileSize     int64     `json:"fileSize"`
@@ -46,6 +50,7 @@ type DownloadRecord struct {
 	CommentCount int64     `json:"commentCount"`
 	ForwardCount int64     `json:"forwardCount"`
 	FavCount     int64     `json:"favCount"`
+	Group        string    `json:"group"` // 来源分组，为空表示未分组
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
@@ -64,6 +69,7 @@ type QueueItem struct {
 	VideoID         string    `json:"videoId"`
 	Title           string    `json:"title"`
 	Author          string    `json:"author"`
+	AuthorID        string    `json:"authorId"`
 	CoverURL        string    `json:"coverUrl"` // 封面图片 URL
 	VideoURL        string    `json:"videoUrl"`
 	DecryptKey      string    `json:"decryptKey"` // 加密视频的解密密钥
@@ -73,6 +79,9 @@ type QueueItem struct {
 	DownloadedSize  int64     `json:"downloadedSize"`
 	Status          string    `json:"status"` // pending, downloading, paused, completed, failed
 	Priority        int       `json:"priority"`
+	GroupPriority   int       `json:"groupPriority"` // 来源分组的优先级，排序时先于 Priority
+	Group           string    `json:"group"`         // 来源分组，为空表示未分组
+	SubDir          string    `json:"subDir"`        // 下载目录下的相对子目录（以 / 分隔），为空时按作者分目录
 	AddedTime       time.Time `json:"addedTime"`
 	StartTime       time.Time `json:"startTime"`
 	Speed           int64     `json:"speed"`
@@ -126,24 +135,32 @@ type PaginationParams struct {
 	PageSize int    `json:"pageSize"`
 	SortBy   string `json:"sortBy"`
 	SortDesc bool   `json:"sortDesc"`
+	// Keyset 为 true 时按游标分页：忽略 Page，从 Cursor 之后继续取（空游标表示第一页），
+	// 固定按时间列和 id 排序，SortDesc 决定方向
+	Keyset    bool   `json:"keyset"`
+	Cursor    string `json:"cursor"`
+	SkipTotal bool   `json:"skipTotal"` // 不统计总数，结果的 Total 为 -1
 }
 
-// FilterParams 表示下载记录的过滤参数
+// FilterParams 表示浏览记录和下载记录的过滤参数
 type FilterParams struct {
 	PaginationParams
-	StartDate *time.Time `json:"startDate"`
-	EndDate   *time.Time `json:"endDate"`
-	Status    string     `json:"status"`
-	Query     string     `json:"query"`
+	StartDate *time.Time    `json:"startDate"`
+	EndDate   *time.Time    `json:"endDate"`
+	Status    string        `json:"status"`
+	Query     string        `json:"query"`            // 全文检索词（已去除结构化条件）
+	Filter    *RecordFilter `json:"filter,omitempty"` // 结构化条件，见 ParseRecordQuery
 }
 
 // PagedResult 表示分页结果
 type PagedResult[T any] struct {
-	Items      []T   `json:"items"`
-	Total      int64 `json:"total"`
-	Page       int   `json:"page"`
-	PageSize   int   `json:"pageSize"`
-	TotalPages int   `json:"totalPages"`
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"pageSize"`
+	TotalPages int    `json:"totalPages"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"` // 游标分页时下一页的游标
 }
 
 // NewPagedResult 创建一个新的分页结果
@@ -158,6 +175,7 @@ func NewPagedResult[T any](items []T, total int64, page, pageSize int) *PagedRes
 		Page:       page,
 		PageSize:   pageSize,
 		TotalPages: totalPages,
+		HasMore:    int64(page*pageSize) < total,
 	}
 }
 
@@ -171,11 +189,24 @@ type RadarLog struct {
 	Status       string    `json:"status"` // success 或 error
 	ErrorMessage string    `json:"error_message"`
 	VideoList    string    `json:"video_list"` // JSON 数组，存储每个视频的详情摘要
+	Mode         string    `json:"mode"`       // scan 定时检测，backfill 回填
 }
 
+// 雷达日志类型
+const (
+	RadarLogScan     = "scan"
+	RadarLogBackfill = "backfill"
+)
+
 // RadarVideoSummary 单次扫描中某个视频的摘要信息
 type RadarVideoSummary struct {
 	VideoID string `json:"video_id"`
 	Title   string `json:"title"`
-	IsNew   bool   `json:"is_new"` // true=新视频并已加入队列，false=已存在
+	IsNew   bool   `json:"is_new"` // true=新视频，false=已存在
+	// Skipped 表示新视频未加入队列（不符合过滤规则或无法提取地址），SkipReason 为原因
+	Skipped    bool   `json:"skipped,omitempty"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	// 关键词目标的搜索结果来自不同作者，记录作者和命中的关键词
+	Author  string `json:"author,omitempty"`
+	Keyword string `json:"keyword,omitempty"`
 }
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 分页查询
+//
+// 默认使用 LIMIT/OFFSET 分页；PaginationParams.Keyset 为 true 时改为游标（键集）分页，
+// 按 (时间, id) 定位上一页的最后一条记录，深翻页不需要扫描并丢弃前面的行。
+// 两种方式都多取一条记录来判断 HasMore，SkipTotal 时不执行 COUNT(*)。
+
+// ErrInvalidCursor 表示分页游标无法解析
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor 是游标的内容：上一页最后一条记录的时间和 ID，下载队列还包含分组优先级和优先级
+type pageCursor struct {
+	Time          time.Time `json:"t"`
+	ID            string    `json:"id"`
+	Priority      int       `json:"p,omitempty"`
+	GroupPriority int       `json:"g,omitempty"`
+}
+
+// encodeCursor 将游标编码为不透明字符串
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析游标，空字符串表示第一页
+func decodeCursor(s string) (*pageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// normalizePage 填充分页参数的默认值
+func normalizePage(params *PaginationParams) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 20
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+}
+
+// pageOrder 定义游标分页的排序键
+type pageOrder struct {
+	orderBy func(desc bool) string                                 // ORDER BY 子句内容
+	after   func(c *pageCursor, desc bool) (string, []interface{}) // 位于游标之后的条件
+}
+
+// timeOrder 按 (时间列, id) 排序；bind 把游标时间转换为与列存储格式一致的 SQL 参数
+func timeOrder(column string, bind func(time.Time) interface{}) pageOrder {
+	return pageOrder{
+		orderBy: func(desc bool) string {
+			dir := sortDirection(desc)
+			return column + " " + dir + ", id " + dir
+		},
+		after: func(c *pageCursor, desc bool) (string, []interface{}) {
+			op := ">"
+			if desc {
+				op = "<"
+			}
+			return fmt.Sprintf("(%s, id) %s (?, ?)", column, op), []interface{}{bind(c.Time), c.ID}
+		},
+	}
+}
+
+// bindTime 按驱动的默认格式写入时间，用于以 time.Time 写入的列
+func bindTime(t time.Time) interface{} {
+	return t
+}
+
+func sortDirection(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// pageQuery 描述一次分页查询
+type pageQuery[T any] struct {
+	table   string        // 表名
+	columns string        // SELECT 的列
+	where   string        // 过滤条件（"WHERE ..." 或空）
+	args    []interface{} // 过滤条件的参数
+	sortBy  string        // 偏移分页的排序列，已校验；为空时使用 order 的排序
+	order   pageOrder
+	noun    string // 错误信息中的记录名称
+	scan    func(*sql.Rows) (*T, error)
+	cursor  func(*T) pageCursor
+}
+
+// run 执行分页查询，params 会被填充默认值
+func (q *pageQuery[T]) run(db *sql.DB, params *PaginationParams) (*PagedResult[T], error) {
+	normalizePage(params)
+
+	total := int64(-1)
+	if !params.SkipTotal {
+		if err := db.QueryRow("SELECT COUNT(*) FROM "+q.table+" "+q.where, q.args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", q.noun, err)
+		}
+	}
+
+	where, args := q.where, append([]interface{}{}, q.args...)
+	var orderBy string
+	offset := 0
+	if params.Keyset {
+		after, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if after != nil {
+			cond, condArgs := q.order.after(after, params.SortDesc)
+			if where == "" {
+				where = "WHERE " + cond
+			} else {
+				where += " AND " + cond
+			}
+			args = append(args, condArgs...)
+		}
+		orderBy = q.order.orderBy(params.SortDesc)
+		params.Page = 0
+	} else {
+		if q.sortBy != "" {
+			dir := sortDirection(params.SortDesc)
+			orderBy = q.sortBy + " " + dir + ", id " + dir
+		} else {
+			orderBy = q.order.orderBy(params.SortDesc)
+		}
+		offset = (params.Page - 1) * params.PageSize
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY %s LIMIT ? OFFSET ?", q.columns, q.table, where, orderBy)
+	rows, err := db.Query(query, append(args, params.PageSize+1, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", q.noun, err)
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		item, err := q.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", q.noun, err)
+	}
+
+	hasMore := len(items) > params.PageSize
+	if hasMore {
+		items = items[:params.PageSize]
+	}
+	result := NewPagedResult(items, total, params.Page, params.PageSize)
+	result.HasMore = hasMore
+	if params.Keyset && hasMore {
+		result.NextCursor = encodeCursor(q.cursor(&items[len(items)-1]))
+	}
+	return result, nil
+}
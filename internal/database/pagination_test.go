@@ -0,0 +1,190 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// collectPages 按游标逐页读取，返回所有 ID
+func collectPages[T any](t *testing.T, fetch func(params *PaginationParams) (*PagedResult[T], error), id func(*T) string, params PaginationParams) []string {
+	t.Helper()
+	params.Keyset = true
+	var ids []string
+	for i := 0; i < 100; i++ {
+		result, err := fetch(&params)
+		if err != nil {
+			t.Fatalf("fetch page: %v", err)
+		}
+		for j := range result.Items {
+			ids = append(ids, id(&result.Items[j]))
+		}
+		if !result.HasMore {
+			if result.NextCursor != "" {
+				t.Errorf("expected no cursor on the last page")
+			}
+			return ids
+		}
+		params.Cursor = result.NextCursor
+	}
+	t.Fatal("pagination did not terminate")
+	return nil
+}
+
+func TestBrowseKeysetPagination(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewBrowseHistoryRepository()
+	base := time.Date(2025, 6, 1, 8, 0, 0, 0, time.Local)
+	for i := 0; i < 25; i++ {
+		// 每三条共用一个浏览时间，验证同一时间下按 id 排序
+		record := &BrowseRecord{
+			ID:         fmt.Sprintf("b%02d", i),
+			Title:      fmt.Sprintf("video %d", i),
+			Author:     "a",
+			BrowseTime: base.Add(time.Duration(i/3) * time.Minute),
+		}
+		if i%2 == 0 {
+			record.Title = "猫咪 " + record.Title
+		}
+		if err := repo.Create(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fetch := func(params *PaginationParams) (*PagedResult[BrowseRecord], error) { return repo.List(params) }
+	id := func(r *BrowseRecord) string { return r.ID }
+
+	for _, desc := range []bool{true, false} {
+		ids := collectPages(t, fetch, id, PaginationParams{PageSize: 10, SortDesc: desc})
+		if len(ids) != 25 {
+			t.Fatalf("desc=%v: expected 25 records, got %d", desc, len(ids))
+		}
+
+		// 与偏移分页的结果一致
+		var offsetIDs []string
+		for page := 1; page <= 3; page++ {
+			result, err := repo.List(&PaginationParams{Page: page, PageSize: 10, SortBy: "browse_time", SortDesc: desc})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.HasMore != (page < 3) {
+				t.Errorf("page %d: unexpected hasMore %v", page, result.HasMore)
+			}
+			for _, r := range result.Items {
+				offsetIDs = append(offsetIDs, r.ID)
+			}
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(offsetIDs) {
+			t.Errorf("desc=%v: keyset order %v differs from offset order %v", desc, ids, offsetIDs)
+		}
+	}
+
+	// 带过滤条件并跳过总数
+	params := &FilterParams{PaginationParams: PaginationParams{PageSize: 5, SortDesc: true, Keyset: true, SkipTotal: true}, Query: "猫咪"}
+	var matched int
+	for {
+		result, err := repo.ListFiltered(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Total != -1 {
+			t.Errorf("expected total -1 when skipping count, got %d", result.Total)
+		}
+		matched += len(result.Items)
+		if !result.HasMore {
+			break
+		}
+		params.Cursor = result.NextCursor
+	}
+	if matched != 13 {
+		t.Errorf("expected 13 matching records, got %d", matched)
+	}
+
+	if _, err := repo.List(&PaginationParams{Keyset: true, Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestQueueAndRadarLogPagination(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	queue := NewQueueRepository()
+	added := time.Date(2025, 6, 1, 8, 0, 0, 0, time.Local)
+	var expected []string
+	for _, priority := range []int{2, 1, 0} {
+		for i := 0; i < 4; i++ {
+			item := &QueueItem{
+				ID:        fmt.Sprintf("q%d%d", priority, i),
+				VideoID:   fmt.Sprintf("v%d%d", priority, i),
+				Title:     "t",
+				Status:    "pending",
+				Priority:  priority,
+				AddedTime: added.Add(time.Duration(i/2) * time.Second),
+			}
+			if err := queue.Add(item); err != nil {
+				t.Fatal(err)
+			}
+			expected = append(expected, item.ID)
+		}
+	}
+
+	// 高优先级分组的项目排在最前，即使优先级更低、添加更晚
+	var grouped []string
+	for i := 0; i < 3; i++ {
+		item := &QueueItem{
+			ID:            fmt.Sprintf("g%d", i),
+			VideoID:       fmt.Sprintf("g%d", i),
+			Title:         "t",
+			Status:        "pending",
+			GroupPriority: 10,
+			Group:         "竞品",
+			SubDir:        "竞品/老王",
+			AddedTime:     added.Add(time.Duration(i) * time.Minute),
+		}
+		if err := queue.Add(item); err != nil {
+			t.Fatal(err)
+		}
+		grouped = append(grouped, item.ID)
+	}
+	expected = append(grouped, expected...)
+
+	ids := collectPages(t, func(params *PaginationParams) (*PagedResult[QueueItem], error) {
+		return queue.ListPage(params, "", "")
+	}, func(item *QueueItem) string { return item.ID }, PaginationParams{PageSize: 5})
+	if fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("unexpected queue order %v, want %v", ids, expected)
+	}
+	if next, err := queue.GetNextPending(); err != nil || next == nil || next.ID != "g0" || next.SubDir != "竞品/老王" {
+		t.Errorf("unexpected next pending item %+v %v", next, err)
+	}
+	page, err := queue.ListPage(&PaginationParams{PageSize: 10}, "pending", "竞品")
+	if err != nil || page.Total != 3 || page.Items[0].Group != "竞品" {
+		t.Errorf("unexpected group page %+v %v", page, err)
+	}
+	if n, err := queue.UpdateGroupPriority("竞品", -1); err != nil || n != 3 {
+		t.Errorf("UpdateGroupPriority: %d %v", n, err)
+	}
+	if next, _ := queue.GetNextPending(); next == nil || next.ID != "q20" {
+		t.Errorf("expected lowered group to move behind, got %+v", next)
+	}
+
+	radar := NewRadarRepository()
+	if err := radar.Add(&RadarTarget{ID: "t1", Username: "u1", IntervalMinutes: 60, Status: RadarStatusActive}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := radar.AddLog(&RadarLog{ID: fmt.Sprintf("l%d", i), TargetID: "t1", CheckTime: added.Add(time.Duration(i/2) * time.Hour), Status: "success"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logIDs := collectPages(t, func(params *PaginationParams) (*PagedResult[RadarLog], error) {
+		return radar.GetLogsPage("t1", params)
+	}, func(log *RadarLog) string { return log.ID }, PaginationParams{PageSize: 3, SortDesc: true})
+	if want := "[l6 l5 l4 l3 l2 l1 l0]"; fmt.Sprint(logIDs) != want {
+		t.Errorf("unexpected radar log order %v, want %s", logIDs, want)
+	}
+}
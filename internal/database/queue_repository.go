@@ -17,7 +17,7 @@ func NewQueueRepository() *QueueRepository {
 	return &QueueRepository{db: GetDB()}
 }
 
-// Add 插入新的队列项目
+// Add 插入新的队列项目，未指定作者 ID 时从 videos 表补全
 func (r *QueueRepository) Add(item *QueueItem) error {
 	now := time.Now()
 	item.CreatedAt = now
@@ -25,18 +25,19 @@ func (r *QueueRepository) Add(item *QueueItem) error {
 
 	query := `
 		INSERT INTO download_queue (
-			id, video_id, title, author, cover_url, video_url, decrypt_key, duration, resolution, total_size, downloaded_size,
+			id, video_id, title, author, author_id, cover_url, video_url, decrypt_key, duration, resolution, total_size, downloaded_size,
 			status, priority, added_time, start_time, speed, chunk_size,
 			chunks_total, chunks_completed, retry_count, error_message,
-			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			group_name, sub_dir, group_priority, created_at, updated_at
+		) VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), (SELECT author_id FROM videos WHERE id = ?), ''),
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := r.db.Exec(query,
-		item.ID, item.VideoID, item.Title, item.Author, item.CoverURL, item.VideoURL, item.DecryptKey,
+		item.ID, item.VideoID, item.Title, item.Author, item.AuthorID, item.VideoID, item.CoverURL, item.VideoURL, item.DecryptKey,
 		item.Duration, item.Resolution, item.TotalSize, item.DownloadedSize, item.Status, item.Priority,
 		item.AddedTime, item.StartTime, item.Speed, item.ChunkSize,
 		item.ChunksTotal, item.ChunksCompleted, item.RetryCount,
-		item.ErrorMessage, item.CreatedAt, item.UpdatedAt,
+		item.ErrorMessage, item.Group, item.SubDir, item.GroupPriority, item.CreatedAt, item.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to add queue item: %w", err)
@@ -47,11 +48,11 @@ func (r *QueueRepository) Add(item *QueueItem) error {
 // GetByID 根据 ID 获取队列项目
 func (r *QueueRepository) GetByID(id string) (*QueueItem, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
 			COALESCE(duration, 0) as duration, COALESCE(resolution, '') as resolution, total_size, downloaded_size,
 			status, priority, added_time, start_time, speed, chunk_size,
 			chunks_total, chunks_completed, retry_count, error_message,
-			created_at, updated_at
+			group_name, sub_dir, group_priority, created_at, updated_at
 		FROM download_queue WHERE id = ?
 	`
 	item := &QueueItem{}
@@ -61,11 +62,11 @@ func (r *QueueRepository) GetByID(id string) (*QueueItem, error) {
 	var coverURL sql.NullString
 	var resolution sql.NullString
 	err := r.db.QueryRow(query, id).Scan(
-		&item.ID, &item.VideoID, &item.Title, &item.Author, &coverURL, &item.VideoURL, &decryptKey,
+		&item.ID, &item.VideoID, &item.Title, &item.Author, &item.AuthorID, &coverURL, &item.VideoURL, &decryptKey,
 		&item.Duration, &resolution, &item.TotalSize, &item.DownloadedSize, &item.Status, &item.Priority,
 		&item.AddedTime, &startTime, &item.Speed, &item.ChunkSize,
 		&item.ChunksTotal, &item.ChunksCompleted, &item.RetryCount,
-		&errorMessage, &item.CreatedAt, &item.UpdatedAt,
+		&errorMessage, &item.Group, &item.SubDir, &item.GroupPriority, &item.CreatedAt, &item.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -86,11 +87,11 @@ func (r *QueueRepository) GetByID(id string) (*QueueItem, error) {
 // GetByVideoID 根据 VideoID 获取队列项目
 func (r *QueueRepository) GetByVideoID(videoID string) (*QueueItem, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
 			COALESCE(duration, 0) as duration, COALESCE(resolution, '') as resolution, total_size, downloaded_size,
 			status, priority, added_time, start_time, speed, chunk_size,
 			chunks_total, chunks_completed, retry_count, error_message,
-			created_at, updated_at
+			group_name, sub_dir, group_priority, created_at, updated_at
 		FROM download_queue WHERE video_id = ? LIMIT 1
 	`
 	item := &QueueItem{}
@@ -100,11 +101,11 @@ func (r *QueueRepository) GetByVideoID(videoID string) (*QueueItem, error) {
 	var coverURL sql.NullString
 	var resolution sql.NullString
 	err := r.db.QueryRow(query, videoID).Scan(
-		&item.ID, &item.VideoID, &item.Title, &item.Author, &coverURL, &item.VideoURL, &decryptKey,
+		&item.ID, &item.VideoID, &item.Title, &item.Author, &item.AuthorID, &coverURL, &item.VideoURL, &decryptKey,
 		&item.Duration, &resolution, &item.TotalSize, &item.DownloadedSize, &item.Status, &item.Priority,
 		&item.AddedTime, &startTime, &item.Speed, &item.ChunkSize,
 		&item.ChunksTotal, &item.ChunksCompleted, &item.RetryCount,
-		&errorMessage, &item.CreatedAt, &item.UpdatedAt,
+		&errorMessage, &item.Group, &item.SubDir, &item.GroupPriority, &item.CreatedAt, &item.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -198,13 +199,13 @@ func (r *QueueRepository) Clear() error {
 // List 获取按优先级和添加时间排序的所有队列项目
 func (r *QueueRepository) List() ([]QueueItem, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
 			COALESCE(duration, 0) as duration, total_size, downloaded_size,
 			status, priority, added_time, start_time, speed, chunk_size,
 			chunks_total, chunks_completed, retry_count, error_message,
-			created_at, updated_at
+			group_name, sub_dir, group_priority, created_at, updated_at
 		FROM download_queue
-		ORDER BY priority DESC, added_time ASC
+		ORDER BY group_priority DESC, priority DESC, added_time ASC
 	`
 
 	rows, err := r.db.Query(query)
@@ -221,11 +222,11 @@ func (r *QueueRepository) List() ([]QueueItem, error) {
 		var decryptKey sql.NullString
 		var coverURL sql.NullString
 		err := rows.Scan(
-			&item.ID, &item.VideoID, &item.Title, &item.Author, &coverURL, &item.VideoURL, &decryptKey,
+			&item.ID, &item.VideoID, &item.Title, &item.Author, &item.AuthorID, &coverURL, &item.VideoURL, &decryptKey,
 			&item.Duration, &item.TotalSize, &item.DownloadedSize, &item.Status, &item.Priority,
 			&item.AddedTime, &startTime, &item.Speed, &item.ChunkSize,
 			&item.ChunksTotal, &item.ChunksCompleted, &item.RetryCount,
-			&errorMessage, &item.CreatedAt, &item.UpdatedAt,
+			&errorMessage, &item.Group, &item.SubDir, &item.GroupPriority, &item.CreatedAt, &item.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan queue item: %w", err)
@@ -249,14 +250,14 @@ func (r *QueueRepository) List() ([]QueueItem, error) {
 // ListByStatus 获取指定状态的队列项目
 func (r *QueueRepository) ListByStatus(status string) ([]QueueItem, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
 			COALESCE(duration, 0) as duration, total_size, downloaded_size,
 			status, priority, added_time, start_time, speed, chunk_size,
 			chunks_total, chunks_completed, retry_count, error_message,
-			created_at, updated_at
+			group_name, sub_dir, group_priority, created_at, updated_at
 		FROM download_queue
 		WHERE status = ?
-		ORDER BY priority DESC, added_time ASC
+		ORDER BY group_priority DESC, priority DESC, added_time ASC
 	`
 
 	rows, err := r.db.Query(query, status)
@@ -273,11 +274,11 @@ func (r *QueueRepository) ListByStatus(status string) ([]QueueItem, error) {
 		var decryptKey sql.NullString
 		var coverURL sql.NullString
 		err := rows.Scan(
-			&item.ID, &item.VideoID, &item.Title, &item.Author, &coverURL, &item.VideoURL, &decryptKey,
+			&item.ID, &item.VideoID, &item.Title, &item.Author, &item.AuthorID, &coverURL, &item.VideoURL, &decryptKey,
 			&item.Duration, &item.TotalSize, &item.DownloadedSize, &item.Status, &item.Priority,
 			&item.AddedTime, &startTime, &item.Speed, &item.ChunkSize,
 			&item.ChunksTotal, &item.ChunksCompleted, &item.RetryCount,
-			&errorMessage, &item.CreatedAt, &item.UpdatedAt,
+			&errorMessage, &item.Group, &item.SubDir, &item.GroupPriority, &item.CreatedAt, &item.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan queue item: %w", err)
@@ -298,6 +299,77 @@ func (r *QueueRepository) ListByStatus(status string) ([]QueueItem, error) {
 	return items, nil
 }
 
+// queueItemColumns 是查询队列项目时 SELECT 的列，与 scanQueueItem 对应
+const queueItemColumns = `id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key,
+	COALESCE(duration, 0) as duration, total_size, downloaded_size,
+	status, priority, added_time, start_time, speed, chunk_size,
+	chunks_total, chunks_completed, retry_count, error_message,
+	group_name, sub_dir, group_priority, created_at, updated_at`
+
+// queueOrder 是队列的固定顺序：分组优先级、优先级从高到低，同优先级按添加时间先后
+var queueOrder = pageOrder{
+	orderBy: func(bool) string {
+		return "group_priority DESC, priority DESC, added_time ASC, id ASC"
+	},
+	after: func(c *pageCursor, _ bool) (string, []interface{}) {
+		return "(group_priority < ? OR (group_priority = ? AND (priority < ? OR (priority = ? AND (added_time, id) > (?, ?)))))",
+			[]interface{}{c.GroupPriority, c.GroupPriority, c.Priority, c.Priority, c.Time, c.ID}
+	},
+}
+
+// ListPage 按队列顺序分页获取队列项目，status、group 为空时不过滤状态和分组
+func (r *QueueRepository) ListPage(params *PaginationParams, status, group string) (*PagedResult[QueueItem], error) {
+	query := &pageQuery[QueueItem]{
+		table:   "download_queue",
+		columns: queueItemColumns,
+		order:   queueOrder,
+		noun:    "queue items",
+		scan:    scanQueueItem,
+		cursor: func(item *QueueItem) pageCursor {
+			return pageCursor{Time: item.AddedTime, ID: item.ID, Priority: item.Priority, GroupPriority: item.GroupPriority}
+		},
+	}
+	var conds []string
+	if status != "" {
+		conds = append(conds, "status = ?")
+		query.args = append(query.args, status)
+	}
+	if group != "" {
+		conds = append(conds, "group_name = ?")
+		query.args = append(query.args, group)
+	}
+	if len(conds) > 0 {
+		query.where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	return query.run(r.db, params)
+}
+
+// scanQueueItem 扫描一行队列项目
+func scanQueueItem(rows *sql.Rows) (*QueueItem, error) {
+	item := &QueueItem{}
+	var startTime sql.NullTime
+	var errorMessage sql.NullString
+	var decryptKey sql.NullString
+	var coverURL sql.NullString
+	err := rows.Scan(
+		&item.ID, &item.VideoID, &item.Title, &item.Author, &item.AuthorID, &coverURL, &item.VideoURL, &decryptKey,
+		&item.Duration, &item.TotalSize, &item.DownloadedSize, &item.Status, &item.Priority,
+		&item.AddedTime, &startTime, &item.Speed, &item.ChunkSize,
+		&item.ChunksTotal, &item.ChunksCompleted, &item.RetryCount,
+		&errorMessage, &item.Group, &item.SubDir, &item.GroupPriority, &item.CreatedAt, &item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan queue item: %w", err)
+	}
+	if startTime.Valid {
+		item.StartTime = startTime.Time
+	}
+	item.CoverURL = coverURL.String
+	item.ErrorMessage = errorMessage.String
+	item.DecryptKey = decryptKey.String
+	return item, nil
+}
+
 // UpdateStatus 更新队列项目的状态
 func (r *QueueRepository) UpdateStatus(id string, status string) error {
 	query := "UPDATE download_queue SET status = ?, updated_at = ? WHERE id = ?"
@@ -312,6 +384,18 @@ func (r *QueueRepository) UpdateStatus(id string, status string) error {
 	return nil
 }
 
+// UpdateGroupPriority 更新分组内尚未完成的队列项目的分组优先级，返回更新的数量
+func (r *QueueRepository) UpdateGroupPriority(group string, priority int) (int64, error) {
+	result, err := r.db.Exec(
+		"UPDATE download_queue SET group_priority = ?, updated_at = ? WHERE group_name = ? AND status != ? AND group_priority != ?",
+		priority, time.Now(), group, QueueStatusCompleted, priority,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update queue group priority: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // UpdateProgress 更新队列项目的下载进度
 func (r *QueueRepository) UpdateProgress(id string, downloadedSize int64, chunksCompleted int, speed int64) error {
 	query := `
@@ -410,14 +494,14 @@ func (r *QueueRepository) GetQueueStats() (total, pending, downloading, paused,
 // GetNextPending 获取下一个待处理的队列项目
 func (r *QueueRepository) GetNextPending() (*QueueItem, error) {
 	query := `
-		SELECT id, video_id, title, author, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
+		SELECT id, video_id, title, author, COALESCE(author_id, '') as author_id, COALESCE(cover_url, '') as cover_url, video_url, decrypt_key, 
 			COALESCE(duration, 0) as duration, total_size, downloaded_size,
 			status, priority, added_time, start_time, speed, chunk_size,
 			chunks_total, chunks_completed, retry_count, error_message,
-			created_at, updated_at
+			group_name, sub_dir, group_priority, created_at, updated_at
 		FROM download_queue
 		WHERE status = ?
-		ORDER BY priority DESC, added_time ASC
+		ORDER BY group_priority DESC, priority DESC, added_time ASC
 		LIMIT 1
 	`
 	item := &QueueItem{}
@@ -426,11 +510,11 @@ func (r *QueueRepository) GetNextPending() (*QueueItem, error) {
 	var decryptKey sql.NullString
 	var coverURL sql.NullString
 	err := r.db.QueryRow(query, QueueStatusPending).Scan(
-		&item.ID, &item.VideoID, &item.Title, &item.Author, &coverURL, &item.VideoURL, &decryptKey,
+		&item.ID, &item.VideoID, &item.Title, &item.Author, &item.AuthorID, &coverURL, &item.VideoURL, &decryptKey,
 		&item.Duration, &item.TotalSize, &item.DownloadedSize, &item.Status, &item.Priority,
 		&item.AddedTime, &startTime, &item.Speed, &item.ChunkSize,
 		&item.ChunksTotal, &item.ChunksCompleted, &item.RetryCount,
-		&errorMessage, &item.CreatedAt, &item.UpdatedAt,
+		&errorMessage, &item.Group, &item.SubDir, &item.GroupPriority, &item.CreatedAt, &item.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 雷达过滤规则
+//
+// 雷达只把符合规则的新视频加入下载队列。规则分为全局规则（settings 表的 radar_filter 键）、
+// 分组规则（radar_groups.filter_rules）和监控目标自身的规则（radar_targets.filter_rules），
+// 都要满足；未设置的条件不做限制。
+
+// 媒体类型
+const (
+	RadarMediaVideo = "video"
+	RadarMediaImage = "image"
+)
+
+// RadarFilter 表示一组雷达过滤规则，以 JSON 保存
+type RadarFilter struct {
+	TitleInclude  string `json:"title_include,omitempty"`  // 正则，标题必须匹配
+	TitleExclude  string `json:"title_exclude,omitempty"`  // 正则，标题匹配时跳过
+	MinDuration   int64  `json:"min_duration,omitempty"`   // 最短时长 (秒)
+	MaxDuration   int64  `json:"max_duration,omitempty"`   // 最长时长 (秒)
+	MinSize       int64  `json:"min_size,omitempty"`       // 最小文件大小 (字节)
+	MinResolution int    `json:"min_resolution,omitempty"` // 最低分辨率，按短边像素计，如 720
+	MediaType     string `json:"media_type,omitempty"`     // video 或 image，为空表示不限
+}
+
+// RadarMedia 是过滤规则检查的视频信息，取自 feed_list 返回的 objectDesc
+type RadarMedia struct {
+	Title      string
+	MediaType  string // video 或 image，未知时为空
+	Duration   int64  // 秒
+	Size       int64  // 字节
+	Resolution string // 宽x高
+}
+
+// IsEmpty 判断是否没有设置任何规则
+func (f *RadarFilter) IsEmpty() bool {
+	return f == nil || *f == RadarFilter{}
+}
+
+// Validate 检查规则是否有效，并去除正则两端的空白
+func (f *RadarFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	f.TitleInclude = strings.TrimSpace(f.TitleInclude)
+	f.TitleExclude = strings.TrimSpace(f.TitleExclude)
+	for _, expr := range []string{f.TitleInclude, f.TitleExclude} {
+		if _, err := compileRadarPattern(expr); err != nil {
+			return fmt.Errorf("invalid title pattern %q: %w", expr, err)
+		}
+	}
+	if f.MinDuration < 0 || f.MaxDuration < 0 || f.MinSize < 0 || f.MinResolution < 0 {
+		return fmt.Errorf("filter values must not be negative")
+	}
+	if f.MaxDuration > 0 && f.MinDuration > f.MaxDuration {
+		return fmt.Errorf("min duration is greater than max duration")
+	}
+	if f.MediaType != "" && f.MediaType != RadarMediaVideo && f.MediaType != RadarMediaImage {
+		return fmt.Errorf("media type must be %q or %q", RadarMediaVideo, RadarMediaImage)
+	}
+	return nil
+}
+
+// Check 按规则检查视频，符合时返回空字符串，否则返回跳过原因。
+// 视频缺少某项信息（如分辨率未知）时不按该项过滤。
+func (f *RadarFilter) Check(m *RadarMedia) string {
+	if f.IsEmpty() {
+		return ""
+	}
+	if f.MediaType != "" && m.MediaType != "" && m.MediaType != f.MediaType {
+		return fmt.Sprintf("媒体类型为 %s，规则要求 %s", m.MediaType, f.MediaType)
+	}
+	if f.TitleInclude != "" {
+		if re, err := compileRadarPattern(f.TitleInclude); err != nil || !re.MatchString(m.Title) {
+			return "标题不匹配 " + f.TitleInclude
+		}
+	}
+	if f.TitleExclude != "" {
+		if re, err := compileRadarPattern(f.TitleExclude); err == nil && re.MatchString(m.Title) {
+			return "标题匹配排除规则 " + f.TitleExclude
+		}
+	}
+	// 时长规则只适用于视频
+	if m.MediaType != RadarMediaImage && m.Duration > 0 {
+		if f.MinDuration > 0 && m.Duration < f.MinDuration {
+			return fmt.Sprintf("时长 %d 秒，短于 %d 秒", m.Duration, f.MinDuration)
+		}
+		if f.MaxDuration > 0 && m.Duration > f.MaxDuration {
+			return fmt.Sprintf("时长 %d 秒，超过 %d 秒", m.Duration, f.MaxDuration)
+		}
+	}
+	if f.MinSize > 0 && m.Size > 0 && m.Size < f.MinSize {
+		return fmt.Sprintf("文件大小 %d 字节，小于 %d 字节", m.Size, f.MinSize)
+	}
+	if f.MinResolution > 0 {
+		if short := resolutionShortSide(m.Resolution); short > 0 && short < f.MinResolution {
+			return fmt.Sprintf("分辨率 %s 低于 %dp", m.Resolution, f.MinResolution)
+		}
+	}
+	return ""
+}
+
+// radarPatterns 缓存编译后的标题正则（表达式 -> *regexp.Regexp），各规则共用，检查视频时不再重复编译
+var radarPatterns sync.Map
+
+// compileRadarPattern 返回缓存的正则，首次使用时编译
+func compileRadarPattern(expr string) (*regexp.Regexp, error) {
+	if re, ok := radarPatterns.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	radarPatterns.Store(expr, re)
+	return re, nil
+}
+
+// resolutionShortSide 解析 宽x高 或 720p 形式的分辨率，返回短边像素，无法解析时返回 0
+func resolutionShortSide(res string) int {
+	res = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(res)), "p")
+	parts := strings.Split(res, "x")
+	short := 0
+	for _, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n <= 0 {
+			return 0
+		}
+		if short == 0 || n < short {
+			short = n
+		}
+	}
+	return short
+}
+
+// marshalRadarFilter 将规则序列化为存储用的 JSON，没有规则时为空字符串
+func marshalRadarFilter(f *RadarFilter) (string, error) {
+	if f.IsEmpty() {
+		return "", nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal radar filter: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalRadarFilter 解析存储的规则，空字符串表示没有规则
+func unmarshalRadarFilter(s string) (*RadarFilter, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var f RadarFilter
+	if err := json.Unmarshal([]byte(s), &f); err != nil {
+		return nil, fmt.Errorf("failed to parse radar filter: %w", err)
+	}
+	return &f, nil
+}
+
+// GetRadarFilter 获取全局雷达过滤规则，未设置时返回 nil
+func (r *SettingsRepository) GetRadarFilter() (*RadarFilter, error) {
+	value, err := r.Get(SettingKeyRadarFilter)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalRadarFilter(value)
+}
+
+// SetRadarFilter 保存全局雷达过滤规则，空规则会清除设置
+func (r *SettingsRepository) SetRadarFilter(f *RadarFilter) error {
+	value, err := marshalRadarFilter(f)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return r.Delete(SettingKeyRadarFilter)
+	}
+	return r.Set(SettingKeyRadarFilter, value)
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRadarFilterCheck(t *testing.T) {
+	filter := &RadarFilter{
+		TitleInclude:  "教程|测评",
+		TitleExclude:  "广告",
+		MinDuration:   30,
+		MaxDuration:   600,
+		MinSize:       1024,
+		MinResolution: 720,
+		MediaType:     RadarMediaVideo,
+	}
+	if err := filter.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ok := RadarMedia{Title: "手机测评", MediaType: RadarMediaVideo, Duration: 120, Size: 4096, Resolution: "1080x1920"}
+	tests := []struct {
+		name   string
+		modify func(m *RadarMedia)
+		reason string // 期望原因中包含的内容，为空表示通过
+	}{
+		{"match", func(m *RadarMedia) {}, ""},
+		{"title not included", func(m *RadarMedia) { m.Title = "日常" }, "标题不匹配"},
+		{"title excluded", func(m *RadarMedia) { m.Title = "测评 广告" }, "排除规则"},
+		{"too short", func(m *RadarMedia) { m.Duration = 10 }, "短于"},
+		{"too long", func(m *RadarMedia) { m.Duration = 700 }, "超过"},
+		{"too small", func(m *RadarMedia) { m.Size = 100 }, "文件大小"},
+		{"low resolution", func(m *RadarMedia) { m.Resolution = "480x854" }, "分辨率"},
+		{"image", func(m *RadarMedia) { m.MediaType = RadarMediaImage }, "媒体类型"},
+		{"unknown values pass", func(m *RadarMedia) { m.Duration, m.Size, m.Resolution, m.MediaType = 0, 0, "", "" }, ""},
+	}
+	for _, tt := range tests {
+		m := ok
+		tt.modify(&m)
+		reason := filter.Check(&m)
+		if (tt.reason == "") != (reason == "") || !strings.Contains(reason, tt.reason) {
+			t.Errorf("%s: unexpected reason %q, want %q", tt.name, reason, tt.reason)
+		}
+	}
+
+	// 正则只编译一次，之后的检查使用缓存
+	for _, expr := range []string{filter.TitleInclude, filter.TitleExclude} {
+		if _, ok := radarPatterns.Load(expr); !ok {
+			t.Errorf("pattern %q was not cached", expr)
+		}
+	}
+
+	var none *RadarFilter
+	if reason := none.Check(&RadarMedia{}); reason != "" {
+		t.Errorf("nil filter should accept everything, got %q", reason)
+	}
+
+	invalid := []*RadarFilter{
+		{TitleInclude: "("},
+		{MinDuration: 60, MaxDuration: 30},
+		{MediaType: "audio"},
+		{MinSize: -1},
+	}
+	for _, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", f)
+		}
+	}
+}
+
+func TestRadarFilterStorage(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	target := &RadarTarget{ID: "t1", Username: "u1", IntervalMinutes: 60, Status: RadarStatusActive,
+		Filter: &RadarFilter{TitleExclude: "直播回放", MinDuration: 30}}
+	if err := repo.Add(target); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Add(&RadarTarget{ID: "t2", Username: "u2", IntervalMinutes: 60, Status: RadarStatusActive}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetByID("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Filter == nil || *got.Filter != *target.Filter {
+		t.Errorf("unexpected filter %+v", got.Filter)
+	}
+	if other, _ := repo.GetByUsername("u2"); other == nil || other.Filter != nil {
+		t.Errorf("expected no filter on t2, got %+v", other)
+	}
+
+	// 空规则保存后视为没有规则
+	got.Filter = &RadarFilter{}
+	if err := repo.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = repo.GetByID("t1"); got.Filter != nil {
+		t.Errorf("expected filter to be cleared, got %+v", got.Filter)
+	}
+
+	settings := NewSettingsRepository()
+	if f, err := settings.GetRadarFilter(); err != nil || f != nil {
+		t.Fatalf("expected no global filter, got %+v %v", f, err)
+	}
+	global := &RadarFilter{MediaType: RadarMediaVideo, MinResolution: 720}
+	if err := settings.SetRadarFilter(global); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := settings.GetRadarFilter(); err != nil || f == nil || *f != *global {
+		t.Errorf("unexpected global filter %+v %v", f, err)
+	}
+	if err := settings.SetRadarFilter(&RadarFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := settings.GetRadarFilter(); f != nil {
+		t.Errorf("expected global filter to be cleared, got %+v", f)
+	}
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"wx_channel/internal/utils"
+)
+
+// RadarFolderVars 是分组下载子目录模板可用的变量
+var RadarFolderVars = []string{"group", "author", "keyword", "date"}
+
+// RadarGroup 表示雷达分组的设置。分组由监控目标的 group 字段确定，没有保存设置的分组按默认行为处理：
+// 按作者分目录、优先级 0、不设默认间隔和过滤规则。
+type RadarGroup struct {
+	Name            string       `json:"name"`
+	Folder          string       `json:"folder"`           // 下载子目录模板，如 {group}/{author}，为空时按作者分目录
+	Priority        int          `json:"priority"`         // 队列优先级，越大越先下载
+	IntervalMinutes int          `json:"interval_minutes"` // 加入分组的新目标未指定间隔时使用，0 表示不设置
+	Filter          *RadarFilter `json:"filter,omitempty"` // 过滤规则，与全局规则和目标规则同时生效
+	TargetCount     int          `json:"target_count"`     // 分组内的监控目标数，只用于展示
+	CreatedAt       time.Time    `json:"created_at"`       // 未保存设置的分组为零值
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// RadarGroupMaxPriority 分组优先级的绝对值上限
+const RadarGroupMaxPriority = 100
+
+// Normalize 去除首尾空格、补全默认值并校验分组设置，错误信息可直接展示给用户
+func (g *RadarGroup) Normalize() error {
+	g.Name = strings.TrimSpace(g.Name)
+	g.Folder = strings.Trim(strings.TrimSpace(g.Folder), `/\`)
+	if g.Name == "" {
+		return errors.New("分组名称不能为空")
+	}
+	if len([]rune(g.Name)) > 50 {
+		return errors.New("分组名称过长（最多 50 个字符）")
+	}
+	if err := utils.ValidateFolderTemplate(g.Folder, RadarFolderVars...); err != nil {
+		return fmt.Errorf("下载子目录无效: %v", err)
+	}
+	if g.Priority > RadarGroupMaxPriority || g.Priority < -RadarGroupMaxPriority {
+		return fmt.Errorf("优先级范围为 -%d 到 %d", RadarGroupMaxPriority, RadarGroupMaxPriority)
+	}
+	if g.IntervalMinutes < 0 {
+		g.IntervalMinutes = 0
+	} else if g.IntervalMinutes > 0 && g.IntervalMinutes < RadarMinInterval {
+		g.IntervalMinutes = RadarMinInterval
+	}
+	if err := g.Filter.Validate(); err != nil {
+		return fmt.Errorf("过滤规则无效: %v", err)
+	}
+	return nil
+}
+
+// ApplyDefaults 为加入分组的目标补全分组的默认设置，目前只有检测间隔
+func (g *RadarGroup) ApplyDefaults(target *RadarTarget) {
+	if g != nil && target.IntervalMinutes == 0 {
+		target.IntervalMinutes = g.IntervalMinutes
+	}
+}
+
+// SubDir 按子目录模板计算视频的下载子目录（以 / 分隔），未设置模板时返回空字符串
+func (g *RadarGroup) SubDir(author, keyword string, now time.Time) string {
+	if g == nil || g.Folder == "" {
+		return ""
+	}
+	return utils.ExpandFolderTemplate(g.Folder, map[string]string{
+		"group":   g.Name,
+		"author":  author,
+		"keyword": keyword,
+		"date":    now.Format("2006-01-02"),
+	})
+}
+
+// radarGroupColumns 是查询分组设置时 SELECT 的列，与 groupFromRow 对应
+const radarGroupColumns = `name, folder, priority, interval_minutes, filter_rules, created_at, updated_at,
+	(SELECT COUNT(*) FROM radar_targets WHERE group_name = radar_groups.name)`
+
+func (r *RadarRepository) groupFromRow(scanner interface{ Scan(...interface{}) error }) (*RadarGroup, error) {
+	var group RadarGroup
+	var filterRules, createdAt, updatedAt string
+	err := scanner.Scan(&group.Name, &group.Folder, &group.Priority, &group.IntervalMinutes, &filterRules,
+		&createdAt, &updatedAt, &group.TargetCount)
+	if err != nil {
+		return nil, err
+	}
+	if group.Filter, err = unmarshalRadarFilter(filterRules); err != nil {
+		return nil, err
+	}
+	group.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	group.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &group, nil
+}
+
+// GetGroup 获取分组设置，没有保存设置时返回 nil
+func (r *RadarRepository) GetGroup(name string) (*RadarGroup, error) {
+	row := db.QueryRow("SELECT "+radarGroupColumns+" FROM radar_groups WHERE name = ?", name)
+	group, err := r.groupFromRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get radar group: %w", err)
+	}
+	return group, nil
+}
+
+// GetGroups 获取所有分组：已保存设置的分组，以及只出现在监控目标上的分组；按优先级从高到低、名称排序
+func (r *RadarRepository) GetGroups() ([]RadarGroup, error) {
+	rows, err := db.Query(`
+		SELECT ` + radarGroupColumns + ` FROM radar_groups
+		UNION ALL
+		SELECT group_name, '', 0, 0, '', '', '', COUNT(*) FROM radar_targets
+		WHERE group_name <> '' AND group_name NOT IN (SELECT name FROM radar_groups)
+		GROUP BY group_name
+		ORDER BY 3 DESC, 1`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list radar groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []RadarGroup{}
+	for rows.Next() {
+		group, err := r.groupFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+	return groups, rows.Err()
+}
+
+// SaveGroup 保存分组设置，已存在时覆盖并保留创建时间
+func (r *RadarRepository) SaveGroup(group *RadarGroup) error {
+	filterRules, err := marshalRadarFilter(group.Filter)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	_, err = db.Exec(`
+		INSERT INTO radar_groups (name, folder, priority, interval_minutes, filter_rules, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			folder = excluded.folder, priority = excluded.priority, interval_minutes = excluded.interval_minutes,
+			filter_rules = excluded.filter_rules, updated_at = excluded.updated_at`,
+		group.Name, group.Folder, group.Priority, group.IntervalMinutes, filterRules,
+		now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save radar group: %w", err)
+	}
+	return nil
+}
+
+// DeleteGroup 删除分组设置，分组内的监控目标保留分组名称，之后按默认行为处理
+func (r *RadarRepository) DeleteGroup(name string) (bool, error) {
+	res, err := db.Exec("DELETE FROM radar_groups WHERE name = ?", name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete radar group: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
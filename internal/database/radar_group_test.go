@@ -0,0 +1,113 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRadarGroupNormalize(t *testing.T) {
+	group := &RadarGroup{Name: " 竞品 ", Folder: "/{group}/{author}/", IntervalMinutes: 2}
+	if err := group.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if group.Name != "竞品" || group.Folder != "{group}/{author}" || group.IntervalMinutes != RadarMinInterval {
+		t.Errorf("unexpected normalized group %+v", group)
+	}
+
+	invalid := []*RadarGroup{
+		{Name: " "},
+		{Name: "a", Folder: "{title}"},
+		{Name: "a", Folder: "{group}/.."},
+		{Name: "a", Priority: RadarGroupMaxPriority + 1},
+		{Name: "a", Filter: &RadarFilter{MinSize: -1}},
+	}
+	for _, g := range invalid {
+		if err := g.Normalize(); err == nil {
+			t.Errorf("expected %+v to be invalid", g)
+		}
+	}
+
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.Local)
+	if dir := group.SubDir("老王", "", now); dir != "竞品/老王" {
+		t.Errorf("sub dir = %q", dir)
+	}
+	group.Folder = "雷达/{date}"
+	if dir := group.SubDir("老王", "", now); dir != "雷达/2026-03-14" {
+		t.Errorf("sub dir = %q", dir)
+	}
+	if dir := (*RadarGroup)(nil).SubDir("老王", "", now); dir != "" {
+		t.Errorf("expected no sub dir without group, got %q", dir)
+	}
+
+	target := &RadarTarget{}
+	group.ApplyDefaults(target)
+	if target.IntervalMinutes != RadarMinInterval {
+		t.Errorf("expected group interval, got %d", target.IntervalMinutes)
+	}
+	target.IntervalMinutes = 30
+	group.ApplyDefaults(target)
+	if target.IntervalMinutes != 30 {
+		t.Errorf("expected target interval to be kept, got %d", target.IntervalMinutes)
+	}
+}
+
+func TestRadarGroupStorage(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	for _, target := range []*RadarTarget{
+		{ID: "t1", Username: "u1", Group: "竞品", Status: RadarStatusActive},
+		{ID: "t2", Username: "u2", Group: "竞品", Status: RadarStatusActive},
+		{ID: "t3", Username: "u3", Group: "灵感", Status: RadarStatusActive},
+	} {
+		if err := repo.Add(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if group, err := repo.GetGroup("竞品"); err != nil || group != nil {
+		t.Fatalf("expected no settings, got %+v %v", group, err)
+	}
+	group := &RadarGroup{Name: "竞品", Folder: "{group}/{author}", Priority: 10, IntervalMinutes: 15,
+		Filter: &RadarFilter{MinDuration: 30}}
+	if err := repo.SaveGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	group.Priority = 20
+	if err := repo.SaveGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveGroup(&RadarGroup{Name: "合作", Priority: -5}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetGroup("竞品")
+	if err != nil || got == nil {
+		t.Fatalf("GetGroup: %+v %v", got, err)
+	}
+	if got.Priority != 20 || got.Folder != "{group}/{author}" || got.Filter == nil || got.Filter.MinDuration != 30 ||
+		got.TargetCount != 2 || got.CreatedAt.IsZero() {
+		t.Errorf("unexpected group %+v", got)
+	}
+
+	// 已保存设置的分组和只出现在目标上的分组，按优先级排序
+	groups, err := repo.GetGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 3 || groups[0].Name != "竞品" || groups[1].Name != "灵感" || groups[1].TargetCount != 1 ||
+		!groups[1].CreatedAt.IsZero() || groups[2].Name != "合作" || groups[2].TargetCount != 0 {
+		t.Errorf("unexpected groups %+v", groups)
+	}
+
+	if ok, err := repo.DeleteGroup("竞品"); err != nil || !ok {
+		t.Errorf("DeleteGroup: %v %v", ok, err)
+	}
+	if ok, _ := repo.DeleteGroup("竞品"); ok {
+		t.Error("expected second delete to report missing group")
+	}
+	if targets, _ := repo.GetByGroup("竞品"); len(targets) != 2 {
+		t.Errorf("expected targets to keep the group, got %+v", targets)
+	}
+}
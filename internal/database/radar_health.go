@@ -0,0 +1,117 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// 雷达健康状态
+//
+// 每个监控目标按连续失败次数在 healthy、degraded、suspended 之间切换：失败后按指数退避延后检测，
+// 连续失败过多时进入 suspended 低频探测，任何一次成功都恢复为 healthy。
+// 多个目标接连失败时（通常是请求过于频繁被微信限制）全局断路器打开，冷却期间暂停所有请求。
+
+// 健康状态
+const (
+	RadarHealthHealthy   = "healthy"   // 正常
+	RadarHealthDegraded  = "degraded"  // 连续失败，按指数退避延后检测
+	RadarHealthSuspended = "suspended" // 连续失败次数过多，按最长间隔探测
+)
+
+const (
+	// RadarSuspendAfter 连续失败达到该次数后进入 suspended
+	RadarSuspendAfter = 5
+	// RadarMaxBackoff 退避和断路器冷却的最长时间，也是 suspended 的探测间隔
+	RadarMaxBackoff = 6 * time.Hour
+)
+
+// RecordSuccess 记录一次成功的检测，恢复为 healthy；最近的错误保留供查看
+func (t *RadarTarget) RecordSuccess() {
+	t.Health = RadarHealthHealthy
+	t.ConsecutiveFailures = 0
+}
+
+// RecordFailure 记录一次失败的检测
+func (t *RadarTarget) RecordFailure(message string, now time.Time) {
+	t.ConsecutiveFailures++
+	t.LastError = message
+	t.LastErrorTime = &now
+	t.Health = RadarHealthDegraded
+	if t.ConsecutiveFailures >= RadarSuspendAfter {
+		t.Health = RadarHealthSuspended
+	}
+}
+
+// BackoffAfter 返回失败后的下次检测时间：正常间隔按连续失败次数翻倍，最长 RadarMaxBackoff
+func (t *RadarTarget) BackoffAfter(now time.Time) time.Time {
+	if t.Health == RadarHealthSuspended {
+		return now.Add(RadarMaxBackoff)
+	}
+	delay := t.ScheduledAfter(now).Sub(now)
+	for i := 1; i < t.ConsecutiveFailures && delay < RadarMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > RadarMaxBackoff {
+		delay = RadarMaxBackoff
+	}
+	return now.Add(delay)
+}
+
+// RadarBreaker 表示全局断路器状态，保存在 settings 表的 radar_breaker 键下
+type RadarBreaker struct {
+	OpenUntil *time.Time `json:"open_until,omitempty"` // 冷却结束时间，之前暂停所有请求
+	Trips     int        `json:"trips"`                // 连续打开次数，冷却时间按次数翻倍；大于 0 时冷却结束后先试探一个目标
+	Reason    string     `json:"reason,omitempty"`
+}
+
+// IsOpen 判断断路器是否处于冷却期
+func (b *RadarBreaker) IsOpen(now time.Time) bool {
+	return b.OpenUntil != nil && now.Before(*b.OpenUntil)
+}
+
+// Open 打开断路器，冷却时间为 cooldown 按连续打开次数翻倍，最长 RadarMaxBackoff
+func (b *RadarBreaker) Open(now time.Time, cooldown time.Duration, reason string) {
+	b.Trips++
+	for i := 1; i < b.Trips && cooldown < RadarMaxBackoff; i++ {
+		cooldown *= 2
+	}
+	if cooldown > RadarMaxBackoff {
+		cooldown = RadarMaxBackoff
+	}
+	until := now.Add(cooldown)
+	b.OpenUntil = &until
+	b.Reason = reason
+}
+
+// Close 关闭断路器
+func (b *RadarBreaker) Close() {
+	*b = RadarBreaker{}
+}
+
+// GetRadarBreaker 获取全局断路器状态，未设置时返回关闭状态
+func (r *SettingsRepository) GetRadarBreaker() (*RadarBreaker, error) {
+	value, err := r.Get(SettingKeyRadarBreaker)
+	if err != nil {
+		return nil, err
+	}
+	breaker := &RadarBreaker{}
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), breaker); err != nil {
+			return nil, fmt.Errorf("failed to parse radar breaker: %w", err)
+		}
+	}
+	return breaker, nil
+}
+
+// SetRadarBreaker 保存全局断路器状态
+func (r *SettingsRepository) SetRadarBreaker(breaker *RadarBreaker) error {
+	if breaker.Trips == 0 && breaker.OpenUntil == nil {
+		return r.Delete(SettingKeyRadarBreaker)
+	}
+	data, err := json.Marshal(breaker)
+	if err != nil {
+		return fmt.Errorf("failed to marshal radar breaker: %w", err)
+	}
+	return r.Set(SettingKeyRadarBreaker, string(data))
+}
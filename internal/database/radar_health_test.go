@@ -0,0 +1,85 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRadarTargetHealth(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	target := &RadarTarget{ID: "t1", Username: "u1", IntervalMinutes: 30, Status: RadarStatusActive}
+	if err := repo.Add(target); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.Local)
+	// 连续失败时间隔翻倍：30m、1h、2h、4h，第 5 次进入 suspended
+	for i, want := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour, 4 * time.Hour} {
+		target.RecordFailure("微信接口返回失败", now)
+		if target.Health != RadarHealthDegraded || target.ConsecutiveFailures != i+1 {
+			t.Fatalf("failure %d: unexpected state %s/%d", i+1, target.Health, target.ConsecutiveFailures)
+		}
+		if got := target.BackoffAfter(now).Sub(now); got != want {
+			t.Errorf("failure %d: backoff %v, want %v", i+1, got, want)
+		}
+	}
+	target.RecordFailure(errors.New("timeout").Error(), now)
+	if target.Health != RadarHealthSuspended || target.BackoffAfter(now).Sub(now) != RadarMaxBackoff {
+		t.Errorf("expected suspended with max backoff, got %s %v", target.Health, target.BackoffAfter(now).Sub(now))
+	}
+
+	if err := repo.UpdateHealth(target); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := repo.GetByID("t1")
+	if got.Health != RadarHealthSuspended || got.ConsecutiveFailures != 5 || got.LastError != "timeout" || !got.LastErrorTime.Equal(now) {
+		t.Errorf("unexpected stored health %+v", got)
+	}
+
+	// 成功后自动恢复，保留最近的错误
+	got.RecordSuccess()
+	if err := repo.UpdateHealth(got); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	if got.Health != RadarHealthHealthy || got.ConsecutiveFailures != 0 || got.LastError != "timeout" {
+		t.Errorf("expected recovery, got %+v", got)
+	}
+}
+
+func TestRadarBreaker(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	settings := NewSettingsRepository()
+	breaker, err := settings.GetRadarBreaker()
+	if err != nil || breaker.Trips != 0 || breaker.IsOpen(time.Now()) {
+		t.Fatalf("expected closed breaker, got %+v %v", breaker, err)
+	}
+
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.Local)
+	breaker.Open(now, 30*time.Minute, "3 个目标接连失败")
+	breaker.Open(now, 30*time.Minute, "试探请求失败")
+	if !breaker.IsOpen(now.Add(59*time.Minute)) || breaker.IsOpen(now.Add(time.Hour)) {
+		t.Errorf("expected second trip to cool down for 1h, open until %v", breaker.OpenUntil)
+	}
+	if err := settings.SetRadarBreaker(breaker); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := settings.GetRadarBreaker()
+	if stored.Trips != 2 || !stored.OpenUntil.Equal(*breaker.OpenUntil) || stored.Reason != "试探请求失败" {
+		t.Errorf("unexpected stored breaker %+v", stored)
+	}
+
+	stored.Close()
+	if err := settings.SetRadarBreaker(stored); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := settings.Get(SettingKeyRadarBreaker); value != "" {
+		t.Errorf("expected closed breaker to be removed, got %q", value)
+	}
+}
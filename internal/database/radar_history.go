@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RadarAccountSnapshot 是一次检测时监控账号的资料快照
+type RadarAccountSnapshot struct {
+	ID              int64     `json:"id"`
+	TargetID        string    `json:"target_id"`
+	Username        string    `json:"username"`
+	Nickname        string    `json:"nickname"`
+	AvatarURL       string    `json:"avatar_url"`
+	FansCount       *int64    `json:"fans_count"`        // 返回数据中没有粉丝数时为 null
+	FeedsCount      *int64    `json:"feeds_count"`       // 返回数据中没有作品数时为 null
+	Posts7d         int       `json:"posts_7d"`          // 最近 7 天发布的视频数，按已保存的视频统计
+	Posts30d        int       `json:"posts_30d"`         // 最近 30 天发布的视频数
+	LastPublishedAt int64     `json:"last_published_at"` // 最新视频的发布时间（Unix 秒），0 表示未知
+	CapturedAt      time.Time `json:"captured_at"`
+
+	// 与上一条快照相比昵称、头像是否变化，查询时计算，窗口内的第一条不标记
+	NicknameChanged bool `json:"nickname_changed,omitempty"`
+	AvatarChanged   bool `json:"avatar_changed,omitempty"`
+}
+
+// AddAccountSnapshot 追加一条账号资料快照，发布频率按 videos 表中该账号截至 CapturedAt 的视频计算
+func (r *RadarRepository) AddAccountSnapshot(s *RadarAccountSnapshot) error {
+	if s.CapturedAt.IsZero() {
+		s.CapturedAt = time.Now()
+	}
+	err := db.QueryRow(`
+		SELECT COUNT(CASE WHEN published_at >= ? THEN 1 END), COUNT(CASE WHEN published_at >= ? THEN 1 END),
+			COALESCE(MAX(published_at), 0)
+		FROM videos WHERE author_id = ?`,
+		s.CapturedAt.AddDate(0, 0, -7).Unix(), s.CapturedAt.AddDate(0, 0, -30).Unix(), s.Username,
+	).Scan(&s.Posts7d, &s.Posts30d, &s.LastPublishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to count radar account posts: %w", err)
+	}
+
+	res, err := db.Exec(`
+		INSERT INTO radar_account_snapshots (target_id, username, nickname, avatar_url, fans_count, feeds_count,
+			posts_7d, posts_30d, last_published_at, captured_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.TargetID, s.Username, s.Nickname, s.AvatarURL, nullInt64(s.FansCount), nullInt64(s.FeedsCount),
+		s.Posts7d, s.Posts30d, s.LastPublishedAt, s.CapturedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to add radar account snapshot: %w", err)
+	}
+	s.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// AccountHistory 按时间正序返回账号在 [since, until] 内的资料快照，零值时间表示不限，超出 limit 时保留最近的点
+func (r *RadarRepository) AccountHistory(username string, since, until time.Time, limit int) ([]RadarAccountSnapshot, error) {
+	query := `
+		SELECT id, target_id, username, nickname, avatar_url, fans_count, feeds_count, posts_7d, posts_30d,
+			last_published_at, captured_at
+		FROM radar_account_snapshots WHERE username = ?`
+	args := []interface{}{username}
+	// captured_at 统一以 UTC 写入，按字符串比较时顺序正确
+	if !since.IsZero() {
+		query += " AND captured_at >= ?"
+		args = append(args, since.UTC().Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		query += " AND captured_at <= ?"
+		args = append(args, until.UTC().Format(time.RFC3339))
+	}
+	query = "SELECT * FROM (" + query + " ORDER BY captured_at DESC, id DESC LIMIT ?) ORDER BY captured_at, id"
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query radar account history: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []RadarAccountSnapshot{}
+	for rows.Next() {
+		var s RadarAccountSnapshot
+		var fans, feeds sql.NullInt64
+		var capturedAt string
+		if err := rows.Scan(&s.ID, &s.TargetID, &s.Username, &s.Nickname, &s.AvatarURL, &fans, &feeds,
+			&s.Posts7d, &s.Posts30d, &s.LastPublishedAt, &capturedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan radar account snapshot: %w", err)
+		}
+		if fans.Valid {
+			s.FansCount = &fans.Int64
+		}
+		if feeds.Valid {
+			s.FeedsCount = &feeds.Int64
+		}
+		if t, err := time.Parse(time.RFC3339, capturedAt); err == nil {
+			s.CapturedAt = t.Local()
+		}
+		if n := len(snapshots); n > 0 {
+			prev := snapshots[n-1]
+			s.NicknameChanged = s.Nickname != "" && prev.Nickname != "" && s.Nickname != prev.Nickname
+			s.AvatarChanged = s.AvatarURL != "" && prev.AvatarURL != "" && s.AvatarURL != prev.AvatarURL
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// nullInt64 将可选的数字转换为写入数据库的值，nil 写入 NULL
+func nullInt64(n *int64) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRadarAccountHistory(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	videos := NewVideoRepository()
+	for i, age := range []int{1, 3, 10, 40} {
+		v := &Video{ID: string(rune('a' + i)), AuthorID: "u1", PublishedAt: now.AddDate(0, 0, -age).Unix()}
+		if err := videos.Upsert(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := videos.Upsert(&Video{ID: "other", AuthorID: "u2", PublishedAt: now.Unix()}); err != nil {
+		t.Fatal(err)
+	}
+
+	fans := int64(1000)
+	snapshots := []*RadarAccountSnapshot{
+		{TargetID: "a1", Username: "u1", Nickname: "作者", AvatarURL: "https://avatar/1", FansCount: &fans, CapturedAt: now.Add(-2 * time.Hour)},
+		{TargetID: "a1", Username: "u1", Nickname: "作者", AvatarURL: "https://avatar/2", CapturedAt: now.Add(-time.Hour)},
+		{TargetID: "a1", Username: "u1", Nickname: "新名字", AvatarURL: "https://avatar/2", CapturedAt: now},
+	}
+	for _, s := range snapshots {
+		if err := repo.AddAccountSnapshot(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s := snapshots[2]; s.ID == 0 || s.Posts7d != 2 || s.Posts30d != 3 || s.LastPublishedAt != now.AddDate(0, 0, -1).Unix() {
+		t.Errorf("unexpected posting frequency %+v", s)
+	}
+
+	history, err := repo.AccountHistory("u1", time.Time{}, time.Time{}, 100)
+	if err != nil || len(history) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d %v", len(history), err)
+	}
+	if history[0].FansCount == nil || *history[0].FansCount != 1000 || history[1].FansCount != nil || history[0].FeedsCount != nil {
+		t.Errorf("unexpected counts %+v %+v", history[0], history[1])
+	}
+	if history[0].AvatarChanged || history[0].NicknameChanged || !history[1].AvatarChanged || history[1].NicknameChanged ||
+		!history[2].NicknameChanged || history[2].AvatarChanged {
+		t.Errorf("unexpected change flags %+v", history)
+	}
+
+	// 时间范围和 limit：超出 limit 时保留最近的点
+	if history, _ = repo.AccountHistory("u1", now.Add(-90*time.Minute), time.Time{}, 100); len(history) != 2 {
+		t.Errorf("expected 2 snapshots since, got %d", len(history))
+	}
+	if history, _ = repo.AccountHistory("u1", time.Time{}, now.Add(-90*time.Minute), 100); len(history) != 1 {
+		t.Errorf("expected 1 snapshot until, got %d", len(history))
+	}
+	if history, _ = repo.AccountHistory("u1", time.Time{}, time.Time{}, 2); len(history) != 2 || history[1].Nickname != "新名字" {
+		t.Errorf("expected latest 2 snapshots, got %+v", history)
+	}
+	if history, _ = repo.AccountHistory("u2", time.Time{}, time.Time{}, 100); len(history) != 0 {
+		t.Errorf("expected no snapshots for u2, got %d", len(history))
+	}
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// RadarKeywordHit 记录关键词目标搜到的一个视频，同一关键词下每个视频只记录第一次
+type RadarKeywordHit struct {
+	Keyword    string    `json:"keyword"`
+	VideoID    string    `json:"video_id"`
+	TargetID   string    `json:"target_id"`
+	Title      string    `json:"title"`
+	AuthorID   string    `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	FoundAt    time.Time `json:"found_at"`
+}
+
+// IsKeyword 判断是否为关键词目标
+func (t *RadarTarget) IsKeyword() bool {
+	return t.Type == RadarTargetKeyword
+}
+
+// AddKeywordHit 记录关键词命中的视频，返回是否第一次命中（之前没有记录）
+func (r *RadarRepository) AddKeywordHit(hit *RadarKeywordHit) (bool, error) {
+	if hit.FoundAt.IsZero() {
+		hit.FoundAt = time.Now()
+	}
+	res, err := db.Exec(`
+		INSERT OR IGNORE INTO radar_keyword_hits (keyword, video_id, target_id, title, author_id, author_name, found_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		hit.Keyword, hit.VideoID, hit.TargetID, hit.Title, hit.AuthorID, hit.AuthorName, hit.FoundAt.Format(time.RFC3339))
+	if err != nil {
+		return false, fmt.Errorf("failed to add radar keyword hit: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetKeywordHits 获取关键词目标最近命中的视频，按发现时间倒序
+func (r *RadarRepository) GetKeywordHits(targetID string, limit int) ([]RadarKeywordHit, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := db.Query(`
+		SELECT keyword, video_id, target_id, title, author_id, author_name, found_at
+		FROM radar_keyword_hits
+		WHERE target_id = ?
+		ORDER BY found_at DESC, video_id
+		LIMIT ?`, targetID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []RadarKeywordHit{}
+	for rows.Next() {
+		var hit RadarKeywordHit
+		var foundAt string
+		if err := rows.Scan(&hit.Keyword, &hit.VideoID, &hit.TargetID, &hit.Title, &hit.AuthorID, &hit.AuthorName, &foundAt); err != nil {
+			return nil, err
+		}
+		hit.FoundAt, _ = time.Parse(time.RFC3339, foundAt)
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// GetKeywordsByVideoID 获取发现过该视频的关键词
+func (r *RadarRepository) GetKeywordsByVideoID(videoID string) ([]string, error) {
+	rows, err := db.Query("SELECT keyword FROM radar_keyword_hits WHERE video_id = ? ORDER BY found_at", videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keywords []string
+	for rows.Next() {
+		var keyword string
+		if err := rows.Scan(&keyword); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, keyword)
+	}
+	return keywords, rows.Err()
+}
+
+// HasKeywordHit 判断关键词之前是否搜到过该视频
+func (r *RadarRepository) HasKeywordHit(keyword, videoID string) (bool, error) {
+	var n int
+	err := db.QueryRow("SELECT COUNT(*) FROM radar_keyword_hits WHERE keyword = ? AND video_id = ?", keyword, videoID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to check radar keyword hit: %w", err)
+	}
+	return n > 0, nil
+}
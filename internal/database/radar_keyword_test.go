@@ -0,0 +1,116 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRadarKeywordTargets(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	account := &RadarTarget{ID: "a1", Username: "u1", AuthorName: "作者", IntervalMinutes: 30, Status: RadarStatusActive}
+	if err := repo.Add(account); err != nil {
+		t.Fatal(err)
+	}
+	if account.Type != RadarTargetAccount || account.Action != RadarActionDownload {
+		t.Errorf("unexpected defaults %q %q", account.Type, account.Action)
+	}
+
+	// 关键词目标没有 username，多个关键词目标可以共存，同一关键词不能重复
+	for _, kw := range []string{"露营", "咖啡"} {
+		target := &RadarTarget{ID: "k-" + kw, Type: RadarTargetKeyword, Keyword: kw, AuthorName: kw, IntervalMinutes: 60,
+			Status: RadarStatusActive, Action: RadarActionNotify}
+		if err := repo.Add(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Add(&RadarTarget{Type: RadarTargetKeyword, Keyword: "露营", IntervalMinutes: 60, Status: RadarStatusActive}); err == nil {
+		t.Error("expected duplicate keyword to be rejected")
+	}
+	if err := repo.Add(&RadarTarget{Username: "u1", IntervalMinutes: 60, Status: RadarStatusActive}); err == nil {
+		t.Error("expected duplicate username to be rejected")
+	}
+
+	got, err := repo.GetByID("k-露营")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsKeyword() || got.Keyword != "露营" || got.Action != RadarActionNotify || got.Username != "" {
+		t.Errorf("unexpected keyword target %+v", got)
+	}
+	got.Keyword, got.Action = "露营装备", ""
+	if err := repo.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = repo.GetByID("k-露营"); got.Keyword != "露营装备" || got.Action != RadarActionDownload {
+		t.Errorf("unexpected updated target %+v", got)
+	}
+	if byName, _ := repo.GetByUsername(""); byName != nil {
+		t.Errorf("keyword targets should not match by username, got %+v", byName)
+	}
+}
+
+func TestRadarKeywordHits(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	for _, kw := range []string{"露营", "咖啡"} {
+		if err := repo.Add(&RadarTarget{ID: kw, Type: RadarTargetKeyword, Keyword: kw, IntervalMinutes: 60, Status: RadarStatusActive}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.Local)
+	hits := []struct {
+		hit   RadarKeywordHit
+		first bool
+	}{
+		{RadarKeywordHit{Keyword: "露营", VideoID: "v1", TargetID: "露营", Title: "周末露营", AuthorID: "a1", FoundAt: base}, true},
+		{RadarKeywordHit{Keyword: "露营", VideoID: "v2", TargetID: "露营", FoundAt: base.Add(time.Hour)}, true},
+		{RadarKeywordHit{Keyword: "露营", VideoID: "v1", TargetID: "露营", FoundAt: base.Add(2 * time.Hour)}, false},
+		{RadarKeywordHit{Keyword: "咖啡", VideoID: "v1", TargetID: "咖啡", FoundAt: base.Add(3 * time.Hour)}, true},
+	}
+	for i, tt := range hits {
+		first, err := repo.AddKeywordHit(&tt.hit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != tt.first {
+			t.Errorf("hit %d: first = %v, want %v", i, first, tt.first)
+		}
+	}
+
+	if hit, err := repo.HasKeywordHit("咖啡", "v1"); err != nil || !hit {
+		t.Errorf("HasKeywordHit: %v %v", hit, err)
+	}
+	if hit, _ := repo.HasKeywordHit("咖啡", "v2"); hit {
+		t.Error("expected no hit for v2")
+	}
+
+	list, err := repo.GetKeywordHits("露营", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].VideoID != "v2" || list[1].Title != "周末露营" || !list[1].FoundAt.Equal(base) {
+		t.Errorf("unexpected hits %+v", list)
+	}
+
+	keywords, err := repo.GetKeywordsByVideoID("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keywords) != 2 || keywords[0] != "露营" || keywords[1] != "咖啡" {
+		t.Errorf("unexpected keywords %v", keywords)
+	}
+
+	// 删除目标时一并删除命中记录
+	if err := repo.Delete("咖啡"); err != nil {
+		t.Fatal(err)
+	}
+	if keywords, _ = repo.GetKeywordsByVideoID("v1"); len(keywords) != 1 {
+		t.Errorf("expected hits of deleted target to be removed, got %v", keywords)
+	}
+}
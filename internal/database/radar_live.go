@@ -0,0 +1,147 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"wx_channel/internal/utils"
+)
+
+// 直播状态的来源
+const (
+	RadarLiveSourceFeed   = "feed_list" // 视频列表返回的账号直播状态
+	RadarLiveSourceSearch = "search"    // 按昵称搜索直播（contact_list，type 2）
+)
+
+// RadarLiveSession 表示监控账号的一场直播，从检测到开播开始，到检测到下播结束
+type RadarLiveSession struct {
+	ID          string     `json:"id"`
+	TargetID    string     `json:"target_id"`
+	Username    string     `json:"username"`
+	AuthorName  string     `json:"author_name"`
+	LiveID      string     `json:"live_id,omitempty"` // 微信返回的直播 ID，用于区分同一账号前后两场直播
+	Description string     `json:"description"`
+	StreamURL   string     `json:"stream_url,omitempty"`
+	CoverURL    string     `json:"cover_url,omitempty"`
+	Source      string     `json:"source"`                // feed_list 或 search
+	RecordFile  string     `json:"record_file,omitempty"` // 交给录制命令的文件路径（不含扩展名）
+	StartTime   time.Time  `json:"start_time"`            // 检测到开播的时间，实际开播时间可能更早
+	EndTime     *time.Time `json:"end_time,omitempty"`    // 检测到下播的时间，为空表示仍在直播
+}
+
+// radarLiveColumns 是查询直播场次时 SELECT 的列，与 scanRadarLive 对应
+const radarLiveColumns = `id, target_id, username, author_name, live_id, description, stream_url, cover_url, source,
+	record_file, start_time, COALESCE(end_time, '')`
+
+func scanRadarLive(scanner interface{ Scan(...interface{}) error }) (*RadarLiveSession, error) {
+	var session RadarLiveSession
+	var startTime, endTime string
+	err := scanner.Scan(&session.ID, &session.TargetID, &session.Username, &session.AuthorName, &session.LiveID,
+		&session.Description, &session.StreamURL, &session.CoverURL, &session.Source, &session.RecordFile,
+		&startTime, &endTime)
+	if err != nil {
+		return nil, err
+	}
+	session.StartTime, _ = time.Parse(time.RFC3339, startTime)
+	if endTime != "" {
+		if t, err := time.Parse(time.RFC3339, endTime); err == nil {
+			session.EndTime = &t
+		}
+	}
+	return &session, nil
+}
+
+// GetOpenLiveSession 获取账号正在进行的直播场次，没有时返回 nil
+func (r *RadarRepository) GetOpenLiveSession(username string) (*RadarLiveSession, error) {
+	row := db.QueryRow(`SELECT `+radarLiveColumns+` FROM radar_live_sessions
+		WHERE username = ? AND end_time IS NULL ORDER BY start_time DESC LIMIT 1`, username)
+	session, err := scanRadarLive(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get radar live session: %w", err)
+	}
+	return session, nil
+}
+
+// UpdateLiveStatus 按一次检测到的直播状态更新账号的直播场次，live 为 nil 表示未在直播。
+// 开播时新建场次；下播，或直播 ID 变化（前一场结束后又开了一场）时结束进行中的场次。
+// 返回新开始和刚结束的场次，没有变化时都为 nil。
+func (r *RadarRepository) UpdateLiveStatus(target *RadarTarget, live *RadarLiveSession, now time.Time) (started, ended *RadarLiveSession, err error) {
+	open, err := r.GetOpenLiveSession(target.Username)
+	if err != nil {
+		return nil, nil, err
+	}
+	if open != nil && (live == nil || (live.LiveID != "" && open.LiveID != "" && live.LiveID != open.LiveID)) {
+		if _, err := db.Exec("UPDATE radar_live_sessions SET end_time = ? WHERE id = ?", now.Format(time.RFC3339), open.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to end radar live session: %w", err)
+		}
+		open.EndTime = &now
+		ended, open = open, nil
+	}
+	if live == nil || open != nil {
+		return nil, ended, nil
+	}
+
+	session := *live
+	session.ID = utils.RandomString(12)
+	session.TargetID = target.ID
+	session.Username = target.Username
+	if session.AuthorName == "" {
+		session.AuthorName = target.AuthorName
+	}
+	session.StartTime = now
+	session.EndTime = nil
+	_, err = db.Exec(`
+		INSERT INTO radar_live_sessions (id, target_id, username, author_name, live_id, description, stream_url,
+			cover_url, source, record_file, start_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ID, session.TargetID, session.Username, session.AuthorName, session.LiveID, session.Description,
+		session.StreamURL, session.CoverURL, session.Source, session.RecordFile, now.Format(time.RFC3339))
+	if err != nil {
+		return nil, ended, fmt.Errorf("failed to add radar live session: %w", err)
+	}
+	return &session, ended, nil
+}
+
+// SetLiveRecordFile 记录直播场次交给录制命令的文件路径
+func (r *RadarRepository) SetLiveRecordFile(id, file string) error {
+	_, err := db.Exec("UPDATE radar_live_sessions SET record_file = ? WHERE id = ?", file, id)
+	return err
+}
+
+// GetLiveSessions 获取直播场次，按开播时间倒序；username 为空时不限账号，liveOnly 只返回仍在直播的场次
+func (r *RadarRepository) GetLiveSessions(username string, liveOnly bool, limit int) ([]RadarLiveSession, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := "SELECT " + radarLiveColumns + " FROM radar_live_sessions WHERE 1 = 1"
+	var args []interface{}
+	if username != "" {
+		query += " AND username = ?"
+		args = append(args, username)
+	}
+	if liveOnly {
+		query += " AND end_time IS NULL"
+	}
+	query += " ORDER BY start_time DESC, id LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list radar live sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []RadarLiveSession{}
+	for rows.Next() {
+		session, err := scanRadarLive(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
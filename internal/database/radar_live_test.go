@@ -0,0 +1,73 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRadarLiveSessions(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	target := &RadarTarget{ID: "a1", Username: "u1", AuthorName: "作者"}
+	now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+
+	// 未在直播且没有进行中的场次：没有变化
+	if started, ended, err := repo.UpdateLiveStatus(target, nil, now); err != nil || started != nil || ended != nil {
+		t.Fatalf("unexpected change %v %v %v", started, ended, err)
+	}
+
+	// 开播：新建场次，作者名默认取目标的
+	started, ended, err := repo.UpdateLiveStatus(target, &RadarLiveSession{LiveID: "l1", Description: "开播啦", Source: RadarLiveSourceFeed}, now)
+	if err != nil || started == nil || ended != nil {
+		t.Fatalf("expected live start, got %v %v %v", started, ended, err)
+	}
+	if started.Username != "u1" || started.AuthorName != "作者" || started.TargetID != "a1" || !started.StartTime.Equal(now) {
+		t.Errorf("unexpected session %+v", started)
+	}
+
+	// 仍在直播：不重复记录
+	if s, e, _ := repo.UpdateLiveStatus(target, &RadarLiveSession{LiveID: "l1"}, now.Add(time.Minute)); s != nil || e != nil {
+		t.Errorf("expected no change while still live, got %v %v", s, e)
+	}
+	if err := repo.SetLiveRecordFile(started.ID, "/tmp/live"); err != nil {
+		t.Fatal(err)
+	}
+	open, err := repo.GetOpenLiveSession("u1")
+	if err != nil || open == nil || open.ID != started.ID || open.RecordFile != "/tmp/live" || open.EndTime != nil {
+		t.Fatalf("unexpected open session %+v %v", open, err)
+	}
+
+	// 直播 ID 变化：结束上一场并开始新的一场
+	s, e, err := repo.UpdateLiveStatus(target, &RadarLiveSession{LiveID: "l2"}, now.Add(2*time.Hour))
+	if err != nil || s == nil || e == nil || e.ID != started.ID || s.LiveID != "l2" {
+		t.Fatalf("expected session switch, got %v %v %v", s, e, err)
+	}
+
+	// 下播：结束场次
+	end := now.Add(3 * time.Hour)
+	if s, e, _ = repo.UpdateLiveStatus(target, nil, end); s != nil || e == nil || !e.EndTime.Equal(end) {
+		t.Fatalf("expected live end, got %v %v", s, e)
+	}
+	if open, _ = repo.GetOpenLiveSession("u1"); open != nil {
+		t.Errorf("expected no open session, got %+v", open)
+	}
+
+	// 其他账号的场次
+	other := &RadarTarget{ID: "a2", Username: "u2", AuthorName: "另一个"}
+	if _, _, err := repo.UpdateLiveStatus(other, &RadarLiveSession{Source: RadarLiveSourceSearch}, end); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := repo.GetLiveSessions("u1", false, 0)
+	if err != nil || len(sessions) != 2 || sessions[0].LiveID != "l2" || sessions[1].EndTime == nil {
+		t.Fatalf("unexpected sessions %+v %v", sessions, err)
+	}
+	if sessions, _ = repo.GetLiveSessions("", true, 0); len(sessions) != 1 || sessions[0].Username != "u2" {
+		t.Errorf("expected only u2 to be live, got %+v", sessions)
+	}
+	if sessions, _ = repo.GetLiveSessions("", false, 2); len(sessions) != 2 {
+		t.Errorf("expected limit to apply, got %d", len(sessions))
+	}
+}
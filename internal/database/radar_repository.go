@@ -2,6 +2,10 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"wx_channel/internal/utils"
@@ -15,16 +19,146 @@ const (
 	RadarStatusPaused RadarTargetStatus = "paused" // 已暂停
 )
 
+// 监控目标类型
+const (
+	RadarTargetAccount = "account" // 监控指定账号的视频列表（feed_list）
+	RadarTargetKeyword = "keyword" // 定期按关键词搜索视频（contact_list，type 3）
+)
+
+// 发现新视频后的处理方式
+const (
+	RadarActionDownload = "download" // 加入下载队列
+	RadarActionNotify   = "notify"   // 只记录到日志，不下载
+)
+
+// 回填状态与触发原因
+const (
+	RadarBackfillPending = "pending" // 进行中，按 Cursor 继续
+	RadarBackfillDone    = "done"    // 已完成
+
+	RadarBackfillManual = "manual" // 手动发起，跳过已知视频继续翻页
+	RadarBackfillGap    = "gap"    // 第一页没有已知视频时自动补抓，遇到已知视频即停止
+)
+
 // RadarTarget 表示一个雷达监控目标
 type RadarTarget struct {
 	ID              string            `json:"id"`
-	Username        string            `json:"username"`
-	AuthorName      string            `json:"author_name"`
-	IntervalMinutes int               `json:"interval_minutes"` // 监控频率 (分钟)
-	LastCheckTime   *time.Time        `json:"last_check_time"`  // 上次检测时间 (可能为 nil)
+	Type            string            `json:"type"`              // account 或 keyword，默认 account
+	Username        string            `json:"username"`          // 监控的账号，关键词目标为空
+	AuthorName      string            `json:"author_name"`       // 显示名称，关键词目标默认为关键词
+	Keyword         string            `json:"keyword,omitempty"` // 关键词目标的搜索词
+	Action          string            `json:"action"`            // 新视频的处理方式，默认 download
+	Group           string            `json:"group"`             // 分组名称，为空表示未分组
+	IntervalMinutes int               `json:"interval_minutes"`  // 监控频率 (分钟)
+	Schedule        string            `json:"schedule"`          // cron 表达式，设置后代替 interval_minutes
+	LastCheckTime   *time.Time        `json:"last_check_time"`   // 上次检测时间 (可能为 nil)
+	NextRunTime     *time.Time        `json:"next_run_time"`     // 下次检测时间，未计划时按上次检测时间推算
 	Status          RadarTargetStatus `json:"status"`
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
+	Filter          *RadarFilter      `json:"filter,omitempty"` // 过滤规则，与全局规则同时生效
+	Backfill        *RadarBackfill    `json:"backfill,omitempty"`
+	// 健康状态，见 radar_health.go
+	Health              string     `json:"health"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error"`
+	LastErrorTime       *time.Time `json:"last_error_time"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// ScheduledAfter 按 schedule 或 interval_minutes 计算 from 之后的检测时间（不含随机延迟）
+func (t *RadarTarget) ScheduledAfter(from time.Time) time.Time {
+	if t.Schedule != "" {
+		if cron, err := utils.ParseCron(t.Schedule); err == nil {
+			// 不早于最短检测间隔，避免之前保存的过密表达式集中请求
+			if next := cron.Next(from.Add((RadarMinInterval - 1) * time.Minute)); !next.IsZero() {
+				return next
+			}
+		}
+	}
+	interval := t.IntervalMinutes
+	if interval < 1 {
+		interval = 1
+	}
+	return from.Add(time.Duration(interval) * time.Minute)
+}
+
+// RadarMinInterval 最短检测间隔（分钟）
+const RadarMinInterval = 5
+
+// Normalize 去除首尾空格、补全默认值并校验监控目标的配置，错误信息可直接展示给用户。
+// 账号目标需要账号 ID 和名称，关键词目标需要关键词，名称默认为关键词。
+func (t *RadarTarget) Normalize() error {
+	t.Username = strings.TrimSpace(t.Username)
+	t.AuthorName = strings.TrimSpace(t.AuthorName)
+	t.Keyword = strings.TrimSpace(t.Keyword)
+	t.Group = strings.TrimSpace(t.Group)
+	t.Schedule = strings.TrimSpace(t.Schedule)
+
+	switch t.Type {
+	case "", RadarTargetAccount:
+		t.Type, t.Keyword = RadarTargetAccount, ""
+		if t.Username == "" || t.AuthorName == "" {
+			return errors.New("账号ID和账号名称不能为空")
+		}
+	case RadarTargetKeyword:
+		if t.Keyword == "" {
+			return errors.New("关键词不能为空")
+		}
+		if len([]rune(t.Keyword)) > 100 {
+			return errors.New("关键词过长（最多 100 个字符）")
+		}
+		t.Username = ""
+		if t.AuthorName == "" {
+			t.AuthorName = t.Keyword
+		}
+	default:
+		return fmt.Errorf("无效的目标类型: %s", t.Type)
+	}
+
+	switch t.Status {
+	case "":
+		t.Status = RadarStatusActive
+	case RadarStatusActive, RadarStatusPaused:
+	default:
+		return fmt.Errorf("无效的状态值: %s", t.Status)
+	}
+	switch t.Action {
+	case "":
+		t.Action = RadarActionDownload
+	case RadarActionDownload, RadarActionNotify:
+	default:
+		return fmt.Errorf("无效的处理方式: %s", t.Action)
+	}
+	if t.IntervalMinutes < RadarMinInterval {
+		t.IntervalMinutes = RadarMinInterval
+	}
+	if t.Schedule != "" {
+		cron, err := utils.ParseCron(t.Schedule)
+		if err != nil {
+			return fmt.Errorf("cron 表达式无效: %v", err)
+		}
+		if cron.MinInterval() < RadarMinInterval*time.Minute {
+			return fmt.Errorf("cron 表达式触发过于频繁，两次检测至少间隔 %d 分钟", RadarMinInterval)
+		}
+	}
+	if err := t.Filter.Validate(); err != nil {
+		return fmt.Errorf("过滤规则无效: %v", err)
+	}
+	return nil
+}
+
+// RadarBackfill 表示监控目标的回填进度：沿 feed_list 的 next_marker 向前翻页，
+// 直到遇到已知视频、达到页数上限或早于指定日期。每页之后保存游标，中断后可继续。
+type RadarBackfill struct {
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason"`
+	Cursor    string     `json:"cursor,omitempty"`    // 下一页的 next_marker
+	Pages     int        `json:"pages"`               // 已拉取页数
+	MaxPages  int        `json:"max_pages,omitempty"` // 页数上限，0 表示使用 radar_backfill_max_pages
+	Since     *time.Time `json:"since,omitempty"`     // 只回填该时间之后发布的视频
+	NewVideos int        `json:"new_videos"`          // 已加入队列的视频数
+	Message   string     `json:"message,omitempty"`   // 结束原因或最近一次错误
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 // RadarRepository 处理雷达配置相关的数据库操作
@@ -35,11 +169,15 @@ func NewRadarRepository() *RadarRepository {
 	return &RadarRepository{}
 }
 
+// radarTargetColumns 是查询监控目标时 SELECT 的列，与 targetFromRow 对应
+const radarTargetColumns = `id, username, author_name, interval_minutes, last_check_time, status, created_at, updated_at, filter_rules, backfill, schedule, next_run_time,
+	health, consecutive_failures, last_error, last_error_time, type, keyword, action, group_name`
+
 // targetFromRow 从数据库行扫描 Target 数据
 func (r *RadarRepository) targetFromRow(scanner interface{ Scan(...interface{}) error }) (*RadarTarget, error) {
 	var target RadarTarget
-	var lastCheckTimeStr sql.NullString
-	var createdAtStr, updatedAtStr string
+	var lastCheckTimeStr, nextRunTimeStr, lastErrorTimeStr sql.NullString
+	var createdAtStr, updatedAtStr, filterRules, backfill string
 
 	err := scanner.Scan(
 		&target.ID,
@@ -50,10 +188,31 @@ func (r *RadarRepository) targetFromRow(scanner interface{ Scan(...interface{})
 		&target.Status,
 		&createdAtStr,
 		&updatedAtStr,
+		&filterRules,
+		&backfill,
+		&target.Schedule,
+		&nextRunTimeStr,
+		&target.Health,
+		&target.ConsecutiveFailures,
+		&target.LastError,
+		&lastErrorTimeStr,
+		&target.Type,
+		&target.Keyword,
+		&target.Action,
+		&target.Group,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if target.Filter, err = unmarshalRadarFilter(filterRules); err != nil {
+		return nil, err
+	}
+	if backfill != "" {
+		target.Backfill = &RadarBackfill{}
+		if err := json.Unmarshal([]byte(backfill), target.Backfill); err != nil {
+			return nil, fmt.Errorf("failed to parse radar backfill: %w", err)
+		}
+	}
 
 	// 转换时间
 	if lastCheckTimeStr.Valid && lastCheckTimeStr.String != "" {
@@ -62,9 +221,29 @@ func (r *RadarRepository) targetFromRow(scanner interface{ Scan(...interface{})
 		}
 	}
 
+	if lastErrorTimeStr.Valid && lastErrorTimeStr.String != "" {
+		if t, err := time.Parse(time.RFC3339, lastErrorTimeStr.String); err == nil {
+			target.LastErrorTime = &t
+		}
+	}
+
 	target.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr)
 	target.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
 
+	// 未计划下次检测时间（新添加或刚修改过）时按上次检测时间推算，从未检测过的立即检测
+	if nextRunTimeStr.Valid && nextRunTimeStr.String != "" {
+		if t, err := time.Parse(time.RFC3339, nextRunTimeStr.String); err == nil {
+			target.NextRunTime = &t
+		}
+	}
+	if target.NextRunTime == nil {
+		next := target.CreatedAt
+		if target.LastCheckTime != nil {
+			next = target.ScheduledAfter(*target.LastCheckTime)
+		}
+		target.NextRunTime = &next
+	}
+
 	return &target, nil
 }
 
@@ -73,19 +252,35 @@ func (r *RadarRepository) Add(target *RadarTarget) error {
 	if target.ID == "" {
 		target.ID = utils.RandomString(12)
 	}
+	target.Health, target.ConsecutiveFailures = RadarHealthHealthy, 0
+	if target.Type == "" {
+		target.Type = RadarTargetAccount
+	}
+	if target.Action == "" {
+		target.Action = RadarActionDownload
+	}
 
 	now := time.Now().Format(time.RFC3339)
 	var lastCheckTime interface{}
 	if target.LastCheckTime != nil {
 		lastCheckTime = target.LastCheckTime.Format(time.RFC3339)
 	}
+	filterRules, err := marshalRadarFilter(target.Filter)
+	if err != nil {
+		return err
+	}
+	backfill, err := marshalRadarBackfill(target.Backfill)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		INSERT INTO radar_targets (
-			id, username, author_name, interval_minutes, last_check_time, status, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, username, author_name, interval_minutes, last_check_time, status, created_at, updated_at, filter_rules, backfill, schedule,
+			type, keyword, action, group_name
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := db.Exec(query,
+	_, err = db.Exec(query,
 		target.ID,
 		target.Username,
 		target.AuthorName,
@@ -94,30 +289,56 @@ func (r *RadarRepository) Add(target *RadarTarget) error {
 		target.Status,
 		now,
 		now,
+		filterRules,
+		backfill,
+		target.Schedule,
+		target.Type,
+		target.Keyword,
+		target.Action,
+		target.Group,
 	)
 	return err
 }
 
-// Update 更新监控目标配置或状态
+// Update 更新监控目标配置或状态，回填进度由 UpdateBackfill 单独维护，目标类型不可修改。
+// 仅在 schedule 或 interval 变化时清除已计划的下次检测时间，按新配置重新推算；
+// 其他修改保留已计划的时间（包括失败退避）。
 func (r *RadarRepository) Update(target *RadarTarget) error {
+	if target.Action == "" {
+		target.Action = RadarActionDownload
+	}
 	now := time.Now().Format(time.RFC3339)
 	var lastCheckTime interface{}
 	if target.LastCheckTime != nil {
 		lastCheckTime = target.LastCheckTime.Format(time.RFC3339)
 	}
 
+	filterRules, err := marshalRadarFilter(target.Filter)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE radar_targets 
-		SET username = ?, author_name = ?, interval_minutes = ?, last_check_time = ?, status = ?, updated_at = ?
+		SET username = ?, author_name = ?, interval_minutes = ?, last_check_time = ?, status = ?, updated_at = ?, filter_rules = ?,
+			schedule = ?, keyword = ?, action = ?, group_name = ?,
+			next_run_time = CASE WHEN interval_minutes = ? AND COALESCE(schedule, '') = ? THEN next_run_time ELSE NULL END
 		WHERE id = ?
 	`
-	_, err := db.Exec(query,
+	_, err = db.Exec(query,
 		target.Username,
 		target.AuthorName,
 		target.IntervalMinutes,
 		lastCheckTime,
 		target.Status,
 		now,
+		filterRules,
+		target.Schedule,
+		target.Keyword,
+		target.Action,
+		target.Group,
+		target.IntervalMinutes,
+		target.Schedule,
 		target.ID,
 	)
 	return err
@@ -135,10 +356,62 @@ func (r *RadarRepository) UpdateLastCheckTime(id string, lastCheckTime time.Time
 	return err
 }
 
+// UpdateNextRunTime 保存计划的下次检测时间
+func (r *RadarRepository) UpdateNextRunTime(id string, next time.Time) error {
+	_, err := db.Exec("UPDATE radar_targets SET next_run_time = ? WHERE id = ?", next.Format(time.RFC3339), id)
+	if err != nil {
+		return fmt.Errorf("failed to update radar next run time: %w", err)
+	}
+	return nil
+}
+
+// UpdateHealth 保存健康状态和最近的错误
+func (r *RadarRepository) UpdateHealth(target *RadarTarget) error {
+	var lastErrorTime interface{}
+	if target.LastErrorTime != nil {
+		lastErrorTime = target.LastErrorTime.Format(time.RFC3339)
+	}
+	_, err := db.Exec(`
+		UPDATE radar_targets SET health = ?, consecutive_failures = ?, last_error = ?, last_error_time = ?
+		WHERE id = ?`,
+		target.Health, target.ConsecutiveFailures, target.LastError, lastErrorTime, target.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update radar health: %w", err)
+	}
+	return nil
+}
+
+// UpdateBackfill 保存回填进度，nil 表示清除
+func (r *RadarRepository) UpdateBackfill(id string, backfill *RadarBackfill) error {
+	if backfill != nil {
+		backfill.UpdatedAt = time.Now()
+	}
+	value, err := marshalRadarBackfill(backfill)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE radar_targets SET backfill = ? WHERE id = ?", value, id)
+	if err != nil {
+		return fmt.Errorf("failed to update radar backfill: %w", err)
+	}
+	return nil
+}
+
+func marshalRadarBackfill(backfill *RadarBackfill) (string, error) {
+	if backfill == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(backfill)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal radar backfill: %w", err)
+	}
+	return string(data), nil
+}
+
 // GetAll 获取所有监控目标
 func (r *RadarRepository) GetAll() ([]RadarTarget, error) {
 	query := `
-		SELECT id, username, author_name, interval_minutes, last_check_time, status, created_at, updated_at
+		SELECT ` + radarTargetColumns + `
 		FROM radar_targets
 		ORDER BY created_at DESC
 	`
@@ -159,10 +432,35 @@ func (r *RadarRepository) GetAll() ([]RadarTarget, error) {
 	return targets, nil
 }
 
+// GetByGroup 获取分组内的所有监控目标
+func (r *RadarRepository) GetByGroup(group string) ([]RadarTarget, error) {
+	query := `
+		SELECT ` + radarTargetColumns + `
+		FROM radar_targets
+		WHERE group_name = ?
+		ORDER BY created_at DESC
+	`
+	rows, err := db.Query(query, group)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []RadarTarget
+	for rows.Next() {
+		target, err := r.targetFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, *target)
+	}
+	return targets, nil
+}
+
 // GetActive 获取所有活动状态的监控目标
 func (r *RadarRepository) GetActive() ([]RadarTarget, error) {
 	query := `
-		SELECT id, username, author_name, interval_minutes, last_check_time, status, created_at, updated_at
+		SELECT ` + radarTargetColumns + `
 		FROM radar_targets
 		WHERE status = 'active'
 		ORDER BY created_at DESC
@@ -187,7 +485,7 @@ func (r *RadarRepository) GetActive() ([]RadarTarget, error) {
 // GetByID 通过 ID 获取监控目标
 func (r *RadarRepository) GetByID(id string) (*RadarTarget, error) {
 	query := `
-		SELECT id, username, author_name, interval_minutes, last_check_time, status, created_at, updated_at
+		SELECT ` + radarTargetColumns + `
 		FROM radar_targets
 		WHERE id = ?
 	`
@@ -195,6 +493,34 @@ func (r *RadarRepository) GetByID(id string) (*RadarTarget, error) {
 	return r.targetFromRow(row)
 }
 
+// GetByUsername 通过视频号 username 获取监控目标，不存在时返回 nil
+func (r *RadarRepository) GetByUsername(username string) (*RadarTarget, error) {
+	query := `
+		SELECT ` + radarTargetColumns + `
+		FROM radar_targets
+		WHERE username = ? AND type = 'account'
+	`
+	target, err := r.targetFromRow(db.QueryRow(query, username))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return target, err
+}
+
+// GetByKeyword 通过关键词获取关键词目标，不存在时返回 nil
+func (r *RadarRepository) GetByKeyword(keyword string) (*RadarTarget, error) {
+	query := `
+		SELECT ` + radarTargetColumns + `
+		FROM radar_targets
+		WHERE keyword = ? AND type = 'keyword'
+	`
+	target, err := r.targetFromRow(db.QueryRow(query, keyword))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return target, err
+}
+
 // Delete 删除监控目标
 func (r *RadarRepository) Delete(id string) error {
 	query := "DELETE FROM radar_targets WHERE id = ?"
@@ -210,6 +536,25 @@ func (r *RadarRepository) UpdateStatus(id string, status RadarTargetStatus) erro
 	return err
 }
 
+// UpdateStatusByGroup 批量更新分组内所有目标的状态，返回更新的数量
+func (r *RadarRepository) UpdateStatusByGroup(group string, status RadarTargetStatus) (int64, error) {
+	now := time.Now().Format(time.RFC3339)
+	res, err := db.Exec("UPDATE radar_targets SET status = ?, updated_at = ? WHERE group_name = ? AND status <> ?", status, now, group, status)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update radar group status: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// DeleteByGroup 删除分组内的所有监控目标，返回删除的数量
+func (r *RadarRepository) DeleteByGroup(group string) (int64, error) {
+	res, err := db.Exec("DELETE FROM radar_targets WHERE group_name = ?", group)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete radar group: %w", err)
+	}
+	return res.RowsAffected()
+}
+
 // ======================== Radar Logs ========================
 
 // AddLog 记录一条执行日志
@@ -220,11 +565,14 @@ func (r *RadarRepository) AddLog(log *RadarLog) error {
 	if log.CheckTime.IsZero() {
 		log.CheckTime = time.Now()
 	}
+	if log.Mode == "" {
+		log.Mode = RadarLogScan
+	}
 
 	query := `
 		INSERT INTO radar_logs (
-			id, target_id, check_time, found_videos, new_videos, status, error_message, video_list
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, target_id, check_time, found_videos, new_videos, status, error_message, video_list, mode
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := db.Exec(query,
 		log.ID,
@@ -235,6 +583,7 @@ func (r *RadarRepository) AddLog(log *RadarLog) error {
 		log.Status,
 		log.ErrorMessage,
 		log.VideoList,
+		log.Mode,
 	)
 	return err
 }
@@ -246,10 +595,10 @@ func (r *RadarRepository) GetLogsByTargetID(targetID string, limit int) ([]Radar
 	}
 
 	query := `
-		SELECT id, target_id, check_time, found_videos, new_videos, status, error_message, COALESCE(video_list, '')
+		SELECT ` + radarLogColumns + `
 		FROM radar_logs
 		WHERE target_id = ?
-		ORDER BY check_time DESC
+		ORDER BY check_time DESC, id DESC
 		LIMIT ?
 	`
 	rows, err := db.Query(query, targetID, limit)
@@ -260,23 +609,54 @@ func (r *RadarRepository) GetLogsByTargetID(targetID string, limit int) ([]Radar
 
 	var logs []RadarLog
 	for rows.Next() {
-		var log RadarLog
-		var checkTimeStr string
-		err := rows.Scan(
-			&log.ID,
-			&log.TargetID,
-			&checkTimeStr,
-			&log.FoundVideos,
-			&log.NewVideos,
-			&log.Status,
-			&log.ErrorMessage,
-			&log.VideoList,
-		)
+		log, err := scanRadarLog(rows)
 		if err != nil {
 			return nil, err
 		}
-		log.CheckTime, _ = time.Parse(time.RFC3339, checkTimeStr)
-		logs = append(logs, log)
+		logs = append(logs, *log)
 	}
 	return logs, nil
 }
+
+// radarLogColumns 是查询执行日志时 SELECT 的列，与 scanRadarLog 对应
+const radarLogColumns = `id, target_id, check_time, found_videos, new_videos, status, error_message, COALESCE(video_list, ''), mode`
+
+// GetLogsPage 分页获取指定监控目标的执行日志，游标分页时按检查时间和 ID 定位
+func (r *RadarRepository) GetLogsPage(targetID string, params *PaginationParams) (*PagedResult[RadarLog], error) {
+	query := &pageQuery[RadarLog]{
+		table:   "radar_logs",
+		columns: radarLogColumns,
+		where:   "WHERE target_id = ?",
+		args:    []interface{}{targetID},
+		// check_time 以 RFC3339 文本写入，游标时间也按同样格式比较
+		order: timeOrder("check_time", func(t time.Time) interface{} { return t.Format(time.RFC3339) }),
+		noun:  "radar logs",
+		scan:  scanRadarLog,
+		cursor: func(log *RadarLog) pageCursor {
+			return pageCursor{Time: log.CheckTime, ID: log.ID}
+		},
+	}
+	return query.run(db, params)
+}
+
+// scanRadarLog 扫描一行执行日志
+func scanRadarLog(rows *sql.Rows) (*RadarLog, error) {
+	log := &RadarLog{}
+	var checkTimeStr string
+	err := rows.Scan(
+		&log.ID,
+		&log.TargetID,
+		&checkTimeStr,
+		&log.FoundVideos,
+		&log.NewVideos,
+		&log.Status,
+		&log.ErrorMessage,
+		&log.VideoList,
+		&log.Mode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	log.CheckTime, _ = time.Parse(time.RFC3339, checkTimeStr)
+	return log, nil
+}
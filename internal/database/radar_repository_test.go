@@ -0,0 +1,230 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRadarBackfillStorage(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := &RadarTarget{ID: "t1", Username: "u1", IntervalMinutes: 60, Status: RadarStatusActive,
+		Backfill: &RadarBackfill{Status: RadarBackfillPending, Reason: RadarBackfillManual, MaxPages: 5, Since: &since}}
+	if err := repo.Add(target); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.GetByID("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Backfill == nil || got.Backfill.Status != RadarBackfillPending || got.Backfill.MaxPages != 5 || !got.Backfill.Since.Equal(since) {
+		t.Fatalf("unexpected backfill %+v", got.Backfill)
+	}
+
+	// 保存游标后继续；更新目标配置不影响回填进度
+	got.Backfill.Cursor, got.Backfill.Pages = "marker-2", 1
+	if err := repo.UpdateBackfill("t1", got.Backfill); err != nil {
+		t.Fatal(err)
+	}
+	got.IntervalMinutes = 30
+	got.Backfill = nil
+	if err := repo.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	active, err := repo.GetActive()
+	if err != nil || len(active) != 1 {
+		t.Fatalf("GetActive: %v %v", active, err)
+	}
+	if b := active[0].Backfill; b == nil || b.Cursor != "marker-2" || b.Pages != 1 || b.UpdatedAt.IsZero() || active[0].IntervalMinutes != 30 {
+		t.Errorf("unexpected target after update %+v %+v", active[0], b)
+	}
+
+	if err := repo.UpdateBackfill("t1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = repo.GetByID("t1"); got.Backfill != nil {
+		t.Errorf("expected backfill to be cleared, got %+v", got.Backfill)
+	}
+
+	// 日志类型默认为 scan
+	if err := repo.AddLog(&RadarLog{ID: "l1", TargetID: "t1", Status: "success"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddLog(&RadarLog{ID: "l2", TargetID: "t1", Status: "success", Mode: RadarLogBackfill}); err != nil {
+		t.Fatal(err)
+	}
+	logs, err := repo.GetLogsByTargetID("t1", 10)
+	if err != nil || len(logs) != 2 {
+		t.Fatalf("GetLogsByTargetID: %v %v", logs, err)
+	}
+	modes := map[string]string{}
+	for _, l := range logs {
+		modes[l.ID] = l.Mode
+	}
+	if modes["l1"] != RadarLogScan || modes["l2"] != RadarLogBackfill {
+		t.Errorf("unexpected log modes %v", modes)
+	}
+}
+
+func TestRadarTargetSchedule(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	if err := repo.Add(&RadarTarget{ID: "t1", Username: "u1", IntervalMinutes: 30, Status: RadarStatusActive}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 从未检测过：立即到期
+	got, err := repo.GetByID("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NextRunTime == nil || got.NextRunTime.After(time.Now()) {
+		t.Errorf("expected new target to be due, got %v", got.NextRunTime)
+	}
+
+	// 按上次检测时间和间隔推算
+	last := time.Date(2026, 3, 14, 10, 17, 0, 0, time.Local)
+	if err := repo.UpdateLastCheckTime("t1", last); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	if want := last.Add(30 * time.Minute); !got.NextRunTime.Equal(want) {
+		t.Errorf("next run = %v, want %v", got.NextRunTime, want)
+	}
+
+	// 已计划的时间优先
+	planned := last.Add(42 * time.Minute)
+	if err := repo.UpdateNextRunTime("t1", planned); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	if !got.NextRunTime.Equal(planned) {
+		t.Errorf("next run = %v, want planned %v", got.NextRunTime, planned)
+	}
+
+	// 只修改其他配置时保留已计划的时间（如失败退避）
+	got.AuthorName, got.Status = "作者", RadarStatusPaused
+	if err := repo.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	if !got.NextRunTime.Equal(planned) {
+		t.Errorf("next run = %v, want planned %v after unrelated update", got.NextRunTime, planned)
+	}
+
+	// 修改为 cron 后清除已计划的时间，按表达式重新推算
+	got.Schedule = "0 */2 * * *"
+	if err := repo.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	if want := time.Date(2026, 3, 14, 12, 0, 0, 0, time.Local); got.Schedule != "0 */2 * * *" || !got.NextRunTime.Equal(want) {
+		t.Errorf("schedule %q next run = %v, want %v", got.Schedule, got.NextRunTime, want)
+	}
+
+	// 修改间隔同样重新推算
+	if err := repo.UpdateNextRunTime("t1", planned); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	got.Schedule, got.IntervalMinutes = "", 60
+	if err := repo.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.GetByID("t1")
+	if want := last.Add(60 * time.Minute); !got.NextRunTime.Equal(want) {
+		t.Errorf("interval next run = %v, want %v", got.NextRunTime, want)
+	}
+
+	// 已保存的过密表达式按最短间隔顺延
+	dense := &RadarTarget{Schedule: "* * * * *"}
+	if got, want := dense.ScheduledAfter(last), last.Add(RadarMinInterval*time.Minute); !got.Equal(want) {
+		t.Errorf("dense schedule next run = %v, want %v", got, want)
+	}
+}
+
+func TestRadarTargetNormalize(t *testing.T) {
+	target := &RadarTarget{Username: " u1 ", AuthorName: "作者", Keyword: "忽略", Group: " 竞品 ", IntervalMinutes: 1}
+	if err := target.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if target.Type != RadarTargetAccount || target.Username != "u1" || target.Keyword != "" || target.Group != "竞品" ||
+		target.IntervalMinutes != RadarMinInterval || target.Status != RadarStatusActive || target.Action != RadarActionDownload {
+		t.Errorf("unexpected normalized target %+v", target)
+	}
+
+	keyword := &RadarTarget{Type: RadarTargetKeyword, Username: "u1", Keyword: " 露营 ", IntervalMinutes: 60}
+	if err := keyword.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if keyword.Username != "" || keyword.AuthorName != "露营" {
+		t.Errorf("unexpected keyword target %+v", keyword)
+	}
+
+	invalid := []*RadarTarget{
+		{Username: "u1"},
+		{Type: RadarTargetKeyword},
+		{Type: "topic", Keyword: "x"},
+		{Username: "u1", AuthorName: "a", Status: "stopped"},
+		{Username: "u1", AuthorName: "a", Action: "email"},
+		{Username: "u1", AuthorName: "a", Schedule: "* * *"},
+		{Username: "u1", AuthorName: "a", Schedule: "* * * * *"},
+		{Username: "u1", AuthorName: "a", Schedule: "0,2 * * * *"},
+		{Username: "u1", AuthorName: "a", Filter: &RadarFilter{MinSize: -1}},
+	}
+	for _, target := range invalid {
+		if err := target.Normalize(); err == nil {
+			t.Errorf("expected %+v to be invalid", target)
+		}
+	}
+}
+
+func TestRadarTargetGroups(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewRadarRepository()
+	for _, target := range []*RadarTarget{
+		{ID: "t1", Username: "u1", Group: "竞品", Status: RadarStatusActive},
+		{ID: "t2", Username: "u2", Group: "竞品", Status: RadarStatusPaused},
+		{ID: "t3", Username: "u3", Group: "灵感", Status: RadarStatusActive},
+		{ID: "t4", Type: RadarTargetKeyword, Keyword: "露营", Group: "竞品", Status: RadarStatusActive},
+	} {
+		if err := repo.Add(target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	group, err := repo.GetByGroup("竞品")
+	if err != nil || len(group) != 3 {
+		t.Fatalf("GetByGroup: %v %v", group, err)
+	}
+	if kw, err := repo.GetByKeyword("露营"); err != nil || kw == nil || kw.ID != "t4" {
+		t.Errorf("GetByKeyword: %+v %v", kw, err)
+	}
+	if kw, err := repo.GetByKeyword("咖啡"); err != nil || kw != nil {
+		t.Errorf("expected no keyword target, got %+v %v", kw, err)
+	}
+
+	// 只统计状态实际改变的目标
+	if n, err := repo.UpdateStatusByGroup("竞品", RadarStatusPaused); err != nil || n != 2 {
+		t.Errorf("pause group: %d %v", n, err)
+	}
+	active, _ := repo.GetActive()
+	if len(active) != 1 || active[0].ID != "t3" {
+		t.Errorf("unexpected active targets %+v", active)
+	}
+
+	if n, err := repo.DeleteByGroup("竞品"); err != nil || n != 3 {
+		t.Errorf("delete group: %d %v", n, err)
+	}
+	if all, _ := repo.GetAll(); len(all) != 1 || all[0].Group != "灵感" {
+		t.Errorf("unexpected remaining targets %+v", all)
+	}
+}
@@ -0,0 +1,453 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// IntRange 表示闭区间 [Min, Max]，为 nil 的一端不做限制
+type IntRange struct {
+	Min *int64 `json:"min,omitempty"`
+	Max *int64 `json:"max,omitempty"`
+}
+
+// IsZero 判断区间是否未设置
+func (r IntRange) IsZero() bool {
+	return r.Min == nil && r.Max == nil
+}
+
+// RecordFilter 表示浏览记录和下载记录的结构化过滤条件
+// 时长单位为毫秒，大小单位为字节，与数据库存储一致
+type RecordFilter struct {
+	Author     string   `json:"author,omitempty"`
+	AuthorID   string   `json:"authorId,omitempty"`
+	Duration   IntRange `json:"duration,omitempty"`
+	Resolution string   `json:"resolution,omitempty"`
+	Size       IntRange `json:"size,omitempty"`
+	Likes      IntRange `json:"likes,omitempty"`
+	Comments   IntRange `json:"comments,omitempty"`
+	Favs       IntRange `json:"favs,omitempty"`
+	Source     string   `json:"source,omitempty"`     // 页面来源：home, feed, profile, search 等
+	Downloaded *bool    `json:"downloaded,omitempty"` // true 仅已下载，false 仅浏览未下载
+	Status     string   `json:"status,omitempty"`     // 下载状态，仅对下载记录生效
+	Tags       []string `json:"tags,omitempty"`       // 需同时带有的标签
+	Collection string   `json:"collection,omitempty"` // 所属合集名称
+	Group      string   `json:"group,omitempty"`      // 雷达分组，浏览记录按同一视频的下载记录匹配
+}
+
+// IsZero 判断是否没有任何过滤条件
+func (f *RecordFilter) IsZero() bool {
+	return f == nil || (f.Author == "" && f.AuthorID == "" && f.Duration.IsZero() && f.Resolution == "" &&
+		f.Size.IsZero() && f.Likes.IsZero() && f.Comments.IsZero() && f.Favs.IsZero() &&
+		f.Source == "" && f.Downloaded == nil && f.Status == "" && len(f.Tags) == 0 && f.Collection == "" && f.Group == "")
+}
+
+// 页面来源别名，对应视频号网页版的页面路径
+var pageSources = map[string]string{
+	"home":    "/web/pages/home",
+	"feed":    "/web/pages/feed",
+	"profile": "/web/pages/profile",
+	"search":  "/web/pages/s",
+	"s":       "/web/pages/s",
+	"like":    "/web/pages/account/like",
+	"account": "/web/pages/account",
+}
+
+// pageSourceOrder 是识别页面来源时的匹配顺序，路径较具体的在前
+var pageSourceOrder = []string{"like", "account", "profile", "feed", "home", "search"}
+
+// PageSource 返回页面地址对应的来源名称（home、feed、profile、search、like、account），无法识别时返回空字符串
+func PageSource(pageURL string) string {
+	for _, name := range pageSourceOrder {
+		if strings.Contains(pageURL, pageSources[name]) {
+			return name
+		}
+	}
+	return ""
+}
+
+// 查询语法中的字段名及其别名
+var filterKeys = map[string]string{
+	"author": "author", "作者": "author",
+	"authorid": "authorid", "uid": "authorid",
+	"dur": "duration", "duration": "duration", "时长": "duration",
+	"res": "resolution", "resolution": "resolution", "分辨率": "resolution",
+	"size": "size", "大小": "size",
+	"likes": "likes", "like": "likes", "点赞": "likes",
+	"comments": "comments", "comment": "comments", "评论": "comments",
+	"favs": "favs", "fav": "favs", "收藏": "favs",
+	"source": "source", "src": "source", "来源": "source",
+	"downloaded": "downloaded", "下载": "downloaded",
+	"status": "status", "状态": "status",
+	"tag": "tag", "标签": "tag",
+	"collection": "collection", "合集": "collection",
+	"group": "group", "分组": "group",
+}
+
+// ParseRecordQuery 解析记录查询语法，返回过滤条件和剩余的全文检索词
+//
+// 支持的写法：author:老王 likes>1000 dur<60s size>=10MB res:1080p
+// source:feed downloaded:no likes:100..5000 tag:项目A collection:精选 group:竞品，
+// 值中含空格时用双引号包裹，tag 可重复（需同时带有）。
+// 不认识的字段名按普通检索词处理。
+func ParseRecordQuery(input string) (*RecordFilter, string, error) {
+	filter := &RecordFilter{}
+	var text []string
+
+	for _, token := range splitQueryTokens(input) {
+		key, op, value, ok := splitFilterToken(token)
+		if !ok {
+			text = append(text, strings.Trim(token, `"`))
+			continue
+		}
+		if err := filter.apply(key, op, value); err != nil {
+			return nil, "", fmt.Errorf("invalid filter %q: %w", token, err)
+		}
+	}
+
+	return filter, strings.Join(text, " "), nil
+}
+
+// splitQueryTokens 按空白切分，双引号内的空白保留
+func splitQueryTokens(input string) []string {
+	var tokens []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+// splitFilterToken 把 key<op>value 拆开，op 为 : = > >= < <=
+func splitFilterToken(token string) (key, op, value string, ok bool) {
+	i := strings.IndexAny(token, ":=<>")
+	if i <= 0 {
+		return "", "", "", false
+	}
+	key, ok = filterKeys[strings.ToLower(token[:i])]
+	if !ok {
+		return "", "", "", false
+	}
+	op = token[i : i+1]
+	rest := token[i+1:]
+	if (op == ">" || op == "<") && strings.HasPrefix(rest, "=") {
+		op += "="
+		rest = rest[1:]
+	}
+	return key, op, strings.Trim(rest, `"`), true
+}
+
+func (f *RecordFilter) apply(key, op, value string) error {
+	if value == "" {
+		return fmt.Errorf("missing value")
+	}
+
+	var parse func(string) (int64, error)
+	var target *IntRange
+	switch key {
+	case "duration":
+		parse, target = parseDurationValue, &f.Duration
+	case "size":
+		parse, target = parseSizeValue, &f.Size
+	case "likes":
+		parse, target = parseCountValue, &f.Likes
+	case "comments":
+		parse, target = parseCountValue, &f.Comments
+	case "favs":
+		parse, target = parseCountValue, &f.Favs
+	}
+	if target != nil {
+		return target.apply(op, value, parse)
+	}
+
+	if op != ":" && op != "=" {
+		return fmt.Errorf("operator %s not supported", op)
+	}
+	switch key {
+	case "author":
+		f.Author = value
+	case "authorid":
+		f.AuthorID = value
+	case "resolution":
+		f.Resolution = strings.ToLower(value)
+	case "source":
+		f.Source = strings.ToLower(value)
+	case "status":
+		f.Status = strings.ToLower(value)
+	case "tag":
+		f.Tags = append(f.Tags, value)
+	case "collection":
+		f.Collection = value
+	case "group":
+		f.Group = value
+	case "downloaded":
+		b, err := parseBoolValue(value)
+		if err != nil {
+			return err
+		}
+		f.Downloaded = &b
+	}
+	return nil
+}
+
+// apply 根据比较符设置区间，整数比较时 >n 等价于 >=n+1
+func (r *IntRange) apply(op, value string, parse func(string) (int64, error)) error {
+	if lo, hi, found := strings.Cut(value, ".."); found && (op == ":" || op == "=") {
+		if lo != "" {
+			n, err := parse(lo)
+			if err != nil {
+				return err
+			}
+			r.Min = &n
+		}
+		if hi != "" {
+			n, err := parse(hi)
+			if err != nil {
+				return err
+			}
+			r.Max = &n
+		}
+		return nil
+	}
+
+	n, err := parse(value)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case ":", "=":
+		r.Min, r.Max = &n, &n
+	case ">=":
+		r.Min = &n
+	case ">":
+		n++
+		r.Min = &n
+	case "<=":
+		r.Max = &n
+	case "<":
+		n--
+		r.Max = &n
+	}
+	return nil
+}
+
+// parseDurationValue 解析时长为毫秒，纯数字按秒处理，也支持 90s、2m、1m30s、1h
+func parseDurationValue(s string) (int64, error) {
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(n * 1000), nil
+	}
+	d, err := time.ParseDuration(strings.ToLower(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d.Milliseconds(), nil
+}
+
+// parseSizeValue 解析文件大小为字节，支持 KB/MB/GB 等单位（1024 进制），纯数字按字节处理
+func parseSizeValue(s string) (int64, error) {
+	upper := strings.ToUpper(s)
+	units := []struct {
+		suffix string
+		mult   float64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+	mult := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(upper, u.suffix) {
+			upper, mult = strings.TrimSuffix(upper, u.suffix), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(math.Round(n * mult)), nil
+}
+
+// parseCountValue 解析计数，支持 1.2k、3w、3万
+func parseCountValue(s string) (int64, error) {
+	lower := strings.ToLower(s)
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(lower, "k"):
+		lower, mult = strings.TrimSuffix(lower, "k"), 1e3
+	case strings.HasSuffix(lower, "w"):
+		lower, mult = strings.TrimSuffix(lower, "w"), 1e4
+	case strings.HasSuffix(lower, "万"):
+		lower, mult = strings.TrimSuffix(lower, "万"), 1e4
+	}
+	n, err := strconv.ParseFloat(lower, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return int64(math.Round(n * mult)), nil
+}
+
+func parseBoolValue(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "y", "true", "1", "是":
+		return true, nil
+	case "no", "n", "false", "0", "否":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// recordColumns 描述过滤条件在某张表上对应的列
+type recordColumns struct {
+	table      string
+	videoID    string
+	size       string
+	authorID   string // 为空时通过 browse_history 关联
+	pageURL    string // 为空时通过 browse_history 关联
+	downloaded func(bool) string
+}
+
+var browseColumns = recordColumns{
+	table:    "browse_history",
+	videoID:  "id",
+	size:     "size",
+	authorID: "author_id",
+	pageURL:  "page_url",
+	downloaded: func(yes bool) string {
+		cond := "EXISTS (SELECT 1 FROM download_records d WHERE d.video_id = browse_history.id AND d.status = 'completed')"
+		if !yes {
+			cond = "NOT " + cond
+		}
+		return cond
+	},
+}
+
+var downloadColumns = recordColumns{
+	table:   "download_records",
+	videoID: "video_id",
+	size:    "file_size",
+	downloaded: func(yes bool) string {
+		if yes {
+			return "status = 'completed'"
+		}
+		return "status != 'completed'"
+	},
+}
+
+// conditions 生成 WHERE 子句片段及参数
+func (f *RecordFilter) conditions(cols recordColumns) ([]string, []interface{}) {
+	if f.IsZero() {
+		return nil, nil
+	}
+
+	var conds []string
+	var args []interface{}
+
+	// 下载记录没有 author_id 和 page_url，通过同一视频的浏览记录匹配
+	viaBrowse := func(cond string, arg interface{}) {
+		conds = append(conds, "video_id IN (SELECT id FROM browse_history WHERE "+cond+")")
+		args = append(args, arg)
+	}
+
+	if f.Author != "" {
+		conds = append(conds, `author LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(f.Author)+"%")
+	}
+	if f.AuthorID != "" {
+		if cols.authorID != "" {
+			conds = append(conds, cols.authorID+" = ?")
+			args = append(args, f.AuthorID)
+		} else {
+			viaBrowse("author_id = ?", f.AuthorID)
+		}
+	}
+	if f.Resolution != "" {
+		// 分辨率存储为 宽x高，1080p 匹配任意一边为 1080 的视频
+		res := strings.TrimSuffix(f.Resolution, "p")
+		if strings.Contains(res, "x") {
+			conds = append(conds, "LOWER(resolution) = ?")
+			args = append(args, res)
+		} else {
+			conds = append(conds, "(LOWER(resolution) IN (?, ?) OR resolution LIKE ? OR resolution LIKE ?)")
+			args = append(args, res, res+"p", "%x"+res, res+"x%")
+		}
+	}
+	if f.Source != "" {
+		// 已知来源按浏览记录的 sources 匹配（包含合并前各次观看的来源），其余按页面地址模糊匹配
+		cond, arg := `page_url LIKE ? ESCAPE '\'`, interface{}("%"+escapeLike(f.Source)+"%")
+		if path, ok := pageSources[f.Source]; ok {
+			cond, arg = "instr(',' || sources || ',', ?) > 0", ","+PageSource(path)+","
+		}
+		if cols.pageURL != "" {
+			conds = append(conds, cond)
+			args = append(args, arg)
+		} else {
+			viaBrowse(cond, arg)
+		}
+	}
+	if f.Downloaded != nil {
+		conds = append(conds, cols.downloaded(*f.Downloaded))
+	}
+	if f.Status != "" && cols.table == "download_records" {
+		conds = append(conds, "status = ?")
+		args = append(args, f.Status)
+	}
+	if len(f.Tags) > 0 {
+		cond, tagArgs := taggedCondition(cols.videoID, f.Tags)
+		conds = append(conds, cond)
+		args = append(args, tagArgs...)
+	}
+	if f.Collection != "" {
+		cond, collArgs := inCollectionCondition(cols.videoID, f.Collection)
+		conds = append(conds, cond)
+		args = append(args, collArgs...)
+	}
+	if f.Group != "" {
+		if cols.table == "download_records" {
+			conds = append(conds, "group_name = ?")
+		} else {
+			conds = append(conds, cols.videoID+" IN (SELECT video_id FROM download_records WHERE group_name = ?)")
+		}
+		args = append(args, f.Group)
+	}
+
+	for _, r := range []struct {
+		column string
+		rng    IntRange
+	}{
+		{"duration", f.Duration},
+		{cols.size, f.Size},
+		{"COALESCE(like_count, 0)", f.Likes},
+		{"COALESCE(comment_count, 0)", f.Comments},
+		{"COALESCE(fav_count, 0)", f.Favs},
+	} {
+		if r.rng.Min != nil {
+			conds = append(conds, r.column+" >= ?")
+			args = append(args, *r.rng.Min)
+		}
+		if r.rng.Max != nil {
+			conds = append(conds, r.column+" <= ?")
+			args = append(args, *r.rng.Max)
+		}
+	}
+
+	return conds, args
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecordQuery(t *testing.T) {
+	filter, text, err := ParseRecordQuery(`author:"淘金 老王" likes>1000 dur<60s size>=10MB res:1080p 黄金 downloaded:no comments:1w..`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "黄金" {
+		t.Errorf("expected remaining text 黄金, got %q", text)
+	}
+	if filter.Author != "淘金 老王" {
+		t.Errorf("unexpected author %q", filter.Author)
+	}
+	if *filter.Likes.Min != 1001 || filter.Likes.Max != nil {
+		t.Errorf("unexpected likes range %+v", filter.Likes)
+	}
+	if *filter.Duration.Max != 59999 {
+		t.Errorf("expected duration < 60000ms, got %d", *filter.Duration.Max)
+	}
+	if *filter.Size.Min != 10<<20 {
+		t.Errorf("unexpected size %d", *filter.Size.Min)
+	}
+	if *filter.Comments.Min != 10000 || filter.Comments.Max != nil {
+		t.Errorf("unexpected comments range %+v", filter.Comments)
+	}
+	if filter.Downloaded == nil || *filter.Downloaded {
+		t.Error("expected downloaded:no")
+	}
+
+	filter, _, err = ParseRecordQuery(`tag:项目A 标签:"B roll" collection:成片`)
+	if err != nil || len(filter.Tags) != 2 || filter.Tags[1] != "B roll" || filter.Collection != "成片" {
+		t.Errorf("unexpected tag filter %+v %v", filter, err)
+	}
+
+	// 未知字段按检索词处理
+	filter, text, err = ParseRecordQuery("https://example.com foo:bar")
+	if err != nil || !filter.IsZero() || text != "https://example.com foo:bar" {
+		t.Errorf("unexpected result %+v %q %v", filter, text, err)
+	}
+
+	for _, bad := range []string{"likes>abc", "dur<soon", "author>x", "downloaded:maybe", "size:"} {
+		if _, _, err := ParseRecordQuery(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRecordFilter_List(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	browseRepo := NewBrowseHistoryRepository()
+	for _, r := range []BrowseRecord{
+		{ID: "v1", Title: "短视频", Author: "老王", AuthorID: "u1", Duration: 30000, Size: 5 << 20, Resolution: "1080x1920", LikeCount: 5000, PageURL: "https://channels.weixin.qq.com/web/pages/feed"},
+		{ID: "v2", Title: "长视频", Author: "老王", AuthorID: "u1", Duration: 600000, Size: 200 << 20, Resolution: "1920x1080", LikeCount: 200, PageURL: "https://channels.weixin.qq.com/web/pages/home"},
+		{ID: "v3", Title: "别人的视频", Author: "小李", AuthorID: "u2", Duration: 45000, Size: 8 << 20, Resolution: "720x1280", LikeCount: 90000, PageURL: "https://channels.weixin.qq.com/web/pages/feed"},
+	} {
+		r.BrowseTime = now
+		if err := browseRepo.Create(&r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	downloadRepo := NewDownloadRecordRepository()
+	if err := downloadRepo.Create(&DownloadRecord{
+		ID: "d1", VideoID: "v1", Title: "短视频", Author: "老王", Duration: 30000, FileSize: 5 << 20,
+		Resolution: "1080x1920", LikeCount: 5000, Status: DownloadStatusCompleted, DownloadTime: now, Group: "竞品",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	browse := func(query string) []string {
+		t.Helper()
+		filter, text, err := ParseRecordQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := browseRepo.ListFiltered(&FilterParams{
+			PaginationParams: PaginationParams{SortBy: "title"},
+			Query:            text,
+			Filter:           filter,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range result.Items {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	cases := map[string]int{
+		"author:老王":          2,
+		"author:老王 dur<60s":  1,
+		"likes>1000":         2,
+		"likes>=1w":          1,
+		"res:1080p":          2,
+		"res:720p size<10MB": 1,
+		"source:feed":        2,
+		"source:home":        1,
+		"downloaded:yes":     1,
+		"downloaded:no":      2,
+		"uid:u2 source:feed": 1,
+		"group:竞品":           1,
+		"分组:灵感":              0,
+		"视频 likes:100..6000": 2,
+	}
+	for query, want := range cases {
+		if got := browse(query); len(got) != want {
+			t.Errorf("%s: expected %d records, got %v", query, want, got)
+		}
+	}
+
+	// 下载记录没有 author_id 和 page_url，通过浏览记录关联
+	filter, _, _ := ParseRecordQuery("uid:u1 source:feed downloaded:yes")
+	result, err := downloadRepo.List(&FilterParams{Filter: filter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 {
+		t.Errorf("expected 1 download record, got %d", result.Total)
+	}
+	if record, _ := downloadRepo.GetByID("d1"); record == nil || record.Group != "竞品" {
+		t.Errorf("expected download record to keep its group, got %+v", record)
+	}
+	filter, _, _ = ParseRecordQuery("group:竞品")
+	if result, err = downloadRepo.List(&FilterParams{Filter: filter}); err != nil || result.Total != 1 {
+		t.Errorf("expected 1 download record in group, got %v %v", result, err)
+	}
+	all, err := downloadRepo.FindAll(&FilterParams{Filter: &RecordFilter{Author: "小李"}})
+	if err != nil || len(all) != 0 {
+		t.Errorf("expected no downloads for 小李, got %v %v", all, err)
+	}
+}
+
+func TestSavedViews(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSettingsRepository()
+	if err := repo.SaveView(&SavedView{Name: "爆款", Query: "likes>10000", SortBy: "like_count", SortDesc: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveView(&SavedView{Name: "爆款", Query: "likes>50000"}); err != nil {
+		t.Fatal(err)
+	}
+	views, err := repo.ListViews()
+	if err != nil || len(views) != 1 || views[0].Query != "likes>50000" {
+		t.Fatalf("expected view to be updated in place, got %+v %v", views, err)
+	}
+
+	if err := repo.SaveView(&SavedView{Name: "bad", Query: "likes>lots"}); err == nil {
+		t.Error("expected invalid query to be rejected")
+	}
+	if err := repo.SaveView(&SavedView{Name: "bad", Target: "queue"}); err == nil {
+		t.Error("expected invalid target to be rejected")
+	}
+
+	if err := repo.DeleteView("爆款"); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := repo.GetView("爆款"); view != nil {
+		t.Error("expected view to be deleted")
+	}
+	if err := repo.DeleteView("爆款"); err == nil {
+		t.Error("expected error deleting missing view")
+	}
+}
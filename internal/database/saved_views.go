@@ -0,0 +1,118 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 视图适用的记录类型
+const (
+	ViewTargetBrowse    = "browse"
+	ViewTargetDownloads = "downloads"
+)
+
+// SavedView 表示一个已保存的命名视图，以 JSON 数组形式存放在 settings 表的 saved_views 键下
+type SavedView struct {
+	Name      string    `json:"name"`
+	Target    string    `json:"target,omitempty"` // browse、downloads，为空表示两者通用
+	Query     string    `json:"query"`            // 查询语法，见 ParseRecordQuery
+	SortBy    string    `json:"sortBy,omitempty"`
+	SortDesc  bool      `json:"sortDesc"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// savedViewsMu 保护 saved_views 的读-改-写
+var savedViewsMu sync.Mutex
+
+// ListViews 获取所有已保存视图
+func (r *SettingsRepository) ListViews() ([]SavedView, error) {
+	value, err := r.Get(SettingKeySavedViews)
+	if err != nil {
+		return nil, err
+	}
+	views := []SavedView{}
+	if value == "" {
+		return views, nil
+	}
+	if err := json.Unmarshal([]byte(value), &views); err != nil {
+		return nil, fmt.Errorf("failed to parse saved views: %w", err)
+	}
+	return views, nil
+}
+
+// GetView 按名称获取视图，不存在时返回 nil
+func (r *SettingsRepository) GetView(name string) (*SavedView, error) {
+	views, err := r.ListViews()
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		if views[i].Name == name {
+			return &views[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveView 创建或更新（按名称）视图
+func (r *SettingsRepository) SaveView(view *SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" {
+		return fmt.Errorf("view name is required")
+	}
+	if view.Target != "" && view.Target != ViewTargetBrowse && view.Target != ViewTargetDownloads {
+		return fmt.Errorf("view target must be '%s' or '%s'", ViewTargetBrowse, ViewTargetDownloads)
+	}
+	if _, _, err := ParseRecordQuery(view.Query); err != nil {
+		return err
+	}
+	view.UpdatedAt = time.Now()
+
+	savedViewsMu.Lock()
+	defer savedViewsMu.Unlock()
+
+	views, err := r.ListViews()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range views {
+		if views[i].Name == view.Name {
+			views[i] = *view
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		views = append(views, *view)
+	}
+	return r.saveViews(views)
+}
+
+// DeleteView 按名称删除视图
+func (r *SettingsRepository) DeleteView(name string) error {
+	savedViewsMu.Lock()
+	defer savedViewsMu.Unlock()
+
+	views, err := r.ListViews()
+	if err != nil {
+		return err
+	}
+	for i := range views {
+		if views[i].Name == name {
+			return r.saveViews(append(views[:i], views[i+1:]...))
+		}
+	}
+	return fmt.Errorf("view not found: %s", name)
+}
+
+func (r *SettingsRepository) saveViews(views []SavedView) error {
+	data, err := json.Marshal(views)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved views: %w", err)
+	}
+	return r.Set(SettingKeySavedViews, string(data))
+}
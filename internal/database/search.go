@@ -0,0 +1,399 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 搜索结果类型
+const (
+	SearchTypeBrowse   = "browse"
+	SearchTypeDownload = "download"
+	SearchTypeComment  = "comment"
+)
+
+// 全文检索的高亮标记，先用控制字符占位，HTML 转义后再替换为 <mark>
+const (
+	markStart = "\x01"
+	markEnd   = "\x02"
+)
+
+// minTrigramLen trigram 分词要求每个检索词至少 3 个字符，更短时退回 LIKE 匹配
+const minTrigramLen = 3
+
+// SearchHit 表示一条搜索命中，Title/Author/Snippet 为已转义的 HTML，命中部分用 <mark> 包裹
+type SearchHit struct {
+	Type    string    `json:"type"` // browse, download, comment
+	ID      string    `json:"id"`
+	VideoID string    `json:"videoId"`
+	Title   string    `json:"title"`
+	Author  string    `json:"author"`
+	Snippet string    `json:"snippet"`
+	Rank    float64   `json:"rank"` // bm25 分数，越小越相关；LIKE 模式下为 0
+	Time    time.Time `json:"time"`
+}
+
+// SearchHits 表示跨记录类型的搜索结果
+type SearchHits struct {
+	Hits   []SearchHit      `json:"hits"`
+	Counts map[string]int64 `json:"counts"`
+	Total  int64            `json:"total"`
+	Mode   string           `json:"mode"` // fts5 或 like
+}
+
+// searchSource 描述一种可搜索的记录类型
+type searchSource struct {
+	typ     string
+	table   string
+	fts     string
+	columns []string // 全文索引的列，顺序与 FTS 表一致
+	id      string
+	videoID string
+	title   int // columns 中标题列的下标
+	author  int
+	snippet int // 摘要使用的列，-1 表示自动选择
+	time    string
+}
+
+var searchSources = []searchSource{
+	{
+		typ: SearchTypeBrowse, table: "browse_history", fts: "browse_history_fts",
+		columns: []string{"title", "author"}, id: "id", videoID: "id",
+		title: 0, author: 1, snippet: -1, time: "browse_time",
+	},
+	{
+		typ: SearchTypeDownload, table: "download_records", fts: "download_records_fts",
+		columns: []string{"title", "author"}, id: "id", videoID: "video_id",
+		title: 0, author: 1, snippet: -1, time: "download_time",
+	},
+	{
+		typ: SearchTypeComment, table: "comments", fts: "comments_fts",
+		columns: []string{"content", "nickname", "video_title"}, id: "id", videoID: "video_id",
+		title: 2, author: 1, snippet: 0, time: "comment_time",
+	},
+}
+
+// FullTextEnabled 返回全文索引是否可用（需要以 sqlite_fts5 标签构建）
+func FullTextEnabled() bool {
+	if db == nil {
+		return false
+	}
+	var n int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'browse_history_fts'").Scan(&n)
+	return err == nil && n > 0
+}
+
+// searchTerms 按空白拆分检索词
+func searchTerms(query string) []string {
+	return strings.Fields(query)
+}
+
+// ftsMatchQuery 将检索词转换为 FTS5 MATCH 表达式（各词均需命中）
+// 任一检索词短于 trigram 长度时返回 false
+func ftsMatchQuery(terms []string) (string, bool) {
+	if len(terms) == 0 {
+		return "", false
+	}
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		if utf8.RuneCountInString(t) < minTrigramLen {
+			return "", false
+		}
+		quoted[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " "), true
+}
+
+// useFullText 判断本次查询是否走全文索引，返回 MATCH 表达式
+func useFullText(query string) (string, bool) {
+	match, ok := ftsMatchQuery(searchTerms(query))
+	if !ok || !FullTextEnabled() {
+		return "", false
+	}
+	return match, true
+}
+
+// likeCondition 构造 LIKE 退化条件：每个检索词至少命中一列
+func likeCondition(columns []string, terms []string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	for _, t := range terms {
+		pattern := "%" + escapeLike(t) + "%"
+		var ors []string
+		for _, c := range columns {
+			ors = append(ors, c+` LIKE ? ESCAPE '\'`)
+			args = append(args, pattern)
+		}
+		conds = append(conds, "("+strings.Join(ors, " OR ")+")")
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
+
+// markToHTML 转义文本并把占位标记替换为 <mark>
+func markToHTML(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, markStart, "<mark>")
+	return strings.ReplaceAll(s, markEnd, "</mark>")
+}
+
+// highlightTerms 在文本中标记检索词（不区分大小写），用于 LIKE 模式
+func highlightTerms(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	marked := make([]bool, len(text))
+	for _, t := range terms {
+		t = strings.ToLower(t)
+		if t == "" {
+			continue
+		}
+		for start := 0; ; {
+			idx := strings.Index(lower[start:], t)
+			if idx < 0 {
+				break
+			}
+			for i := start + idx; i < start+idx+len(t) && i < len(marked); i++ {
+				marked[i] = true
+			}
+			start += idx + len(t)
+		}
+	}
+
+	var b strings.Builder
+	in := false
+	for i := 0; i < len(text); i++ {
+		if marked[i] != in {
+			if marked[i] {
+				b.WriteString(markStart)
+			} else {
+				b.WriteString(markEnd)
+			}
+			in = marked[i]
+		}
+		b.WriteByte(text[i])
+	}
+	if in {
+		b.WriteString(markEnd)
+	}
+	return b.String()
+}
+
+// snippetAround 截取首个命中附近的文本，用于 LIKE 模式下的摘要
+func snippetAround(text string, terms []string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	lower := strings.ToLower(text)
+	pos := -1
+	for _, t := range terms {
+		if idx := strings.Index(lower, strings.ToLower(t)); idx >= 0 && (pos < 0 || idx < pos) {
+			pos = idx
+		}
+	}
+	start := 0
+	if pos > 0 {
+		start = utf8.RuneCountInString(text[:pos]) - width/4
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+		start = end - width
+	}
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
+
+// SearchRepository 处理跨浏览记录、下载记录和评论的全文搜索
+type SearchRepository struct {
+	db *sql.DB
+}
+
+// NewSearchRepository 创建一个新的 SearchRepository
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{db: GetDB()}
+}
+
+// Search 在所有记录类型中搜索，按相关度排序返回最多 limit 条带高亮的结果
+func (r *SearchRepository) Search(query string, limit int) (*SearchHits, error) {
+	return r.SearchTagged(query, nil, limit)
+}
+
+// SearchTagged 同 Search，但只返回带有全部指定标签的视频相关的命中
+func (r *SearchRepository) SearchTagged(query string, tags []string, limit int) (*SearchHits, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	terms := searchTerms(query)
+	result := &SearchHits{Hits: []SearchHit{}, Counts: map[string]int64{}}
+	if len(terms) == 0 {
+		return result, nil
+	}
+
+	match, fts := useFullText(query)
+	result.Mode = "like"
+	if fts {
+		result.Mode = "fts5"
+	}
+
+	for _, src := range searchSources {
+		var hits []SearchHit
+		var count int64
+		var err error
+		if fts {
+			hits, count, err = r.searchFTS(src, match, tags, limit)
+		} else {
+			hits, count, err = r.searchLike(src, terms, tags, limit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Hits = append(result.Hits, hits...)
+		result.Counts[src.typ] = count
+		result.Total += count
+	}
+
+	// bm25 越小越相关；LIKE 模式下按时间倒序
+	sort.SliceStable(result.Hits, func(i, j int) bool {
+		a, b := result.Hits[i], result.Hits[j]
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.Time.After(b.Time)
+	})
+	if len(result.Hits) > limit {
+		result.Hits = result.Hits[:limit]
+	}
+	return result, nil
+}
+
+func (r *SearchRepository) searchFTS(src searchSource, match string, tags []string, limit int) ([]SearchHit, int64, error) {
+	where := src.fts + " MATCH ?"
+	args := []interface{}{match}
+	if len(tags) > 0 {
+		cond, tagArgs := taggedCondition("t."+src.videoID, tags)
+		where += " AND " + cond
+		args = append(args, tagArgs...)
+	}
+
+	var count int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s JOIN %s t ON t.rowid = %s.rowid WHERE %s", src.fts, src.table, src.fts, where)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("failed to count %s search results: %w", src.typ, err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.%s, t.%s, t.%s,
+			highlight(%s, %d, '%s', '%s'),
+			highlight(%s, %d, '%s', '%s'),
+			snippet(%s, %d, '%s', '%s', '…', 24),
+			bm25(%s)
+		FROM %s
+		JOIN %s t ON t.rowid = %s.rowid
+		WHERE %s
+		ORDER BY bm25(%s)
+		LIMIT ?
+	`, src.id, src.videoID, src.time,
+		src.fts, src.title, markStart, markEnd,
+		src.fts, src.author, markStart, markEnd,
+		src.fts, src.snippet, markStart, markEnd,
+		src.fts,
+		src.fts,
+		src.table, src.fts,
+		where,
+		src.fts)
+
+	rows, err := r.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search %s: %w", src.typ, err)
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		hit := SearchHit{Type: src.typ}
+		var t sql.NullTime
+		if err := rows.Scan(&hit.ID, &hit.VideoID, &t, &hit.Title, &hit.Author, &hit.Snippet, &hit.Rank); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan %s search result: %w", src.typ, err)
+		}
+		hit.Time = t.Time
+		hit.Title = markToHTML(hit.Title)
+		hit.Author = markToHTML(hit.Author)
+		hit.Snippet = markToHTML(hit.Snippet)
+		hits = append(hits, hit)
+	}
+	return hits, count, rows.Err()
+}
+
+func (r *SearchRepository) searchLike(src searchSource, terms []string, tags []string, limit int) ([]SearchHit, int64, error) {
+	where, args := likeCondition(src.columns, terms)
+	if len(tags) > 0 {
+		cond, tagArgs := taggedCondition(src.videoID, tags)
+		where += " AND " + cond
+		args = append(args, tagArgs...)
+	}
+
+	var count int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", src.table, where)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("failed to count %s search results: %w", src.typ, err)
+	}
+
+	query := fmt.Sprintf("SELECT %s, %s, %s, %s FROM %s WHERE %s ORDER BY %s DESC LIMIT ?",
+		src.id, src.videoID, src.time, strings.Join(src.columns, ", "), src.table, where, src.time)
+	rows, err := r.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search %s: %w", src.typ, err)
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		hit := SearchHit{Type: src.typ}
+		var t sql.NullTime
+		values := make([]sql.NullString, len(src.columns))
+		dest := []interface{}{&hit.ID, &hit.VideoID, &t}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan %s search result: %w", src.typ, err)
+		}
+		hit.Time = t.Time
+		hit.Title = markToHTML(highlightTerms(values[src.title].String, terms))
+		hit.Author = markToHTML(highlightTerms(values[src.author].String, terms))
+
+		// 摘要取第一个命中的列
+		snippetCol := src.snippet
+		if snippetCol < 0 {
+			snippetCol = 0
+			for i, v := range values {
+				if highlightTerms(v.String, terms) != v.String {
+					snippetCol = i
+					break
+				}
+			}
+		}
+		text := snippetAround(values[snippetCol].String, terms, 48)
+		hit.Snippet = markToHTML(highlightTerms(text, terms))
+		hits = append(hits, hit)
+	}
+	return hits, count, rows.Err()
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// 未使用 sqlite_fts5 标签构建时走 LIKE 退化路径，两种模式的结果应一致
+func TestSearchRepository_AcrossRecordTypes(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	browseRepo := NewBrowseHistoryRepository()
+	for i, title := range []string{"老表寄来的金矿石提炼黄金", "周末钓鱼 <vlog>"} {
+		if err := browseRepo.Create(&BrowseRecord{
+			ID: "b" + string(rune('1'+i)), Title: title, Author: "淘金老王",
+			BrowseTime: now.Add(-time.Duration(i) * time.Hour),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	downloadRepo := NewDownloadRecordRepository()
+	if err := downloadRepo.Create(&DownloadRecord{
+		ID: "d1", VideoID: "b1", Title: "老表寄来的金矿石提炼黄金", Author: "淘金老王",
+		Status: DownloadStatusCompleted, DownloadTime: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	commentRepo := NewCommentRepository()
+	if err := commentRepo.SaveBatch([]CommentRecord{
+		{ID: "c1", VideoID: "b1", VideoTitle: "老表寄来的金矿石", Nickname: "路人甲", Content: "这一堆能提炼黄金吗？感觉不太行"},
+		{ID: "c2", VideoID: "b1", VideoTitle: "老表寄来的金矿石", Nickname: "路人乙", Content: "支持一下"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewSearchRepository()
+	result, err := repo.Search("提炼黄金", 20)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	wantMode := "like"
+	if FullTextEnabled() {
+		wantMode = "fts5"
+	}
+	if result.Mode != wantMode {
+		t.Errorf("expected mode %s, got %s", wantMode, result.Mode)
+	}
+	if result.Counts[SearchTypeBrowse] != 1 || result.Counts[SearchTypeDownload] != 1 || result.Counts[SearchTypeComment] != 1 {
+		t.Fatalf("unexpected counts: %v", result.Counts)
+	}
+	for _, hit := range result.Hits {
+		if !strings.Contains(hit.Title+hit.Snippet, "<mark>") {
+			t.Errorf("expected highlighted match in %+v", hit)
+		}
+	}
+
+	// 评论更新后索引同步
+	if err := commentRepo.SaveBatch([]CommentRecord{{ID: "c2", VideoID: "b1", Nickname: "路人乙", Content: "提炼黄金的步骤好详细"}}); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = repo.Search("提炼黄金", 20)
+	if result.Counts[SearchTypeComment] != 2 {
+		t.Errorf("expected 2 comment hits after update, got %d", result.Counts[SearchTypeComment])
+	}
+
+	// INSERT OR REPLACE 覆盖下载记录不会留下重复索引
+	if err := downloadRepo.Create(&DownloadRecord{
+		ID: "d1", VideoID: "b1", Title: "老表寄来的金矿石提炼黄金", Author: "淘金老王",
+		Status: DownloadStatusCompleted, DownloadTime: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = repo.Search("提炼黄金", 20)
+	if result.Counts[SearchTypeDownload] != 1 {
+		t.Errorf("expected 1 download hit after replace, got %d", result.Counts[SearchTypeDownload])
+	}
+
+	// 删除后不再命中
+	if err := browseRepo.Delete("b1"); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = repo.Search("提炼黄金", 20)
+	if result.Counts[SearchTypeBrowse] != 0 {
+		t.Errorf("expected deleted browse record to disappear, got %d", result.Counts[SearchTypeBrowse])
+	}
+
+	// 标题中的 HTML 需要转义
+	result, _ = repo.Search("vlog", 20)
+	if len(result.Hits) != 1 || !strings.Contains(result.Hits[0].Title, "&lt;<mark>vlog</mark>&gt;") {
+		t.Errorf("expected escaped highlighted title, got %+v", result.Hits)
+	}
+
+	// 两个字的中文检索词短于 trigram，退回 LIKE 仍能命中
+	result, err = repo.Search("钓鱼", 20)
+	if err != nil || result.Counts[SearchTypeBrowse] != 1 || result.Mode != "like" {
+		t.Errorf("expected short query to fall back to LIKE, got %+v, %v", result, err)
+	}
+}
@@ -27,6 +27,9 @@ const (
 	SettingKeyMaxRetries         = "max_retries"
 	SettingKeyRadarEnabled       = "radar_enabled"
 	SettingKeyTheme              = "theme"
+	SettingKeySavedViews         = "saved_views"
+	SettingKeyRadarFilter        = "radar_filter"
+	SettingKeyRadarBreaker       = "radar_breaker"
 )
 
 // Get 根据键获取设置值
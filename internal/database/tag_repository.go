@@ -0,0 +1,252 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"wx_channel/internal/utils"
+)
+
+// Tag 表示一个用户自定义标签
+type Tag struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Color      string    `json:"color"`
+	VideoCount int64     `json:"videoCount"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TagRepository 处理标签数据库操作
+type TagRepository struct {
+	db *sql.DB
+}
+
+// NewTagRepository 创建一个新的 TagRepository
+func NewTagRepository() *TagRepository {
+	return &TagRepository{db: GetDB()}
+}
+
+// normalizeTagNames 去除空白和重复（不区分大小写）
+func normalizeTagNames(names []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		key := strings.ToLower(n)
+		if n == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, n)
+	}
+	return result
+}
+
+// List 获取所有标签及其关联的视频数
+func (r *TagRepository) List() ([]Tag, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.name, t.color, t.created_at, COUNT(vt.video_id)
+		FROM tags t
+		LEFT JOIN video_tags vt ON vt.tag_id = t.id
+		GROUP BY t.id
+		ORDER BY t.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+	return scanTags(rows)
+}
+
+// ListByVideo 获取某个视频的标签
+func (r *TagRepository) ListByVideo(videoID string) ([]Tag, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.name, t.color, t.created_at,
+			(SELECT COUNT(*) FROM video_tags c WHERE c.tag_id = t.id)
+		FROM tags t
+		JOIN video_tags vt ON vt.tag_id = t.id
+		WHERE vt.video_id = ?
+		ORDER BY t.name
+	`, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list video tags: %w", err)
+	}
+	defer rows.Close()
+	return scanTags(rows)
+}
+
+func scanTags(rows *sql.Rows) ([]Tag, error) {
+	tags := []Tag{}
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Color, &t.CreatedAt, &t.VideoCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// GetByID 根据 ID 获取标签，不存在时返回 nil
+func (r *TagRepository) GetByID(id string) (*Tag, error) {
+	t := &Tag{}
+	err := r.db.QueryRow(`
+		SELECT id, name, color, created_at, (SELECT COUNT(*) FROM video_tags WHERE tag_id = tags.id)
+		FROM tags WHERE id = ?
+	`, id).Scan(&t.ID, &t.Name, &t.Color, &t.CreatedAt, &t.VideoCount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return t, nil
+}
+
+// Create 创建标签，名称不区分大小写且唯一
+func (r *TagRepository) Create(tag *Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return fmt.Errorf("tag name is required")
+	}
+	if tag.ID == "" {
+		tag.ID = utils.RandomString(12)
+	}
+	tag.CreatedAt = time.Now()
+	if _, err := r.db.Exec("INSERT INTO tags (id, name, color, created_at) VALUES (?, ?, ?, ?)",
+		tag.ID, tag.Name, tag.Color, tag.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	return nil
+}
+
+// Update 重命名标签或修改颜色
+func (r *TagRepository) Update(tag *Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return fmt.Errorf("tag name is required")
+	}
+	result, err := r.db.Exec("UPDATE tags SET name = ?, color = ? WHERE id = ?", tag.Name, tag.Color, tag.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("tag not found: %s", tag.ID)
+	}
+	return nil
+}
+
+// Delete 删除标签及其所有关联
+func (r *TagRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM tags WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("tag not found: %s", id)
+	}
+	return nil
+}
+
+// ensureTags 按名称获取标签 ID，不存在的标签会被创建
+func ensureTags(tx *sql.Tx, names []string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		var id string
+		err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", name).Scan(&id)
+		if err == sql.ErrNoRows {
+			id = utils.RandomString(12)
+			_, err = tx.Exec("INSERT INTO tags (id, name, created_at) VALUES (?, ?, ?)", id, name, time.Now())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %s: %w", name, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// TagVideos 给多个视频批量添加标签（按名称，不存在时自动创建），返回新增的关联数
+func (r *TagRepository) TagVideos(videoIDs, tagNames []string) (int64, error) {
+	tagNames = normalizeTagNames(tagNames)
+	if len(videoIDs) == 0 || len(tagNames) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tagIDs, err := ensureTags(tx, tagNames)
+	if err != nil {
+		return 0, err
+	}
+
+	var added int64
+	now := time.Now()
+	for _, videoID := range videoIDs {
+		for _, tagID := range tagIDs {
+			result, err := tx.Exec("INSERT OR IGNORE INTO video_tags (video_id, tag_id, created_at) VALUES (?, ?, ?)",
+				videoID, tagID, now)
+			if err != nil {
+				return 0, fmt.Errorf("failed to tag video %s: %w", videoID, err)
+			}
+			n, _ := result.RowsAffected()
+			added += n
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit tags: %w", err)
+	}
+	return added, nil
+}
+
+// UntagVideos 移除多个视频上的指定标签，返回删除的关联数
+func (r *TagRepository) UntagVideos(videoIDs, tagNames []string) (int64, error) {
+	tagNames = normalizeTagNames(tagNames)
+	if len(videoIDs) == 0 || len(tagNames) == 0 {
+		return 0, nil
+	}
+
+	args := make([]interface{}, 0, len(videoIDs)+len(tagNames))
+	for _, id := range videoIDs {
+		args = append(args, id)
+	}
+	for _, name := range tagNames {
+		args = append(args, name)
+	}
+	query := fmt.Sprintf(`
+		DELETE FROM video_tags
+		WHERE video_id IN (%s) AND tag_id IN (SELECT id FROM tags WHERE name IN (%s))
+	`, placeholders(len(videoIDs)), placeholders(len(tagNames)))
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to untag videos: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// placeholders 生成 n 个以逗号分隔的 ? 占位符
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?,", n-1) + "?"
+}
+
+// taggedCondition 生成"视频带有全部指定标签"的条件，column 为视频 ID 列
+func taggedCondition(column string, tags []string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	for _, name := range normalizeTagNames(tags) {
+		conds = append(conds, column+" IN (SELECT vt.video_id FROM video_tags vt JOIN tags tg ON tg.id = vt.tag_id WHERE tg.name = ?)")
+		args = append(args, name)
+	}
+	return strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagsAndCollections(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	browseRepo := NewBrowseHistoryRepository()
+	for _, id := range []string{"v1", "v2", "v3"} {
+		if err := browseRepo.Create(&BrowseRecord{ID: id, Title: "项目素材 " + id, Author: "老王", BrowseTime: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := NewDownloadRecordRepository().Create(&DownloadRecord{
+		ID: "d1", VideoID: "v1", Title: "项目素材 v1", Author: "老王", FilePath: "/videos/v1.mp4",
+		Status: DownloadStatusCompleted, DownloadTime: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := NewTagRepository()
+	added, err := tags.TagVideos([]string{"v1", "v2"}, []string{"项目A", " 项目a ", "B-roll"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 4 {
+		t.Errorf("expected 4 new links (tag names are case-insensitive), got %d", added)
+	}
+	if added, _ = tags.TagVideos([]string{"v1"}, []string{"项目A"}); added != 0 {
+		t.Errorf("expected tagging twice to be a no-op, got %d", added)
+	}
+
+	list, err := tags.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("expected 2 tags, got %+v %v", list, err)
+	}
+	if err := tags.Create(&Tag{Name: "b-ROLL"}); err == nil {
+		t.Error("expected duplicate tag name to be rejected")
+	}
+
+	if removed, _ := tags.UntagVideos([]string{"v2"}, []string{"B-roll"}); removed != 1 {
+		t.Errorf("expected 1 link removed, got %d", removed)
+	}
+	videoTags, _ := tags.ListByVideo("v2")
+	if len(videoTags) != 1 || videoTags[0].Name != "项目A" {
+		t.Errorf("unexpected tags for v2: %+v", videoTags)
+	}
+
+	// 列表和下载记录按标签过滤
+	result, err := browseRepo.ListFiltered(&FilterParams{Filter: &RecordFilter{Tags: []string{"项目A", "B-roll"}}})
+	if err != nil || result.Total != 1 || result.Items[0].ID != "v1" {
+		t.Errorf("expected only v1 with both tags, got %+v %v", result, err)
+	}
+	downloads, err := NewDownloadRecordRepository().FindAll(&FilterParams{Filter: &RecordFilter{Tags: []string{"项目a"}}})
+	if err != nil || len(downloads) != 1 {
+		t.Errorf("expected download of tagged video, got %+v %v", downloads, err)
+	}
+
+	// 全局搜索按标签过滤
+	hits, err := NewSearchRepository().SearchTagged("项目素材", []string{"项目A"}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits.Counts[SearchTypeBrowse] != 2 || hits.Counts[SearchTypeDownload] != 1 {
+		t.Errorf("unexpected tagged search counts: %v", hits.Counts)
+	}
+
+	// 删除标签级联删除关联
+	for _, tag := range list {
+		if tag.Name == "B-roll" {
+			if err := tags.Delete(tag.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if videoTags, _ := tags.ListByVideo("v1"); len(videoTags) != 1 {
+		t.Errorf("expected cascade delete, got %+v", videoTags)
+	}
+
+	collections := NewCollectionRepository()
+	c := &Collection{Name: "成片"}
+	if err := collections.Create(c); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := collections.AddItems(c.ID, []string{"v3", "v1", "v3"}); n != 2 {
+		t.Errorf("expected 2 items added, got %d", n)
+	}
+	collections.AddItems(c.ID, []string{"v2"})
+	items, err := collections.Items(c.ID)
+	if err != nil || len(items) != 3 {
+		t.Fatalf("expected 3 items, got %+v %v", items, err)
+	}
+	if items[0].VideoID != "v3" || items[1].VideoID != "v1" || items[2].VideoID != "v2" {
+		t.Errorf("expected insertion order, got %+v", items)
+	}
+	if items[1].FilePath != "/videos/v1.mp4" || items[0].FilePath != "" {
+		t.Errorf("expected file path from completed download, got %+v", items)
+	}
+
+	result, _ = browseRepo.ListFiltered(&FilterParams{Filter: &RecordFilter{Collection: "成片", Tags: []string{"项目A"}}})
+	if result.Total != 2 {
+		t.Errorf("expected 2 records in collection with tag, got %d", result.Total)
+	}
+
+	if removed, _ := collections.RemoveItems(c.ID, []string{"v1"}); removed != 1 {
+		t.Errorf("expected 1 item removed, got %d", removed)
+	}
+	if err := collections.Delete(c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ := collections.Items(c.ID); len(items) != 0 {
+		t.Errorf("expected items to be deleted with collection, got %+v", items)
+	}
+}
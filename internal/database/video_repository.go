@@ -0,0 +1,153 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Video 表示一个视频的规范化元数据，浏览、下载、队列记录通过视频 ID 引用它
+type Video struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	AuthorID     string    `json:"authorId"`
+	AuthorName   string    `json:"authorName"`
+	CoverURL     string    `json:"coverUrl"`
+	Duration     int64     `json:"duration"`
+	Size         int64     `json:"size"`
+	Resolution   string    `json:"resolution"`
+	FileFormat   string    `json:"fileFormat"`
+	LikeCount    int64     `json:"likeCount"`
+	CommentCount int64     `json:"commentCount"`
+	FavCount     int64     `json:"favCount"`
+	ForwardCount int64     `json:"forwardCount"`
+	PageURL      string    `json:"pageUrl"`
+	NonceID      string    `json:"nonceId"`
+	PublishedAt  int64     `json:"publishedAt"`  // 发布时间（Unix 秒），0 表示未知
+	StatsTracked bool      `json:"statsTracked"` // 是否参与互动数据定时重新拉取
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// VideoRepository 处理视频元数据数据库操作
+type VideoRepository struct {
+	db *sql.DB
+}
+
+// NewVideoRepository 创建一个新的 VideoRepository
+func NewVideoRepository() *VideoRepository {
+	return &VideoRepository{db: GetDB()}
+}
+
+const videoColumns = `
+	id, title, author_id, author_name, cover_url, duration, size, resolution, file_format,
+	like_count, comment_count, fav_count, forward_count, page_url, nonce_id, published_at, stats_tracked, created_at, updated_at
+`
+
+func scanVideo(scanner interface{ Scan(...interface{}) error }) (*Video, error) {
+	v := &Video{}
+	if err := scanner.Scan(&v.ID, &v.Title, &v.AuthorID, &v.AuthorName, &v.CoverURL, &v.Duration, &v.Size,
+		&v.Resolution, &v.FileFormat, &v.LikeCount, &v.CommentCount, &v.FavCount, &v.ForwardCount,
+		&v.PageURL, &v.NonceID, &v.PublishedAt, &v.StatsTracked, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Upsert 写入视频元数据，已存在时按 videoUpsertMergeSet 合并（空值和 0 不会覆盖已有数据）
+func (r *VideoRepository) Upsert(v *Video) error {
+	if v.ID == "" {
+		return fmt.Errorf("video id is required")
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO videos (id, title, author_id, author_name, cover_url, duration, size, resolution, file_format,
+			like_count, comment_count, fav_count, forward_count, page_url, nonce_id, published_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET `+videoUpsertMergeSet,
+		v.ID, v.Title, v.AuthorID, v.AuthorName, v.CoverURL, v.Duration, v.Size, v.Resolution, v.FileFormat,
+		v.LikeCount, v.CommentCount, v.FavCount, v.ForwardCount, v.PageURL, v.NonceID, v.PublishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert video: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据视频 ID 获取元数据
+func (r *VideoRepository) GetByID(id string) (*Video, error) {
+	v, err := scanVideo(r.db.QueryRow("SELECT "+videoColumns+" FROM videos WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video: %w", err)
+	}
+	return v, nil
+}
+
+// Exists 判断视频是否已有元数据（浏览、下载或雷达扫描时见过）
+func (r *VideoRepository) Exists(id string) (bool, error) {
+	var n int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM videos WHERE id = ?", id).Scan(&n); err != nil {
+		return false, fmt.Errorf("failed to check video: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetStatsTracked 设置视频是否参与互动数据定时重新拉取
+func (r *VideoRepository) SetStatsTracked(id string, tracked bool) error {
+	result, err := r.db.Exec("UPDATE videos SET stats_tracked = ? WHERE id = ?", tracked, id)
+	if err != nil {
+		return fmt.Errorf("failed to set stats tracking: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("video not found: %s", id)
+	}
+	return nil
+}
+
+// MarkStatsAttempted 记录尝试重新拉取互动数据的时间，拉取失败也记录，避免一直失败的视频占满每一轮
+func (r *VideoRepository) MarkStatsAttempted(id string, at time.Time) error {
+	if _, err := r.db.Exec("UPDATE videos SET stats_attempted_at = ? WHERE id = ?", at.UTC().Format(time.RFC3339), id); err != nil {
+		return fmt.Errorf("failed to mark stats attempt: %w", err)
+	}
+	return nil
+}
+
+// ListStatsTracked 获取参与定时重新拉取的视频，从未尝试过的排在最前，其余按上次尝试时间排列
+func (r *VideoRepository) ListStatsTracked(limit int) ([]Video, error) {
+	rows, err := r.db.Query("SELECT "+videoColumns+` FROM videos WHERE stats_tracked = 1
+		ORDER BY stats_attempted_at ASC,
+			(SELECT MAX(captured_at) FROM video_stats_snapshots s WHERE s.video_id = videos.id) ASC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked videos: %w", err)
+	}
+	defer rows.Close()
+	return scanVideos(rows)
+}
+
+// ListByAuthor 获取某个作者的视频，按首次发现时间倒序
+func (r *VideoRepository) ListByAuthor(authorID string, limit int) ([]Video, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.db.Query("SELECT "+videoColumns+" FROM videos WHERE author_id = ? ORDER BY created_at DESC LIMIT ?",
+		authorID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list videos by author: %w", err)
+	}
+	defer rows.Close()
+	return scanVideos(rows)
+}
+
+func scanVideos(rows *sql.Rows) ([]Video, error) {
+	videos := []Video{}
+	for rows.Next() {
+		v, err := scanVideo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan video: %w", err)
+		}
+		videos = append(videos, *v)
+	}
+	return videos, rows.Err()
+}
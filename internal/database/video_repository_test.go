@@ -0,0 +1,89 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVideoRepository_MergesRecordSources(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	if err := NewBrowseHistoryRepository().Create(&BrowseRecord{
+		ID: "v1", Title: "金矿石提炼", Author: "淘金老王", AuthorID: "v2_laowang@finder",
+		Duration: 61000, CoverURL: "https://cover/1.jpg", BrowseTime: now,
+		LikeCount: 120, CommentCount: 8, PageURL: "https://channels.weixin.qq.com/web/pages/feed",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 队列项目没有封面和互动数据，不应覆盖浏览记录已写入的信息
+	if err := NewQueueRepository().Add(&QueueItem{
+		ID: "q1", VideoID: "v1", Title: "金矿石提炼", Author: "淘金老王", VideoURL: "https://video/1",
+		TotalSize: 2048, Status: QueueStatusPending, AddedTime: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	queued, _ := NewQueueRepository().GetByID("q1")
+	if queued == nil || queued.AuthorID != "v2_laowang@finder" {
+		t.Errorf("expected queue item author id filled from videos, got %+v", queued)
+	}
+
+	downloadRepo := NewDownloadRecordRepository()
+	if err := downloadRepo.Create(&DownloadRecord{
+		ID: "d1", VideoID: "v1", Title: "金矿石提炼", Author: "淘金老王",
+		Status: DownloadStatusCompleted, DownloadTime: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	record, _ := downloadRepo.GetByID("d1")
+	if record == nil || record.AuthorID != "v2_laowang@finder" {
+		t.Errorf("expected download author id filled from videos, got %+v", record)
+	}
+
+	video, err := NewVideoRepository().GetByID("v1")
+	if err != nil || video == nil {
+		t.Fatalf("GetByID: %v, %v", video, err)
+	}
+	if video.CoverURL != "https://cover/1.jpg" || video.LikeCount != 120 || video.Size != 2048 || video.Duration != 61000 {
+		t.Errorf("unexpected merged video: %+v", video)
+	}
+
+	// 只带统计数字的写入不会清空其他字段
+	if err := NewVideoRepository().Upsert(&Video{ID: "v1", LikeCount: 300}); err != nil {
+		t.Fatal(err)
+	}
+	video, _ = NewVideoRepository().GetByID("v1")
+	if video.LikeCount != 300 || video.CommentCount != 8 || video.Title != "金矿石提炼" {
+		t.Errorf("expected upsert to merge counts only, got %+v", video)
+	}
+
+	author, _ := NewAuthorRepository().GetByID("v2_laowang@finder")
+	if author == nil || author.Nickname != "淘金老王" {
+		t.Fatalf("expected author created from browse record, got %+v", author)
+	}
+	if err := NewAuthorRepository().Upsert(&Author{ID: author.ID, AvatarURL: "https://avatar/1.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	author, _ = NewAuthorRepository().GetByID(author.ID)
+	if author.Nickname != "淘金老王" || author.AvatarURL != "https://avatar/1.jpg" {
+		t.Errorf("expected author fields merged, got %+v", author)
+	}
+
+	stats, err := NewAuthorRepository().Stats(author.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.VideoCount != 1 || stats.BrowseCount != 1 || stats.DownloadCount != 1 || stats.TotalLikes != 300 {
+		t.Errorf("unexpected author stats: %+v", stats)
+	}
+
+	videos, err := NewVideoRepository().ListByAuthor(author.ID, 10)
+	if err != nil || len(videos) != 1 {
+		t.Errorf("expected 1 video for author, got %d, %v", len(videos), err)
+	}
+	if missing, _ := NewVideoRepository().GetByID("nope"); missing != nil {
+		t.Errorf("expected nil for unknown video, got %+v", missing)
+	}
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// 互动数据快照来源
+const (
+	StatsSourcePage   = "page"   // 浏览视频页面时采集
+	StatsSourceRadar  = "radar"  // 雷达扫描 feed_list
+	StatsSourceRepoll = "repoll" // 定时通过 feed_profile 重新拉取
+)
+
+// StatsSnapshot 表示某一时刻视频的互动数据
+type StatsSnapshot struct {
+	ID           int64     `json:"id"`
+	VideoID      string    `json:"videoId"`
+	Source       string    `json:"source"`
+	LikeCount    int64     `json:"likeCount"`
+	CommentCount int64     `json:"commentCount"`
+	FavCount     int64     `json:"favCount"`
+	ForwardCount int64     `json:"forwardCount"`
+	CapturedAt   time.Time `json:"capturedAt"`
+}
+
+// VideoStatsRepository 处理互动数据快照数据库操作
+type VideoStatsRepository struct {
+	db *sql.DB
+}
+
+// NewVideoStatsRepository 创建一个新的 VideoStatsRepository
+func NewVideoStatsRepository() *VideoStatsRepository {
+	return &VideoStatsRepository{db: GetDB()}
+}
+
+// Add 追加一条快照；互动数据全为 0 时视为未采集到，直接忽略
+func (r *VideoStatsRepository) Add(s *StatsSnapshot) error {
+	if s.VideoID == "" || (s.LikeCount == 0 && s.CommentCount == 0 && s.FavCount == 0 && s.ForwardCount == 0) {
+		return nil
+	}
+	if s.CapturedAt.IsZero() {
+		s.CapturedAt = time.Now()
+	}
+	// 统一本地时区存储，保证按字符串比较时间时顺序正确
+	s.CapturedAt = s.CapturedAt.Local()
+	result, err := r.db.Exec(`
+		INSERT INTO video_stats_snapshots (video_id, source, like_count, comment_count, fav_count, forward_count, captured_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, s.VideoID, s.Source, s.LikeCount, s.CommentCount, s.FavCount, s.ForwardCount, s.CapturedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add stats snapshot: %w", err)
+	}
+	s.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// History 按时间正序返回视频在 [since, until] 内的快照，零值时间表示不限
+func (r *VideoStatsRepository) History(videoID string, since, until time.Time, limit int) ([]StatsSnapshot, error) {
+	query := `
+		SELECT id, video_id, source, like_count, comment_count, fav_count, forward_count, captured_at
+		FROM video_stats_snapshots WHERE video_id = ?`
+	args := []interface{}{videoID}
+	if !since.IsZero() {
+		query += " AND captured_at >= ?"
+		args = append(args, since.Local())
+	}
+	if !until.IsZero() {
+		query += " AND captured_at <= ?"
+		args = append(args, until.Local())
+	}
+	// 超出 limit 时保留最近的点
+	query = "SELECT * FROM (" + query + " ORDER BY captured_at DESC LIMIT ?) ORDER BY captured_at ASC"
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats history: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []StatsSnapshot{}
+	for rows.Next() {
+		var s StatsSnapshot
+		if err := rows.Scan(&s.ID, &s.VideoID, &s.Source, &s.LikeCount, &s.CommentCount,
+			&s.FavCount, &s.ForwardCount, &s.CapturedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan stats snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVideoStatsRepository_History(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewVideoStatsRepository()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	for i, likes := range []int64{10, 25, 60, 140} {
+		if err := repo.Add(&StatsSnapshot{
+			VideoID: "v1", Source: StatsSourceRadar, LikeCount: likes, CommentCount: int64(i),
+			CapturedAt: base.Add(time.Duration(i) * time.Hour),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 全为 0 的数据视为未采集到，不写入
+	if err := repo.Add(&StatsSnapshot{VideoID: "v1", Source: StatsSourcePage, CapturedAt: base.Add(5 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := repo.History("v1", time.Time{}, time.Time{}, 100)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(points) != 4 || points[0].LikeCount != 10 || points[3].LikeCount != 140 {
+		t.Fatalf("expected 4 ascending points, got %+v", points)
+	}
+	if !points[1].CapturedAt.Equal(base.Add(time.Hour)) {
+		t.Errorf("unexpected captured time %v", points[1].CapturedAt)
+	}
+
+	points, _ = repo.History("v1", base.Add(time.Hour), base.Add(2*time.Hour), 100)
+	if len(points) != 2 || points[0].LikeCount != 25 || points[1].LikeCount != 60 {
+		t.Errorf("expected range to select 2 points, got %+v", points)
+	}
+
+	// 超出 limit 时保留最近的点，仍按时间正序
+	points, _ = repo.History("v1", time.Time{}, time.Time{}, 2)
+	if len(points) != 2 || points[0].LikeCount != 60 || points[1].LikeCount != 140 {
+		t.Errorf("expected latest 2 points, got %+v", points)
+	}
+
+	if points, _ = repo.History("other", time.Time{}, time.Time{}, 10); len(points) != 0 {
+		t.Errorf("expected no points for unknown video, got %d", len(points))
+	}
+}
+
+func TestVideoRepository_StatsTracking(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	videos := NewVideoRepository()
+	for _, id := range []string{"v1", "v2", "v3"} {
+		if err := videos.Upsert(&Video{ID: id, Title: id, NonceID: "nonce_" + id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := videos.SetStatsTracked("v1", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := videos.SetStatsTracked("v2", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := videos.SetStatsTracked("missing", true); err == nil {
+		t.Error("expected error when tracking unknown video")
+	}
+
+	// v1 刚有快照，v2 从未拉取过，应排在前面
+	if err := NewVideoStatsRepository().Add(&StatsSnapshot{VideoID: "v1", Source: StatsSourceRepoll, LikeCount: 1}); err != nil {
+		t.Fatal(err)
+	}
+	tracked, err := videos.ListStatsTracked(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracked) != 2 || tracked[0].ID != "v2" || tracked[0].NonceID != "nonce_v2" || !tracked[0].StatsTracked {
+		t.Errorf("unexpected tracked videos: %+v", tracked)
+	}
+
+	// v2 拉取失败（没有新快照）后按尝试时间排到 v1 之后，不会一直占用前面的位置
+	if err := videos.MarkStatsAttempted("v2", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	tracked, _ = videos.ListStatsTracked(10)
+	if len(tracked) != 2 || tracked[0].ID != "v1" {
+		t.Errorf("expected never attempted v1 first, got %+v", tracked)
+	}
+	if err := videos.MarkStatsAttempted("v1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	tracked, _ = videos.ListStatsTracked(1)
+	if len(tracked) != 1 || tracked[0].ID != "v2" {
+		t.Errorf("expected least recently attempted v2 first, got %+v", tracked)
+	}
+}
@@ -1,16 +1,20 @@
 package handlers
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"wx_channel/internal/config"
+	"wx_channel/internal/database"
 	"wx_channel/internal/utils"
 
 	"github.com/qtgolang/SunnyNet/SunnyNet"
@@ -205,9 +209,82 @@ func (h *CommentHandler) saveCommentData(comments []map[string]interface{}, vide
 	// 记录详细评论采集日志
 	utils.LogComment(videoID, videoTitle, totalComments, true)
 
+	// 同步写入数据库，供全文搜索使用（数据库未初始化时仅保存文件）
+	if database.GetDB() != nil {
+		if err := database.NewCommentRepository().SaveBatch(commentRecords(comments, videoID, videoTitle)); err != nil {
+			utils.Warn("评论写入数据库失败: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// commentRecords 将前端提交的评论（含二级评论）展开为数据库记录
+func commentRecords(comments []map[string]interface{}, videoID, videoTitle string) []database.CommentRecord {
+	var records []database.CommentRecord
+	var add func(c map[string]interface{}, parentID string)
+	add = func(c map[string]interface{}, parentID string) {
+		content := stringField(c, "content")
+		nickname := stringField(c, "nickname")
+		id := stringField(c, "id")
+		if id == "" {
+			// 缺少评论 ID 时按内容生成稳定 ID，避免重复采集产生重复记录
+			sum := sha1.Sum([]byte(parentID + nickname + content))
+			id = hex.EncodeToString(sum[:])
+		}
+		record := database.CommentRecord{
+			ID:         videoID + ":" + id,
+			VideoID:    videoID,
+			VideoTitle: videoTitle,
+			Nickname:   nickname,
+			Content:    content,
+			LikeCount:  int64(numberField(c, "likeCount")),
+		}
+		if parentID != "" {
+			record.ParentID = videoID + ":" + parentID
+		}
+		if ts := int64(numberField(c, "createTime")); ts > 0 {
+			record.CommentTime = time.Unix(ts, 0)
+		}
+		records = append(records, record)
+
+		if replies, ok := c["levelTwoComment"].([]interface{}); ok {
+			for _, reply := range replies {
+				if m, ok := reply.(map[string]interface{}); ok {
+					add(m, id)
+				}
+			}
+		}
+	}
+	for _, c := range comments {
+		add(c, "")
+	}
+	return records
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func numberField(m map[string]interface{}, key string) float64 {
+	switch v := m[key].(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
 // sendEmptyResponse 发送空JSON响应
 func (h *CommentHandler) sendEmptyResponse(Conn *SunnyNet.HttpConn) {
 	headers := http.Header{}
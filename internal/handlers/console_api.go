@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -18,6 +19,7 @@ import (
 
 	"wx_channel/internal/config"
 	"wx_channel/internal/database"
+	"wx_channel/internal/response"
 	"wx_channel/internal/services"
 	"wx_channel/internal/utils"
 	"wx_channel/internal/websocket"
@@ -32,6 +34,7 @@ type ConsoleAPIHandler struct {
 	statsService    *services.StatisticsService
 	exportService   *services.ExportService
 	searchService   *services.SearchService
+	viewService     *services.ViewService
 	wsHub           *websocket.Hub
 	radarService    *services.RadarService
 }
@@ -48,6 +51,7 @@ func NewConsoleAPIHandler(cfg *config.Config, wsHub *websocket.Hub, radarService
 		statsService:    services.NewStatisticsService(),
 		exportService:   services.NewExportService(),
 		searchService:   services.NewSearchService(),
+		viewService:     services.NewViewService(),
 		wsHub:           wsHub,
 		radarService:    radarService,
 	}
@@ -170,16 +174,35 @@ func getPaginationParams(r *http.Request) *database.PaginationParams {
 	if sortDesc := r.URL.Query().Get("sortDesc"); sortDesc != "" {
 		params.SortDesc = sortDesc == "true" || sortDesc == "1"
 	}
+	// 带 cursor 参数（可为空，表示第一页）时使用游标分页
+	if r.URL.Query().Has("cursor") {
+		params.Keyset = true
+		params.Cursor = r.URL.Query().Get("cursor")
+	}
+	if skipTotal := r.URL.Query().Get("skipTotal"); skipTotal != "" {
+		params.SkipTotal = skipTotal == "true" || skipTotal == "1"
+	}
 
 	return params
 }
 
+// sendListError 返回列表查询错误，游标无效时为 400
+func (h *ConsoleAPIHandler) sendListError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, database.ErrInvalidCursor) {
+		h.sendError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendError(w, r, http.StatusInternalServerError, err.Error())
+}
+
 // getFilterParams 从查询字符串中提取过滤参数
 func getFilterParams(r *http.Request) *database.FilterParams {
 	params := &database.FilterParams{
 		PaginationParams: *getPaginationParams(r),
 	}
-	params.SortBy = "download_time"
+	if r.URL.Query().Get("sortBy") == "" {
+		params.SortBy = "download_time"
+	}
 
 	if startDate := r.URL.Query().Get("startDate"); startDate != "" {
 		if t, err := time.Parse("2006-01-02", startDate); err == nil {
@@ -203,6 +226,12 @@ func getFilterParams(r *http.Request) *database.FilterParams {
 	return params
 }
 
+// resolveRecordFilter 解析 query 中的结构化条件（如 author:xx likes>1000 dur<60s），
+// 并合并 view 参数指定的已保存视图
+func (h *ConsoleAPIHandler) resolveRecordFilter(r *http.Request, params *database.FilterParams, target string) error {
+	return h.viewService.ResolveFilter(params, r.URL.Query().Get("view"), target, r.URL.Query().Get("sortBy") != "")
+}
+
 // extractIDFromPath 从 URL 路径中提取 ID，例如 /api/browse/123
 func extractIDFromPath(path, prefix string) string {
 	path = strings.TrimPrefix(path, prefix)
@@ -225,20 +254,18 @@ func (h *ConsoleAPIHandler) HandleBrowseList(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	params := getPaginationParams(r)
-	query := r.URL.Query().Get("query")
-
-	var result *database.PagedResult[database.BrowseRecord]
-	var err error
-
-	if query != "" {
-		result, err = h.browseService.Search(query, params)
-	} else {
-		result, err = h.browseService.List(params)
+	params := getFilterParams(r)
+	if r.URL.Query().Get("sortBy") == "" {
+		params.SortBy = "browse_time"
+	}
+	if err := h.resolveRecordFilter(r, params, database.ViewTargetBrowse); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, err.Error())
+		return
 	}
 
+	result, err := h.browseService.Filter(params)
 	if err != nil {
-		h.sendError(w, r, http.StatusInternalServerError, err.Error())
+		h.sendListError(w, r, err)
 		return
 	}
 
@@ -372,9 +399,14 @@ func (h *ConsoleAPIHandler) HandleDownloadsList(w http.ResponseWriter, r *http.R
 	}
 
 	params := getFilterParams(r)
+	if err := h.resolveRecordFilter(r, params, database.ViewTargetDownloads); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	result, err := h.downloadService.List(params)
 	if err != nil {
-		h.sendError(w, r, http.StatusInternalServerError, err.Error())
+		h.sendListError(w, r, err)
 		return
 	}
 
@@ -490,6 +522,18 @@ func (h *ConsoleAPIHandler) HandleQueueList(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// 带分页参数时返回分页结果，否则保持返回完整数组
+	q := r.URL.Query()
+	if q.Has("page") || q.Has("pageSize") || q.Has("cursor") {
+		result, err := h.queueService.ListPage(getPaginationParams(r), q.Get("status"), q.Get("group"))
+		if err != nil {
+			h.sendListError(w, r, err)
+			return
+		}
+		h.sendSuccess(w, r, result)
+		return
+	}
+
 	items, err := h.queueService.GetQueue()
 	if err != nil {
 		h.sendError(w, r, http.StatusInternalServerError, err.Error())
@@ -758,7 +802,11 @@ func (h *ConsoleAPIHandler) HandleSettingsGet(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	h.sendSuccess(w, r, settings)
+	// pendingRestart: 已修改但需重启才能生效的配置项（如 port、cert_file）
+	h.sendSuccess(w, r, struct {
+		*database.Settings
+		PendingRestart []string `json:"pendingRestart"`
+	}{settings, config.PendingRestart()})
 }
 
 // HandleSettingsUpdate 处理 PUT /api/settings - 更新设置
@@ -786,6 +834,9 @@ func (h *ConsoleAPIHandler) HandleSettingsUpdate(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// 重新合并配置层，让依赖这些设置的组件实时生效
+	config.NotifyDatabaseChanged()
+
 	message := "settings updated"
 	if h.radarService != nil && oldSettings != nil && oldSettings.RadarEnabled != settings.RadarEnabled {
 		if settings.RadarEnabled {
@@ -817,6 +868,77 @@ func (h *ConsoleAPIHandler) HandleSettingsAPI(w http.ResponseWriter, r *http.Req
 	}
 }
 
+// ============================================================================
+// 已保存视图 API 处理器
+// ============================================================================
+
+// HandleViewsList 处理 GET /api/views - 列出已保存视图
+func (h *ConsoleAPIHandler) HandleViewsList(w http.ResponseWriter, r *http.Request) {
+	views, err := h.viewService.List()
+	if err != nil {
+		h.sendError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.sendSuccess(w, r, views)
+}
+
+// HandleViewsSave 处理 POST /api/views、PUT /api/views/:name - 创建或更新视图
+func (h *ConsoleAPIHandler) HandleViewsSave(w http.ResponseWriter, r *http.Request, name string) {
+	var view database.SavedView
+	if err := h.parseJSON(r, &view); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if name != "" {
+		view.Name = name
+	}
+
+	if err := h.viewService.Save(&view); err != nil {
+		h.sendError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendSuccess(w, r, view)
+}
+
+// HandleViewsAPI 路由已保存视图 API 请求
+func (h *ConsoleAPIHandler) HandleViewsAPI(w http.ResponseWriter, r *http.Request) {
+	if h.HandleCORS(w, r) {
+		return
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/api")
+	name = strings.Trim(strings.TrimPrefix(name, "/views"), "/")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+
+	switch {
+	case r.Method == "GET" && name == "":
+		h.HandleViewsList(w, r)
+	case r.Method == "GET":
+		view, err := h.viewService.Get(name)
+		if err != nil {
+			h.sendError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if view == nil {
+			h.sendError(w, r, http.StatusNotFound, "view not found")
+			return
+		}
+		h.sendSuccess(w, r, view)
+	case r.Method == "POST" && name == "", r.Method == "PUT" && name != "":
+		h.HandleViewsSave(w, r, name)
+	case r.Method == "DELETE" && name != "":
+		if err := h.viewService.Delete(name); err != nil {
+			h.sendError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		h.sendSuccessMessage(w, r, "view deleted")
+	default:
+		h.sendError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
 // ============================================================================
 // 统计 API 处理器
 // Requirements: 7.1, 7.2 - 统计和图表数据端点
@@ -894,13 +1016,68 @@ func (h *ConsoleAPIHandler) HandleStatsAPI(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if path == "/api/stats/chart" {
+	// /api/v1/stats/* 与 /api/stats/* 相同
+	path = "/api" + strings.TrimPrefix(strings.TrimPrefix(path, "/api/v1"), "/api")
+
+	switch {
+	case path == "/api/stats/chart":
 		h.HandleStatsChart(w, r)
-	} else {
+	case path == "/api/stats/authors":
+		h.HandleStatsAuthors(w, r)
+	case strings.HasPrefix(path, "/api/stats/authors/"):
+		h.HandleStatsAuthorDetail(w, r, strings.TrimPrefix(path, "/api/stats/authors/"))
+	case path == "/api/stats/activity":
+		h.HandleStatsActivity(w, r)
+	default:
 		h.HandleStatsGet(w, r)
 	}
 }
 
+// HandleStatsAuthors 处理 GET /api/stats/authors - 按作者聚合的统计列表
+func (h *ConsoleAPIHandler) HandleStatsAuthors(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	offset, _ := strconv.Atoi(q.Get("offset"))
+
+	page, err := h.statsService.GetAuthorAnalytics(q.Get("sort"), limit, offset)
+	if err != nil {
+		h.sendError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.sendSuccess(w, r, page)
+}
+
+// HandleStatsAuthorDetail 处理 GET /api/stats/authors/{id} - 单个作者的统计与最佳视频
+func (h *ConsoleAPIHandler) HandleStatsAuthorDetail(w http.ResponseWriter, r *http.Request, authorID string) {
+	if authorID == "" {
+		h.sendError(w, r, http.StatusBadRequest, "author id is required")
+		return
+	}
+	detail, err := h.statsService.GetAuthorAnalyticsDetail(authorID)
+	if err != nil {
+		h.sendError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if detail == nil {
+		h.sendError(w, r, http.StatusNotFound, "author not found")
+		return
+	}
+	h.sendSuccess(w, r, detail)
+}
+
+// HandleStatsActivity 处理 GET /api/stats/activity - 浏览时间按星期和小时分布的热力图
+func (h *ConsoleAPIHandler) HandleStatsActivity(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	days, _ := strconv.Atoi(q.Get("days"))
+
+	activity, err := h.statsService.GetActivity(q.Get("authorId"), days)
+	if err != nil {
+		h.sendError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.sendSuccess(w, r, activity)
+}
+
 // ============================================================================
 // 导出 API 处理器
 // Requirements: 4.1, 4.2 - 导出浏览和下载记录
@@ -913,9 +1090,9 @@ func (h *ConsoleAPIHandler) HandleExportBrowse(w http.ResponseWriter, r *http.Re
 	}
 
 	// 获取格式 (默认: json)
-	format := services.ExportFormatJSON
-	if f := r.URL.Query().Get("format"); f == "csv" {
-		format = services.ExportFormatCSV
+	format, ok := services.ParseExportFormat(r.URL.Query().Get("format"))
+	if !ok {
+		format = services.ExportFormatJSON
 	}
 
 	// 获取可选 ID 用于选择性导出
@@ -924,18 +1101,28 @@ func (h *ConsoleAPIHandler) HandleExportBrowse(w http.ResponseWriter, r *http.Re
 		ids = strings.Split(idsParam, ",")
 	}
 
-	result, err := h.exportService.ExportBrowseHistory(format, ids)
-	if err != nil {
-		h.sendError(w, r, http.StatusInternalServerError, err.Error())
-		return
+	// 未指定 ID 时支持按查询语法或已保存视图过滤
+	var params *database.FilterParams
+	if len(ids) == 0 && (r.URL.Query().Get("query") != "" || r.URL.Query().Get("view") != "") {
+		params = &database.FilterParams{Query: r.URL.Query().Get("query")}
+		if err := h.viewService.ResolveFilter(params, r.URL.Query().Get("view"), database.ViewTargetBrowse, true); err != nil {
+			h.sendError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
-	// 设置文件下载头
-	w.Header().Set("Content-Type", result.ContentType)
-	w.Header().Set("Content-Disposition", "attachment; filename=\""+result.Filename+"\"")
+	// 边查询边写出，开始写出之前出错时仍返回 JSON 错误
 	h.setCORSHeaders(w, r)
-	w.WriteHeader(http.StatusOK)
-	w.Write(result.Data)
+	out := response.NewAttachmentWriter(w, format.ContentType(), services.GenerateTimestampFilename("browse_history", format))
+	if _, err := h.exportService.StreamBrowseHistory(out, format, ids, params); err != nil {
+		if !out.Started() {
+			h.sendError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.LogWarn("[导出] browse_history 导出中断: %v", err)
+		return
+	}
+	out.Start()
 }
 
 // HandleExportDownloads 处理 GET /api/export/downloads - 导出下载记录
@@ -944,10 +1131,10 @@ func (h *ConsoleAPIHandler) HandleExportDownloads(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Get format (default: json)
-	format := services.ExportFormatJSON
-	if f := r.URL.Query().Get("format"); f == "csv" {
-		format = services.ExportFormatCSV
+	// 获取格式 (默认: json)
+	format, ok := services.ParseExportFormat(r.URL.Query().Get("format"))
+	if !ok {
+		format = services.ExportFormatJSON
 	}
 
 	// Get optional IDs for selective export
@@ -956,18 +1143,28 @@ func (h *ConsoleAPIHandler) HandleExportDownloads(w http.ResponseWriter, r *http
 		ids = strings.Split(idsParam, ",")
 	}
 
-	result, err := h.exportService.ExportDownloadRecords(format, ids)
-	if err != nil {
-		h.sendError(w, r, http.StatusInternalServerError, err.Error())
-		return
+	// 未指定 ID 时支持按查询语法或已保存视图过滤
+	var params *database.FilterParams
+	if len(ids) == 0 && (r.URL.Query().Get("query") != "" || r.URL.Query().Get("view") != "") {
+		params = &database.FilterParams{Query: r.URL.Query().Get("query")}
+		if err := h.viewService.ResolveFilter(params, r.URL.Query().Get("view"), database.ViewTargetDownloads, true); err != nil {
+			h.sendError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
-	// Set headers for file download
-	w.Header().Set("Content-Type", result.ContentType)
-	w.Header().Set("Content-Disposition", "attachment; filename=\""+result.Filename+"\"")
+	// 边查询边写出，开始写出之前出错时仍返回 JSON 错误
 	h.setCORSHeaders(w, r)
-	w.WriteHeader(http.StatusOK)
-	w.Write(result.Data)
+	out := response.NewAttachmentWriter(w, format.ContentType(), services.GenerateTimestampFilename("download_records", format))
+	if _, err := h.exportService.StreamDownloadRecords(out, format, ids, params); err != nil {
+		if !out.Started() {
+			h.sendError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.LogWarn("[导出] download_records 导出中断: %v", err)
+		return
+	}
+	out.Start()
 }
 
 // HandleExportAPI 路由导出 API 请求
@@ -1031,7 +1228,13 @@ func (h *ConsoleAPIHandler) HandleSearch(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	result, err := h.searchService.Search(query, limit)
+	// 可选的标签过滤，多个标签用逗号分隔（需同时带有）
+	var tags []string
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		tags = strings.Split(tag, ",")
+	}
+
+	result, err := h.searchService.SearchTagged(query, tags, limit)
 	if err != nil {
 		h.sendError(w, r, http.StatusInternalServerError, err.Error())
 		return
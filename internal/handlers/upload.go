@@ -32,6 +32,7 @@ import (
 type UploadHandler struct {
 	downloadService *services.DownloadRecordService
 	gopeedService   *services.GopeedService // Injected Gopeed Service
+	semMu           sync.RWMutex            // 保护 chunkSem/mergeSem，支持热更新并发上限
 	chunkSem        chan struct{}
 	mergeSem        chan struct{}
 	wsHub           *websocket.Hub
@@ -40,21 +41,49 @@ type UploadHandler struct {
 
 // NewUploadHandler 创建上传处理器
 func NewUploadHandler(cfg *config.Config, wsHub *websocket.Hub, gopeedService *services.GopeedService) *UploadHandler {
-	ch := cfg.UploadChunkConcurrency
-	if ch <= 0 {
-		ch = 4
-	}
-	mg := cfg.UploadMergeConcurrency
-	if mg <= 0 {
-		mg = 1
-	}
-	return &UploadHandler{
+	h := &UploadHandler{
 		downloadService: services.NewDownloadRecordService(),
 		gopeedService:   gopeedService,
-		chunkSem:        make(chan struct{}, ch),
-		mergeSem:        make(chan struct{}, mg),
 		wsHub:           wsHub,
 	}
+	h.SetConcurrency(cfg.UploadChunkConcurrency, cfg.UploadMergeConcurrency)
+	return h
+}
+
+// SetConcurrency 调整分片上传/合并的并发上限
+// 正在进行的请求仍归还到旧的信号量，新请求使用新的上限
+func (h *UploadHandler) SetConcurrency(chunk, merge int) {
+	if chunk <= 0 {
+		chunk = 4
+	}
+	if merge <= 0 {
+		merge = 1
+	}
+
+	h.semMu.Lock()
+	defer h.semMu.Unlock()
+	if h.chunkSem == nil || cap(h.chunkSem) != chunk {
+		h.chunkSem = make(chan struct{}, chunk)
+	}
+	if h.mergeSem == nil || cap(h.mergeSem) != merge {
+		h.mergeSem = make(chan struct{}, merge)
+	}
+}
+
+// acquire 占用信号量，返回释放函数
+func (h *UploadHandler) acquire(merge bool) func() {
+	h.semMu.RLock()
+	sem := h.chunkSem
+	if merge {
+		sem = h.mergeSem
+	}
+	h.semMu.RUnlock()
+
+	if sem == nil {
+		return func() {}
+	}
+	sem <- struct{}{}
+	return func() { <-sem }
 }
 
 // getConfig 获取当前配置（动态获取最新配置）
@@ -248,10 +277,7 @@ func (h *UploadHandler) HandleUploadChunk(Conn *SunnyNet.HttpConn) bool {
 	}
 
 	// 并发限流（分片）
-	if h.chunkSem != nil {
-		h.chunkSem <- struct{}{}
-		defer func() { <-h.chunkSem }()
-	}
+	defer h.acquire(false)()
 
 	if h.getConfig() != nil && h.getConfig().SecretToken != "" {
 		if Conn.Request.Header.Get("X-Local-Auth") != h.getConfig().SecretToken {
@@ -451,10 +477,7 @@ func (h *UploadHandler) HandleCompleteUpload(Conn *SunnyNet.HttpConn) bool {
 	}
 
 	// 并发限流（合并）
-	if h.mergeSem != nil {
-		h.mergeSem <- struct{}{}
-		defer func() { <-h.mergeSem }()
-	}
+	defer h.acquire(true)()
 
 	if h.getConfig() != nil && h.getConfig().SecretToken != "" {
 		if Conn.Request.Header.Get("X-Local-Auth") != h.getConfig().SecretToken {
@@ -216,6 +216,14 @@ func (h *APIHandler) processVideoData(data map[string]interface{}) {
 	// 保存浏览记录到数据库
 	h.saveBrowseRecord(videoID, title, author, authorID, duration, size, coverUrl, url, decryptKey, resolution, fileFormat, likeCount, commentCount, favCount, forwardCount, pageUrl)
 
+	// 记录互动数据快照，nonce_id 供之后通过 feed_profile 重新拉取，createtime 用于统计作者发布频率
+	nonceID, _ := data["nonce_id"].(string)
+	var publishedAt int64
+	if ct, ok := data["createtime"].(float64); ok {
+		publishedAt = int64(ct)
+	}
+	h.saveStatsSnapshot(videoID, nonceID, publishedAt, likeCount, commentCount, favCount, forwardCount)
+
 	color.Yellow("\n")
 
 	// 打印视频详细信息
@@ -308,12 +316,21 @@ func (h *APIHandler) saveBrowseRecord(videoID, title, author, authorID string, d
 		return
 	}
 
-	// 如果没有视频ID，生成一个
+	repo := database.NewBrowseHistoryRepository()
+
+	// 没有视频ID时，优先合并到标题、作者和时长相同的记录，否则生成一个
 	if videoID == "" {
-		videoID = fmt.Sprintf("browse_%d", time.Now().UnixNano())
+		existingID, err := repo.FindUnidentified(title, author, duration)
+		if err != nil {
+			utils.Warn("检查浏览记录失败: %v", err)
+			return
+		}
+		videoID = existingID
+		if videoID == "" {
+			videoID = fmt.Sprintf("browse_%d", time.Now().UnixNano())
+		}
 	}
 
-	// 创建浏览记录
 	record := &database.BrowseRecord{
 		ID:           videoID,
 		Title:        title,
@@ -334,41 +351,39 @@ func (h *APIHandler) saveBrowseRecord(videoID, title, author, authorID string, d
 		PageURL:      pageUrl,
 	}
 
-	// 保存到数据库
-	repo := database.NewBrowseHistoryRepository()
-
-	// 先检查是否已存在该记录
-	existing, err := repo.GetByID(videoID)
+	// 同一视频只保留一行：已存在时累加观看次数并合并字段，不会整条覆盖
+	viewCount, err := repo.RecordView(record)
 	if err != nil {
-		utils.Warn("检查浏览记录失败: %v", err)
+		utils.Warn("保存浏览记录失败: %v", err)
 		return
 	}
-
-	if existing != nil {
-		// 更新现有记录
-		record.CreatedAt = existing.CreatedAt
-		// 如果现有记录没有解密密钥但新数据有，则更新
-		if existing.DecryptKey == "" && decryptKey != "" {
-			record.DecryptKey = decryptKey
-		} else if existing.DecryptKey != "" {
-			// 保留现有的解密密钥
-			record.DecryptKey = existing.DecryptKey
-		}
-		err = repo.Update(record)
-		if err != nil {
-			utils.Warn("更新浏览记录失败: %v", err)
-		} else {
-			utils.Info("✓ 浏览记录已更新: %s", title)
-		}
+	if viewCount > 1 {
+		utils.Info("✓ 浏览记录已更新（第 %d 次看到）: %s", viewCount, title)
 	} else {
-		// 创建新记录
-		err = repo.Create(record)
-		if err != nil {
-			utils.Warn("保存浏览记录失败: %v", err)
-		} else {
-			utils.Info("✓ 浏览记录已保存: %s", title)
+		utils.Info("✓ 浏览记录已保存: %s", title)
+	}
+}
+
+// saveStatsSnapshot 保存页面采集到的互动数据快照，同时补充视频的 nonce 和发布时间
+func (h *APIHandler) saveStatsSnapshot(videoID, nonceID string, publishedAt, likeCount, commentCount, favCount, forwardCount int64) {
+	if videoID == "" || database.GetDB() == nil {
+		return
+	}
+	if nonceID != "" || publishedAt > 0 {
+		if err := database.NewVideoRepository().Upsert(&database.Video{ID: videoID, NonceID: nonceID, PublishedAt: publishedAt}); err != nil {
+			utils.Warn("保存视频元数据失败: %v", err)
 		}
 	}
+	if err := database.NewVideoStatsRepository().Add(&database.StatsSnapshot{
+		VideoID:      videoID,
+		Source:       database.StatsSourcePage,
+		LikeCount:    likeCount,
+		CommentCount: commentCount,
+		FavCount:     favCount,
+		ForwardCount: forwardCount,
+	}); err != nil {
+		utils.Warn("保存互动数据快照失败: %v", err)
+	}
 }
 
 // HandleTip 处理前端提示请求
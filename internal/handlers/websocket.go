@@ -22,6 +22,7 @@ const (
 	MessageTypeDownloadProgress = "download_progress"
 	MessageTypeQueueChange      = "queue_change"
 	MessageTypeStatsUpdate      = "stats_update"
+	MessageTypeRadarLive        = "radar_live"
 	MessageTypePing             = "ping"
 	MessageTypePong             = "pong"
 	WSMessageTypeCommand        = "cmd"
@@ -55,6 +56,13 @@ type QueueChangeMessage struct {
 	Queue  []database.QueueItem `json:"queue,omitempty"`
 }
 
+// RadarLiveMessage 表示雷达监控账号开播或下播，Action 为 start 或 end
+type RadarLiveMessage struct {
+	Type    string                     `json:"type"`
+	Action  string                     `json:"action"`
+	Session *database.RadarLiveSession `json:"session"`
+}
+
 // StatsUpdateMessage 表示统计信息更新
 type StatsUpdateMessage struct {
 	Type  string               `json:"type"`
@@ -303,6 +311,18 @@ func (h *WebSocketHub) BroadcastQueueReorder(queue []database.QueueItem) {
 	}
 }
 
+// BroadcastRadarLive 广播雷达监控账号的开播或下播
+func (h *WebSocketHub) BroadcastRadarLive(event services.RadarLiveEvent) {
+	msg := RadarLiveMessage{
+		Type:    MessageTypeRadarLive,
+		Action:  event.Action,
+		Session: event.Session,
+	}
+	if err := h.BroadcastMessage(msg); err != nil {
+		utils.Warn("[WebSocket] Failed to broadcast radar live: %v", err)
+	}
+}
+
 // BroadcastStatsUpdate 向所有客户端广播统计更新
 func (h *WebSocketHub) BroadcastStatsUpdate() {
 	stats, err := h.statsService.GetStatistics()
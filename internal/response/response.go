@@ -2,6 +2,7 @@ package response
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 )
 
@@ -90,3 +91,39 @@ func SuccessJSON(data interface{}) []byte {
 func ErrorJSON(code int, message string) []byte {
 	return ToJSON(code, message, nil)
 }
+
+// AttachmentWriter 在第一次写入时才设置文件下载响应头，
+// 流式导出在写出内容之前出错时仍可以返回 JSON 错误
+type AttachmentWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+// NewAttachmentWriter 创建一个文件下载响应写入器
+func NewAttachmentWriter(w http.ResponseWriter, contentType, filename string) *AttachmentWriter {
+	return &AttachmentWriter{ResponseWriter: w, contentType: contentType, filename: filename}
+}
+
+// Started 返回是否已经开始写出文件内容
+func (w *AttachmentWriter) Started() bool {
+	return w.started
+}
+
+// Start 写出下载响应头，重复调用无效
+func (w *AttachmentWriter) Start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.Header().Set("Content-Type", w.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", w.filename))
+	w.WriteHeader(http.StatusOK)
+}
+
+// Write 写出文件内容，第一次写入前先写出响应头
+func (w *AttachmentWriter) Write(p []byte) (int, error) {
+	w.Start()
+	return w.ResponseWriter.Write(p)
+}
@@ -6,9 +6,11 @@ import (
 	"wx_channel/internal/api"
 	"wx_channel/internal/config"
 	"wx_channel/internal/handlers"
+	"wx_channel/internal/services"
 	"wx_channel/internal/websocket"
 
 	"strings"
+	"sync"
 
 	"github.com/qtgolang/SunnyNet/SunnyNet"
 )
@@ -25,6 +27,11 @@ type APIRouter struct {
 	certificateService *api.CertificateService
 	versionService     *api.VersionAPI
 	radarAPI           *api.RadarServiceAPI
+	configAPI          *api.ConfigAPI
+	tagAPI             *api.TagAPI
+	videoAPI           *api.VideoAPI
+	importAPI          *api.ImportAPI
+	originsMu          sync.RWMutex
 	allowedOrigins     []string
 	secretToken        string
 }
@@ -51,8 +58,8 @@ func (r *APIRouter) Handle(Conn *SunnyNet.HttpConn) bool {
 	return true
 }
 
-// NewAPIRouter 创建 API 路由器
-func NewAPIRouter(cfg *config.Config, hub *websocket.Hub, sunny *SunnyNet.Sunny) *APIRouter {
+// NewAPIRouter 创建 API 路由器，radarService 为运行中的雷达服务（可为 nil）
+func NewAPIRouter(cfg *config.Config, hub *websocket.Hub, sunny *SunnyNet.Sunny, radarService *services.RadarService) *APIRouter {
 	mux := http.NewServeMux()
 
 	router := &APIRouter{
@@ -65,7 +72,11 @@ func NewAPIRouter(cfg *config.Config, hub *websocket.Hub, sunny *SunnyNet.Sunny)
 		proxyService:       api.NewProxyService(sunny, cfg.Port),
 		certificateService: api.NewCertificateService(sunny),
 		versionService:     api.NewVersionAPI(),
-		radarAPI:           api.NewRadarServiceAPI(),
+		radarAPI:           api.NewRadarServiceAPI(hub, radarService),
+		configAPI:          api.NewConfigAPI(),
+		tagAPI:             api.NewTagAPI(),
+		videoAPI:           api.NewVideoAPI(),
+		importAPI:          api.NewImportAPI(),
 		allowedOrigins:     cfg.AllowedOrigins,
 		secretToken:        cfg.SecretToken,
 	}
@@ -87,6 +98,13 @@ func (r *APIRouter) registerRoutes() {
 	r.proxyService.RegisterRoutes(r.mux)
 	r.certificateService.RegisterRoutes(r.mux)
 	r.versionService.RegisterRoutes(r.mux)
+	r.configAPI.RegisterRoutes(r.mux)
+
+	// 标签与合集
+	r.tagAPI.RegisterRoutes(r.mux)
+
+	// 视频与作者详情
+	r.videoAPI.RegisterRoutes(r.mux)
 
 	// 控制台 API - 浏览历史
 	r.mux.HandleFunc("/api/browse", r.consoleHandler.HandleBrowseAPI)
@@ -104,6 +122,10 @@ func (r *APIRouter) registerRoutes() {
 	// 设置管理
 	r.mux.HandleFunc("/api/settings", r.consoleHandler.HandleSettingsAPI)
 
+	// 已保存视图（存储在设置中，可通过 view 参数用于浏览、下载记录和导出）
+	r.mux.HandleFunc("/api/views", r.consoleHandler.HandleViewsAPI)
+	r.mux.HandleFunc("/api/views/", r.consoleHandler.HandleViewsAPI)
+
 	// 健康检查
 	r.mux.HandleFunc("/api/health", r.consoleHandler.HandleHealth)
 
@@ -123,6 +145,9 @@ func (r *APIRouter) registerRoutes() {
 	r.mux.HandleFunc("/api/export/browse", r.exportService.HandleExportBrowseHistory)
 	r.mux.HandleFunc("/api/export/downloads", r.exportService.HandleExportDownloadRecords)
 
+	// 控制台 API - 导入功能（/api 与 /api/v1）
+	r.importAPI.RegisterRoutes(r.mux)
+
 	// 控制台 API - 视频相关
 	r.mux.HandleFunc("/api/video/stream", r.consoleHandler.HandleVideoStream)
 	r.mux.HandleFunc("/api/video/play", r.consoleHandler.HandleVideoPlay)
@@ -138,6 +163,8 @@ func (r *APIRouter) registerRoutes() {
 	r.mux.HandleFunc("/api/v1/queue", r.consoleHandler.HandleQueueAPI)
 	r.mux.HandleFunc("/api/v1/queue/", r.consoleHandler.HandleQueueAPI)
 	r.mux.HandleFunc("/api/v1/settings", r.consoleHandler.HandleSettingsAPI)
+	r.mux.HandleFunc("/api/v1/views", r.consoleHandler.HandleViewsAPI)
+	r.mux.HandleFunc("/api/v1/views/", r.consoleHandler.HandleViewsAPI)
 	r.mux.HandleFunc("/api/v1/stats", r.consoleHandler.HandleStatsAPI)
 	r.mux.HandleFunc("/api/v1/stats/", r.consoleHandler.HandleStatsAPI)
 
@@ -145,14 +172,25 @@ func (r *APIRouter) registerRoutes() {
 	r.radarAPI.RegisterRoutes(r.mux)
 }
 
+// SetAllowedOrigins 热更新允许跨域的 Origin 列表
+func (r *APIRouter) SetAllowedOrigins(origins []string) {
+	r.originsMu.Lock()
+	r.allowedOrigins = origins
+	r.originsMu.Unlock()
+}
+
 // Handler 返回带中间件的 HTTP Handler
 func (r *APIRouter) Handler() http.Handler {
+	r.originsMu.RLock()
+	origins := r.allowedOrigins
+	r.originsMu.RUnlock()
+
 	// 应用中间件链
 	return Chain(
 		r.mux,
 		RecoveryMiddleware,
 		LoggerMiddleware,
-		CORSMiddleware(r.allowedOrigins),
+		CORSMiddleware(origins),
 		AuthMiddleware(r.secretToken),
 	)
 }
@@ -23,7 +23,7 @@ func newTestRouter() *APIRouter {
 	sunny := SunnyNet.NewSunny()
 
 	// Create router with nil dependencies where possible or mocked ones
-	r := NewAPIRouter(cfg, hub, sunny)
+	r := NewAPIRouter(cfg, hub, sunny, nil)
 	return r
 }
 
@@ -46,6 +46,11 @@ func (s *BrowseHistoryService) List(params *database.PaginationParams) (*databas
 	return s.repo.List(params)
 }
 
+// Filter 获取符合过滤条件的浏览记录（带分页）
+func (s *BrowseHistoryService) Filter(params *database.FilterParams) (*database.PagedResult[database.BrowseRecord], error) {
+	return s.repo.ListFiltered(params)
+}
+
 // GetByID 按 ID 获取单条浏览记录
 func (s *BrowseHistoryService) GetByID(id string) (*database.BrowseRecord, error) {
 	return s.repo.GetByID(id)
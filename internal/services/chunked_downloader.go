@@ -439,8 +439,11 @@ func (d *ChunkedDownloader) prepareDownloadPath(item *database.QueueItem) (strin
 		return "", err
 	}
 
-	// 创建作者文件夹
+	// 创建作者文件夹，雷达分组指定了子目录时使用子目录
 	authorFolder := utils.CleanFolderName(item.Author)
+	if item.SubDir != "" {
+		authorFolder = filepath.FromSlash(item.SubDir)
+	}
 	downloadDir := filepath.Join(baseDir, d.downloadDir, authorFolder)
 
 	if err := utils.EnsureDir(downloadDir); err != nil {
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"wx_channel/internal/database"
+)
+
+// 合集导出方式
+const (
+	MaterializeLinks = "links" // 硬链接文件夹
+	MaterializeM3U   = "m3u"   // M3U 播放列表
+)
+
+// MaterializeResult 表示合集导出到下载目录的结果
+type MaterializeResult struct {
+	Path    string   `json:"path"`    // 生成的文件夹或 .m3u 文件
+	Entries int      `json:"entries"` // 成功写入的视频数
+	Symlink int      `json:"symlink"` // 无法硬链接（如跨分区）而改用符号链接的数量
+	Missing []string `json:"missing"` // 尚未下载或文件已不存在的视频 ID
+}
+
+// linkNamePattern 匹配生成的链接文件名（序号前缀），重新生成时只清理这些文件
+var linkNamePattern = regexp.MustCompile(`^\d{3,}_`)
+
+// CollectionService 处理合集业务逻辑
+type CollectionService struct {
+	repo *database.CollectionRepository
+}
+
+// NewCollectionService 创建一个新的 CollectionService
+func NewCollectionService() *CollectionService {
+	return &CollectionService{
+		repo: database.NewCollectionRepository(),
+	}
+}
+
+// List 获取所有合集
+func (s *CollectionService) List() ([]database.Collection, error) {
+	return s.repo.List()
+}
+
+// Get 获取合集及其条目
+func (s *CollectionService) Get(id string) (*database.Collection, []database.CollectionItem, error) {
+	collection, err := s.repo.GetByID(id)
+	if err != nil || collection == nil {
+		return nil, nil, err
+	}
+	items, err := s.repo.Items(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return collection, items, nil
+}
+
+// Create 创建合集
+func (s *CollectionService) Create(c *database.Collection) error {
+	return s.repo.Create(c)
+}
+
+// Update 更新合集
+func (s *CollectionService) Update(c *database.Collection) error {
+	return s.repo.Update(c)
+}
+
+// Delete 删除合集
+func (s *CollectionService) Delete(id string) error {
+	return s.repo.Delete(id)
+}
+
+// AddItems 把记录加入合集
+func (s *CollectionService) AddItems(id, recordType string, ids []string) (int64, error) {
+	videoIDs, err := resolveVideoIDs(recordType, ids)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.AddItems(id, videoIDs)
+}
+
+// RemoveItems 从合集移除记录
+func (s *CollectionService) RemoveItems(id, recordType string, ids []string) (int64, error) {
+	videoIDs, err := resolveVideoIDs(recordType, ids)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.RemoveItems(id, videoIDs)
+}
+
+// Materialize 把合集导出到下载目录下的 collections 子目录
+// links 模式生成以序号开头的硬链接文件夹，m3u 模式生成使用相对路径的播放列表
+func (s *CollectionService) Materialize(id, mode string) (*MaterializeResult, error) {
+	collection, items, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("collection not found: %s", id)
+	}
+
+	baseDir := filepath.Join(resolveDownloadsDir(), "collections")
+	name := cleanFolderName(collection.Name)
+	if name == "" {
+		name = collection.ID
+	}
+
+	switch mode {
+	case "", MaterializeLinks:
+		return materializeLinks(filepath.Join(baseDir, name), items)
+	case MaterializeM3U:
+		return materializeM3U(filepath.Join(baseDir, name+".m3u"), items)
+	default:
+		return nil, fmt.Errorf("unsupported materialize mode: %s", mode)
+	}
+}
+
+func materializeLinks(dir string, items []database.CollectionItem) (*MaterializeResult, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create collection folder: %w", err)
+	}
+
+	// 清理上次生成的链接（删除链接不影响原文件）
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection folder: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() && linkNamePattern.MatchString(e.Name()) {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+
+	result := &MaterializeResult{Path: dir, Missing: []string{}}
+	for i, item := range items {
+		if !fileExists(item.FilePath) {
+			result.Missing = append(result.Missing, item.VideoID)
+			continue
+		}
+		dst := filepath.Join(dir, fmt.Sprintf("%03d_%s", i+1, filepath.Base(item.FilePath)))
+		if err := os.Link(item.FilePath, dst); err != nil {
+			if err := os.Symlink(item.FilePath, dst); err != nil {
+				return nil, fmt.Errorf("failed to link %s: %w", item.FilePath, err)
+			}
+			result.Symlink++
+		}
+		result.Entries++
+	}
+	return result, nil
+}
+
+func materializeM3U(path string, items []database.CollectionItem) (*MaterializeResult, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create collection folder: %w", err)
+	}
+
+	result := &MaterializeResult{Path: path, Missing: []string{}}
+	var buf bytes.Buffer
+	buf.WriteString("#EXTM3U\n")
+	for _, item := range items {
+		if !fileExists(item.FilePath) {
+			result.Missing = append(result.Missing, item.VideoID)
+			continue
+		}
+		target := item.FilePath
+		if rel, err := filepath.Rel(filepath.Dir(path), item.FilePath); err == nil {
+			target = filepath.ToSlash(rel)
+		}
+		title := strings.TrimSpace(item.Title)
+		if item.Author != "" {
+			title = item.Author + " - " + title
+		}
+		fmt.Fprintf(&buf, "#EXTINF:%d,%s\n%s\n", item.Duration/1000, strings.ReplaceAll(title, "\n", " "), target)
+		result.Entries++
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write playlist: %w", err)
+	}
+	return result, nil
+}
+
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -95,17 +95,17 @@ func (s *DownloadRecordService) Clear(deleteFiles bool) error {
 // DeleteBefore 删除指定日期前的所有记录（可选删除文件）
 func (s *DownloadRecordService) DeleteBefore(date time.Time, deleteFiles bool) (int64, error) {
 	if deleteFiles {
-		// 分页获取日期前的所有记录以删除文件
-		const batchSize = 500
-		page := 1
+		// 按游标分页获取日期前的所有记录以删除文件
+		params := &database.FilterParams{
+			PaginationParams: database.PaginationParams{
+				PageSize:  100,
+				SortDesc:  true,
+				Keyset:    true,
+				SkipTotal: true,
+			},
+			EndDate: &date,
+		}
 		for {
-			params := &database.FilterParams{
-				PaginationParams: database.PaginationParams{
-					Page:     page,
-					PageSize: batchSize,
-				},
-				EndDate: &date,
-			}
 			result, err := s.repo.List(params)
 			if err != nil {
 				return 0, err
@@ -115,11 +115,10 @@ func (s *DownloadRecordService) DeleteBefore(date time.Time, deleteFiles bool) (
 					_ = os.Remove(record.FilePath)
 				}
 			}
-			// 如果这一页数据不足一批，说明没有更多了
-			if len(result.Items) < batchSize {
+			if !result.HasMore {
 				break
 			}
-			page++
+			params.Cursor = result.NextCursor
 		}
 	}
 	return s.repo.DeleteBefore(date)
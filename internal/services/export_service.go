@@ -2,9 +2,10 @@ package services
 
 import (
 	"bytes"
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"wx_channel/internal/database"
@@ -14,10 +15,36 @@ import (
 type ExportFormat string
 
 const (
-	ExportFormatJSON ExportFormat = "json"
-	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatJSON   ExportFormat = "json"
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson" // 每行一条 JSON，适合超大历史记录
+	ExportFormatXLSX   ExportFormat = "xlsx"   // 数值和时间列带类型的 Excel 工作簿
+	ExportFormatSQLite ExportFormat = "sqlite" // 独立的 SQLite 数据库文件
+	ExportFormatHTML   ExportFormat = "html"   // 带封面和统计的单文件报告
 )
 
+// exportContentTypes 各格式对应的 Content-Type
+var exportContentTypes = map[ExportFormat]string{
+	ExportFormatJSON:   "application/json",
+	ExportFormatCSV:    "text/csv",
+	ExportFormatNDJSON: "application/x-ndjson",
+	ExportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	ExportFormatSQLite: "application/vnd.sqlite3",
+	ExportFormatHTML:   "text/html; charset=utf-8",
+}
+
+// ParseExportFormat 解析导出格式（不区分大小写）
+func ParseExportFormat(value string) (ExportFormat, bool) {
+	format := ExportFormat(strings.ToLower(strings.TrimSpace(value)))
+	_, ok := exportContentTypes[format]
+	return format, ok
+}
+
+// ContentType 返回导出格式对应的 Content-Type
+func (f ExportFormat) ContentType() string {
+	return exportContentTypes[f]
+}
+
 // ExportResult 包含导出的数据和元数据
 type ExportResult struct {
 	Data        []byte    `json:"data"`
@@ -66,33 +93,49 @@ func (s *ExportService) ExportBrowseHistory(format ExportFormat, ids []string) (
 		return nil, fmt.Errorf("failed to get browse records: %w", err)
 	}
 
-	var data []byte
-	var contentType string
+	return s.buildBrowseExport(format, records)
+}
 
-	switch format {
-	case ExportFormatJSON:
-		data, err = s.exportBrowseRecordsToJSON(records)
-		contentType = "application/json"
-	case ExportFormatCSV:
-		data, err = s.exportBrowseRecordsToCSV(records)
-		contentType = "text/csv"
-	default:
-		return nil, fmt.Errorf("unsupported export format: %s", format)
+// ExportBrowseHistoryFiltered 导出符合过滤条件（查询语法或已保存视图）的浏览记录
+func (s *ExportService) ExportBrowseHistoryFiltered(format ExportFormat, params *database.FilterParams) (*ExportResult, error) {
+	records, err := s.browseRepo.FindAll(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get browse records: %w", err)
 	}
+	return s.buildBrowseExport(format, records)
+}
 
+func (s *ExportService) buildBrowseExport(format ExportFormat, records []database.BrowseRecord) (*ExportResult, error) {
+	var buf bytes.Buffer
+	_, err := streamRecords(&buf, format, &browseTable, func(fn func(*database.BrowseRecord) error) error {
+		for i := range records {
+			if err := fn(&records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &ExportResult{
-		Data:        data,
+		Data:        buf.Bytes(),
 		Filename:    GenerateTimestampFilename("browse_history", format),
-		ContentType: contentType,
+		ContentType: format.ContentType(),
 		RecordCount: len(records),
 		ExportTime:  time.Now(),
 	}, nil
 }
 
+// StreamBrowseHistory 将浏览记录逐条写入 w，不在内存中构建完整文件；
+// ids 非空时按 ID 导出，否则按 filter 过滤（nil 表示全部），返回导出的记录数
+func (s *ExportService) StreamBrowseHistory(w io.Writer, format ExportFormat, ids []string, filter *database.FilterParams) (int, error) {
+	return streamRecords(w, format, &browseTable, func(fn func(*database.BrowseRecord) error) error {
+		return s.browseRepo.ForEach(ids, filter, fn)
+	})
+}
+
 // ExportDownloadRecords 导出下载记录
 // Requirements: 4.2 - 以 JSON 或 CSV 格式导出下载记录
 func (s *ExportService) ExportDownloadRecords(format ExportFormat, ids []string) (*ExportResult, error) {
@@ -110,156 +153,47 @@ func (s *ExportService) ExportDownloadRecords(format ExportFormat, ids []string)
 		return nil, fmt.Errorf("failed to get download records: %w", err)
 	}
 
-	var data []byte
-	var contentType string
+	return s.buildDownloadExport(format, records)
+}
 
-	switch format {
-	case ExportFormatJSON:
-		data, err = s.exportDownloadRecordsToJSON(records)
-		contentType = "application/json"
-	case ExportFormatCSV:
-		data, err = s.exportDownloadRecordsToCSV(records)
-		contentType = "text/csv"
-	default:
-		return nil, fmt.Errorf("unsupported export format: %s", format)
+// ExportDownloadRecordsFiltered 导出符合过滤条件（查询语法或已保存视图）的下载记录
+func (s *ExportService) ExportDownloadRecordsFiltered(format ExportFormat, params *database.FilterParams) (*ExportResult, error) {
+	records, err := s.downloadRepo.FindAll(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get download records: %w", err)
 	}
+	return s.buildDownloadExport(format, records)
+}
 
+func (s *ExportService) buildDownloadExport(format ExportFormat, records []database.DownloadRecord) (*ExportResult, error) {
+	var buf bytes.Buffer
+	_, err := streamRecords(&buf, format, &downloadTable, func(fn func(*database.DownloadRecord) error) error {
+		for i := range records {
+			if err := fn(&records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &ExportResult{
-		Data:        data,
+		Data:        buf.Bytes(),
 		Filename:    GenerateTimestampFilename("download_records", format),
-		ContentType: contentType,
+		ContentType: format.ContentType(),
 		RecordCount: len(records),
 		ExportTime:  time.Now(),
 	}, nil
 }
 
-// exportBrowseRecordsToJSON 将浏览记录导出为 JSON 格式
-func (s *ExportService) exportBrowseRecordsToJSON(records []database.BrowseRecord) ([]byte, error) {
-	data, err := json.MarshalIndent(records, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal browse records to JSON: %w", err)
-	}
-	return data, nil
-}
-
-// exportBrowseRecordsToCSV 将浏览记录导出为 CSV 格式
-func (s *ExportService) exportBrowseRecordsToCSV(records []database.BrowseRecord) ([]byte, error) {
-	var buf bytes.Buffer
-	// 写入 UTF-8 BOM 以兼容 Excel
-	buf.Write([]byte{0xEF, 0xBB, 0xBF})
-
-	writer := csv.NewWriter(&buf)
-
-	// 写入表头
-	header := []string{
-		"ID", "Title", "Author", "AuthorID", "Duration", "Size", "Resolution",
-		"CoverURL", "VideoURL", "DecryptKey", "BrowseTime", "LikeCount",
-		"CommentCount", "FavCount", "ForwardCount", "PageURL", "CreatedAt", "UpdatedAt",
-	}
-	if err := writer.Write(header); err != nil {
-		return nil, fmt.Errorf("failed to write CSV header: %w", err)
-	}
-
-	// 写入记录
-	for _, record := range records {
-		row := []string{
-			record.ID,
-			record.Title,
-			record.Author,
-			record.AuthorID,
-			fmt.Sprintf("%d", record.Duration),
-			fmt.Sprintf("%d", record.Size),
-			record.Resolution,
-			record.CoverURL,
-			record.VideoURL,
-			record.DecryptKey,
-			record.BrowseTime.Format(time.RFC3339),
-			fmt.Sprintf("%d", record.LikeCount),
-			fmt.Sprintf("%d", record.CommentCount),
-			fmt.Sprintf("%d", record.FavCount),
-			fmt.Sprintf("%d", record.ForwardCount),
-			record.PageURL,
-			record.CreatedAt.Format(time.RFC3339),
-			record.UpdatedAt.Format(time.RFC3339),
-		}
-		if err := writer.Write(row); err != nil {
-			return nil, fmt.Errorf("failed to write CSV row: %w", err)
-		}
-	}
-
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		return nil, fmt.Errorf("failed to flush CSV writer: %w", err)
-	}
-
-	return buf.Bytes(), nil
-}
-
-// exportDownloadRecordsToJSON 将下载记录导出为 JSON 格式
-func (s *ExportService) exportDownloadRecordsToJSON(records []database.DownloadRecord) ([]byte, error) {
-	data, err := json.MarshalIndent(records, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal download records to JSON: %w", err)
-	}
-	return data, nil
-}
-
-// exportDownloadRecordsToCSV 将下载记录导出为 CSV 格式
-func (s *ExportService) exportDownloadRecordsToCSV(records []database.DownloadRecord) ([]byte, error) {
-	var buf bytes.Buffer
-	// 写入 UTF-8 BOM 以兼容 Excel
-	buf.Write([]byte{0xEF, 0xBB, 0xBF})
-
-	writer := csv.NewWriter(&buf)
-
-	// 写入表头
-	header := []string{
-		"ID", "VideoID", "Title", "Author", "Duration", "FileSize",
-		"FilePath", "Format", "Resolution", "Status", "DownloadTime",
-		"LikeCount", "CommentCount", "ForwardCount", "FavCount",
-		"ErrorMessage", "CreatedAt", "UpdatedAt",
-	}
-	if err := writer.Write(header); err != nil {
-		return nil, fmt.Errorf("failed to write CSV header: %w", err)
-	}
-
-	// 写入记录
-	for _, record := range records {
-		row := []string{
-			record.ID,
-			record.VideoID,
-			record.Title,
-			record.Author,
-			formatDuration(record.Duration),
-			formatFileSize(record.FileSize),
-			record.FilePath,
-			record.Format,
-			record.Resolution,
-			record.Status,
-			record.DownloadTime.Format(time.RFC3339),
-			fmt.Sprintf("%d", record.LikeCount),
-			fmt.Sprintf("%d", record.CommentCount),
-			fmt.Sprintf("%d", record.ForwardCount),
-			fmt.Sprintf("%d", record.FavCount),
-			record.ErrorMessage,
-			record.CreatedAt.Format(time.RFC3339),
-			record.UpdatedAt.Format(time.RFC3339),
-		}
-		if err := writer.Write(row); err != nil {
-			return nil, fmt.Errorf("failed to write CSV row: %w", err)
-		}
-	}
-
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		return nil, fmt.Errorf("failed to flush CSV writer: %w", err)
-	}
-
-	return buf.Bytes(), nil
+// StreamDownloadRecords 将下载记录逐条写入 w，不在内存中构建完整文件；
+// ids 非空时按 ID 导出，否则按 filter 过滤（nil 表示全部），返回导出的记录数
+func (s *ExportService) StreamDownloadRecords(w io.Writer, format ExportFormat, ids []string, filter *database.FilterParams) (int, error) {
+	return streamRecords(w, format, &downloadTable, func(fn func(*database.DownloadRecord) error) error {
+		return s.downloadRepo.ForEach(ids, filter, fn)
+	})
 }
 
 // formatDuration 将毫秒持续时间格式化为 MM:SS 字符串
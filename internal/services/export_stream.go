@@ -0,0 +1,656 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recordEncoder 将记录逐条写入输出，End 之前不保证内容完整；
+// Begin 成功后遍历或写入失败时调用 Abort 释放临时资源，之后不再调用 End
+type recordEncoder[T any] interface {
+	Begin() error
+	Encode(record *T) error
+	End() error
+	Abort()
+}
+
+// newRecordEncoder 根据格式创建编码器
+func newRecordEncoder[T any](w io.Writer, format ExportFormat, table *exportTable[T]) (recordEncoder[T], error) {
+	switch format {
+	case ExportFormatJSON:
+		return &jsonEncoder[T]{w: w}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonEncoder[T]{enc: json.NewEncoder(w)}, nil
+	case ExportFormatCSV:
+		return &csvEncoder[T]{w: w, table: table}, nil
+	case ExportFormatXLSX:
+		return &xlsxEncoder[T]{w: w, table: table}, nil
+	case ExportFormatSQLite:
+		return &sqliteEncoder[T]{w: w, table: table}, nil
+	case ExportFormatHTML:
+		return &htmlEncoder[T]{dst: w, table: table}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// streamRecords 依次调用编码器写出 each 遍历到的记录，返回记录数
+func streamRecords[T any](w io.Writer, format ExportFormat, table *exportTable[T],
+	each func(func(*T) error) error) (int, error) {
+	enc, err := newRecordEncoder(w, format, table)
+	if err != nil {
+		return 0, err
+	}
+	if err := enc.Begin(); err != nil {
+		return 0, err
+	}
+	count := 0
+	err = each(func(record *T) error {
+		count++
+		return enc.Encode(record)
+	})
+	if err != nil {
+		enc.Abort()
+		return count, err
+	}
+	return count, enc.End()
+}
+
+// jsonEncoder 输出与 json.MarshalIndent 一致的数组
+type jsonEncoder[T any] struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder[T]) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder[T]) Encode(record *T) error {
+	data, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal record to JSON: %w", err)
+	}
+	sep := ",\n  "
+	if e.count == 0 {
+		sep = "\n  "
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder[T]) End() error {
+	end := "]"
+	if e.count > 0 {
+		end = "\n]"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+func (e *jsonEncoder[T]) Abort() {}
+
+// ndjsonEncoder 每行一条 JSON 记录
+type ndjsonEncoder[T any] struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder[T]) Begin() error { return nil }
+
+func (e *ndjsonEncoder[T]) Encode(record *T) error {
+	if err := e.enc.Encode(record); err != nil {
+		return fmt.Errorf("failed to write NDJSON record: %w", err)
+	}
+	return nil
+}
+
+func (e *ndjsonEncoder[T]) End() error { return nil }
+
+func (e *ndjsonEncoder[T]) Abort() {}
+
+// csvFlushRows CSV 每写入多少行刷新一次
+const csvFlushRows = 500
+
+// csvEncoder 输出带 UTF-8 BOM 的 CSV
+type csvEncoder[T any] struct {
+	w     io.Writer
+	table *exportTable[T]
+	cw    *csv.Writer
+	count int
+}
+
+func (e *csvEncoder[T]) Begin() error {
+	// 写入 UTF-8 BOM 以兼容 Excel
+	if _, err := e.w.Write(utf8BOM); err != nil {
+		return err
+	}
+	e.cw = csv.NewWriter(e.w)
+	if err := e.cw.Write(e.table.CSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return nil
+}
+
+func (e *csvEncoder[T]) Encode(record *T) error {
+	if err := e.cw.Write(e.table.CSVRow(record)); err != nil {
+		return fmt.Errorf("failed to write CSV row: %w", err)
+	}
+	if e.count++; e.count%csvFlushRows == 0 {
+		e.cw.Flush()
+		return e.cw.Error()
+	}
+	return nil
+}
+
+func (e *csvEncoder[T]) End() error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV writer: %w", err)
+	}
+	return nil
+}
+
+func (e *csvEncoder[T]) Abort() {}
+
+// xlsxMaxCellText Excel 单元格文本长度上限
+const xlsxMaxCellText = 32767
+
+// xlsxEncoder 直接生成 Office Open XML 工作簿：数字列写为数值，时间列写为带日期格式的序列值
+type xlsxEncoder[T any] struct {
+	w     io.Writer
+	table *exportTable[T]
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+// xlsxStyles 样式 0 为默认，1 为日期时间，2 为加粗表头
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+
+func (e *xlsxEncoder[T]) Begin() error {
+	e.zw = zip.NewWriter(e.w)
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="` + xmlEscape(e.table.Name) + `" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := e.zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create xlsx part %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := e.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create xlsx sheet: %w", err)
+	}
+	e.sheet = bufio.NewWriter(f)
+	// 冻结表头行
+	e.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+<sheetData>`)
+
+	e.row = 1
+	e.sheet.WriteString(`<row r="1">`)
+	for i, col := range e.table.Columns {
+		e.writeText(i, col.Header, 2)
+	}
+	e.sheet.WriteString("</row>")
+	return nil
+}
+
+func (e *xlsxEncoder[T]) Encode(record *T) error {
+	e.row++
+	fmt.Fprintf(e.sheet, `<row r="%d">`, e.row)
+	for i, col := range e.table.Columns {
+		switch v := col.Value(record).(type) {
+		case int64:
+			fmt.Fprintf(e.sheet, `<c r="%s"><v>%d</v></c>`, xlsxCellRef(i, e.row), v)
+		case time.Time:
+			if !v.IsZero() {
+				fmt.Fprintf(e.sheet, `<c r="%s" s="1"><v>%s</v></c>`, xlsxCellRef(i, e.row),
+					strconv.FormatFloat(excelSerial(v), 'f', -1, 64))
+			}
+		case string:
+			if v != "" {
+				e.writeText(i, v, 0)
+			}
+		}
+	}
+	_, err := e.sheet.WriteString("</row>")
+	return err
+}
+
+func (e *xlsxEncoder[T]) End() error {
+	e.sheet.WriteString("</sheetData>")
+	if e.row > 1 {
+		fmt.Fprintf(e.sheet, `<autoFilter ref="A1:%s"/>`, xlsxCellRef(len(e.table.Columns)-1, e.row))
+	}
+	e.sheet.WriteString("</worksheet>")
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	if err := e.zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish xlsx: %w", err)
+	}
+	return nil
+}
+
+// Abort 不写出未完成的工作簿，输出已写入的部分由调用方丢弃
+func (e *xlsxEncoder[T]) Abort() {}
+
+// writeText 写入内联字符串单元格
+func (e *xlsxEncoder[T]) writeText(col int, text string, style int) {
+	if len(text) > xlsxMaxCellText {
+		if runes := []rune(text); len(runes) > xlsxMaxCellText {
+			text = string(runes[:xlsxMaxCellText])
+		}
+	}
+	styleAttr := ""
+	if style > 0 {
+		styleAttr = fmt.Sprintf(` s="%d"`, style)
+	}
+	fmt.Fprintf(e.sheet, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`,
+		xlsxCellRef(col, e.row), styleAttr, xmlEscape(text))
+}
+
+// xlsxCellRef 返回从 0 开始的列号和从 1 开始的行号对应的单元格引用，如 A1、AB12
+func xlsxCellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+// excelEpoch Excel 日期序列值的起点（1900 日期系统）
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelSerial 将时间按本地时区转换为 Excel 日期序列值
+func excelSerial(t time.Time) float64 {
+	local := t.Local()
+	wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+// xmlEscape 转义 XML 文本，非法字符替换为 U+FFFD
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// sqliteEncoder 写入临时 SQLite 文件，完成后整体复制到输出
+type sqliteEncoder[T any] struct {
+	w     io.Writer
+	table *exportTable[T]
+	dir   string
+	db    *sql.DB
+	tx    *sql.Tx
+	stmt  *sql.Stmt
+	count int
+}
+
+func (e *sqliteEncoder[T]) Begin() error {
+	dir, err := os.MkdirTemp("", "wx_channel_export_")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	e.dir = dir
+	if e.db, err = sql.Open("sqlite3", filepath.Join(dir, "export.db")); err != nil {
+		e.cleanup()
+		return fmt.Errorf("failed to create sqlite export: %w", err)
+	}
+
+	cols := make([]string, len(e.table.Columns))
+	placeholders := make([]string, len(e.table.Columns))
+	for i, col := range e.table.Columns {
+		colType := "TEXT"
+		if col.Kind == columnInt {
+			colType = "INTEGER"
+		} else if col.Kind == columnTime {
+			colType = "DATETIME"
+		}
+		cols[i] = col.Name + " " + colType
+		placeholders[i] = "?"
+	}
+	schema := fmt.Sprintf(`
+		CREATE TABLE %s (%s);
+		CREATE TABLE export_info (key TEXT PRIMARY KEY, value TEXT);
+	`, e.table.Name, strings.Join(cols, ", "))
+	if _, err := e.db.Exec(schema); err != nil {
+		e.cleanup()
+		return fmt.Errorf("failed to create sqlite export schema: %w", err)
+	}
+
+	if e.tx, err = e.db.Begin(); err != nil {
+		e.cleanup()
+		return err
+	}
+	e.stmt, err = e.tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", e.table.Name, strings.Join(placeholders, ", ")))
+	if err != nil {
+		e.cleanup()
+		return fmt.Errorf("failed to prepare sqlite export: %w", err)
+	}
+	return nil
+}
+
+func (e *sqliteEncoder[T]) Encode(record *T) error {
+	values := make([]interface{}, len(e.table.Columns))
+	for i, col := range e.table.Columns {
+		value := col.Value(record)
+		if t, ok := value.(time.Time); ok {
+			// RFC3339 可直接被 SQLite 日期函数解析
+			if t.IsZero() {
+				value = nil
+			} else {
+				value = t.Format(time.RFC3339)
+			}
+		}
+		values[i] = value
+	}
+	if _, err := e.stmt.Exec(values...); err != nil {
+		return fmt.Errorf("failed to write sqlite export row: %w", err)
+	}
+	e.count++
+	return nil
+}
+
+func (e *sqliteEncoder[T]) End() error {
+	defer e.cleanup()
+	e.stmt.Close()
+	if _, err := e.tx.Exec("INSERT INTO export_info (key, value) VALUES ('table', ?), ('record_count', ?), ('exported_at', ?)",
+		e.table.Name, strconv.Itoa(e.count), time.Now().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to write sqlite export info: %w", err)
+	}
+	if err := e.tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sqlite export: %w", err)
+	}
+	e.tx = nil
+	if err := e.db.Close(); err != nil {
+		return err
+	}
+	e.db = nil
+
+	f, err := os.Open(filepath.Join(e.dir, "export.db"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(e.w, f)
+	return err
+}
+
+func (e *sqliteEncoder[T]) Abort() { e.cleanup() }
+
+// cleanup 关闭临时数据库并删除临时目录，可重复调用
+func (e *sqliteEncoder[T]) cleanup() {
+	if e.tx != nil {
+		e.tx.Rollback()
+		e.tx = nil
+	}
+	if e.db != nil {
+		e.db.Close()
+		e.db = nil
+	}
+	if e.dir != "" {
+		os.RemoveAll(e.dir)
+		e.dir = ""
+	}
+}
+
+// htmlTopAuthors HTML 报告中列出的作者数
+const htmlTopAuthors = 10
+
+// htmlCoverTimeout 下载单张封面的超时时间
+const htmlCoverTimeout = 5 * time.Second
+
+var (
+	// htmlMaxCovers 单个报告最多嵌入的封面数，超出后引用远程地址
+	htmlMaxCovers = 500
+	// htmlMaxCoverBytes 单张封面的大小上限，超出后引用远程地址
+	htmlMaxCoverBytes int64 = 512 << 10
+	// htmlMaxCoverFailures 连续下载失败多少次后不再尝试（如离线导出）
+	htmlMaxCoverFailures = 5
+	// htmlCoverClient 下载封面的客户端，测试中可替换
+	htmlCoverClient = &http.Client{Timeout: htmlCoverTimeout}
+)
+
+// htmlEncoder 输出单文件 HTML 报告：记录表格逐行写出，统计汇总在末尾写出并通过 CSS 显示在顶部。
+// 封面下载后以 data: URI 嵌入，离线也能显示；超出数量或大小上限、下载失败时引用远程地址（不发送 Referer）
+type htmlEncoder[T any] struct {
+	w        *bufio.Writer
+	dst      io.Writer
+	table    *exportTable[T]
+	covers   int // 已嵌入的封面数
+	failures int // 连续下载失败次数
+	count    int
+	size     int64
+	dur      int64
+	likes    int64
+	first    time.Time
+	last     time.Time
+	authors  map[string]int
+	status   map[string]int
+}
+
+const htmlReportStyle = `<style>
+body{margin:0;font-family:-apple-system,"Segoe UI","PingFang SC","Microsoft YaHei",sans-serif;background:#f5f6f8;color:#222}
+main{display:flex;flex-direction:column;max-width:1200px;margin:0 auto;padding:24px}
+h1{order:0;margin:0 0 16px;font-size:22px}
+.summary{order:1;display:grid;grid-template-columns:repeat(auto-fill,minmax(180px,1fr));gap:12px;margin-bottom:20px}
+.card{background:#fff;border-radius:8px;padding:12px 16px;box-shadow:0 1px 2px rgba(0,0,0,.06)}
+.card b{display:block;font-size:20px;margin-top:4px}
+.card ol{margin:4px 0 0;padding-left:20px;font-size:13px}
+table{order:2;width:100%;border-collapse:collapse;background:#fff;border-radius:8px;overflow:hidden;font-size:13px}
+th,td{padding:8px;border-bottom:1px solid #eee;text-align:left;vertical-align:middle}
+th{background:#fafafa;position:sticky;top:0}
+td.num{text-align:right;white-space:nowrap}
+img{width:64px;height:64px;object-fit:cover;border-radius:4px;background:#eee}
+</style>`
+
+func (e *htmlEncoder[T]) Begin() error {
+	e.authors = make(map[string]int)
+	e.status = make(map[string]int)
+	e.w = bufio.NewWriter(e.dst)
+	title := html.EscapeString(e.table.Title) + "导出报告"
+	fmt.Fprintf(e.w, `<!DOCTYPE html>
+<html lang="zh-CN"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1">
+<meta name="referrer" content="no-referrer"><title>%s</title>%s</head>
+<body><main><h1>%s</h1>
+<table><thead><tr><th>封面</th><th>标题</th><th>作者</th><th>时间</th><th>时长</th><th>大小</th><th>点赞</th><th>评论</th><th>状态</th></tr></thead><tbody>
+`, title, htmlReportStyle, title)
+	return nil
+}
+
+func (e *htmlEncoder[T]) Encode(record *T) error {
+	item := e.table.Summary(record)
+	e.count++
+	e.size += item.Size
+	e.dur += item.Duration
+	e.likes += item.Likes
+	if !item.Time.IsZero() {
+		if e.first.IsZero() || item.Time.Before(e.first) {
+			e.first = item.Time
+		}
+		if item.Time.After(e.last) {
+			e.last = item.Time
+		}
+	}
+	if item.Author != "" {
+		e.authors[item.Author]++
+	}
+	if item.Status != "" {
+		e.status[item.Status]++
+	}
+
+	cover := ""
+	if strings.HasPrefix(item.CoverURL, "https://") || strings.HasPrefix(item.CoverURL, "http://") {
+		cover = fmt.Sprintf(`<img loading="lazy" src="%s" alt="">`, html.EscapeString(e.coverSrc(item.CoverURL)))
+	}
+	timeText := ""
+	if !item.Time.IsZero() {
+		timeText = item.Time.Local().Format("2006-01-02 15:04")
+	}
+	_, err := fmt.Fprintf(e.w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td class=num>%s</td><td class=num>%s</td><td class=num>%d</td><td class=num>%d</td><td>%s</td></tr>\n",
+		cover, html.EscapeString(item.Title), html.EscapeString(item.Author), timeText,
+		formatDuration(item.Duration), formatFileSize(item.Size), item.Likes, item.Comments, html.EscapeString(item.Status))
+	return err
+}
+
+func (e *htmlEncoder[T]) End() error {
+	e.w.WriteString("</tbody></table>\n<section class=\"summary\">\n")
+	card := func(label, value string) {
+		fmt.Fprintf(e.w, "<div class=\"card\">%s<b>%s</b></div>\n", label, value)
+	}
+	card("记录数", strconv.Itoa(e.count))
+	card("总大小", formatFileSize(e.size))
+	card("总时长", formatHours(e.dur))
+	card("总点赞", strconv.FormatInt(e.likes, 10))
+	if !e.first.IsZero() {
+		card("时间范围", e.first.Local().Format("2006-01-02")+" ~ "+e.last.Local().Format("2006-01-02"))
+	}
+	card("导出时间", time.Now().Format("2006-01-02 15:04"))
+	if len(e.status) > 0 {
+		e.writeRanking("状态", e.status)
+	}
+	if len(e.authors) > 0 {
+		e.writeRanking("作者（前 10）", e.authors)
+	}
+	e.w.WriteString("</section>\n</main></body></html>\n")
+	return e.w.Flush()
+}
+
+func (e *htmlEncoder[T]) Abort() {}
+
+// coverSrc 返回封面的 data: URI，无法嵌入时返回原地址
+func (e *htmlEncoder[T]) coverSrc(url string) string {
+	if e.covers >= htmlMaxCovers || e.failures >= htmlMaxCoverFailures {
+		return url
+	}
+	data, err := fetchCover(url)
+	if err != nil {
+		e.failures++
+		return url
+	}
+	e.covers++
+	e.failures = 0
+	return data
+}
+
+// fetchCover 下载封面并编码为 data: URI，只接受图片内容
+func fetchCover(url string) (string, error) {
+	resp, err := htmlCoverClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if resp.ContentLength > htmlMaxCoverBytes {
+		return "", fmt.Errorf("cover too large: %d bytes", resp.ContentLength)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, htmlMaxCoverBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > htmlMaxCoverBytes {
+		return "", fmt.Errorf("cover too large")
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("cover is not an image: %s", contentType)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// writeRanking 按数量倒序写出排行卡片
+func (e *htmlEncoder[T]) writeRanking(label string, counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > htmlTopAuthors {
+		names = names[:htmlTopAuthors]
+	}
+	fmt.Fprintf(e.w, "<div class=\"card\">%s<ol>", label)
+	for _, name := range names {
+		fmt.Fprintf(e.w, "<li>%s（%d）</li>", html.EscapeString(name), counts[name])
+	}
+	e.w.WriteString("</ol></div>\n")
+}
+
+// formatHours 将毫秒时长格式化为小时数
+func formatHours(ms int64) string {
+	return fmt.Sprintf("%.1f 小时", float64(ms)/3600000)
+}
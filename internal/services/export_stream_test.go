@@ -0,0 +1,316 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"wx_channel/internal/database"
+)
+
+// exportTestRecords 返回用于导出测试的浏览记录
+func exportTestRecords() []database.BrowseRecord {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	return []database.BrowseRecord{
+		{ID: "b1", Title: `<标题> & "引号"`, Author: "作者", Duration: 61000, Size: 2048, LikeCount: 42,
+			CoverURL: "https://cover/1", BrowseTime: base, CreatedAt: base, UpdatedAt: base},
+		{ID: "b2", Title: "第二条", Author: "作者", LikeCount: 3, BrowseTime: base.Add(time.Hour)},
+		{ID: "b3", Title: "第三条", Author: "另一个作者"},
+	}
+}
+
+// exportTestEach 依次输出 records，failAfter 大于 0 时在输出该数量的记录后返回错误
+func exportTestEach(records []database.BrowseRecord, failAfter int) func(func(*database.BrowseRecord) error) error {
+	return func(emit func(*database.BrowseRecord) error) error {
+		for i := range records {
+			if failAfter > 0 && i == failAfter {
+				return errors.New("query failed")
+			}
+			if err := emit(&records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// exportColumnIndex 返回表头对应的列号
+func exportColumnIndex(t *testing.T, header string) int {
+	t.Helper()
+	for i, col := range browseTable.Columns {
+		if col.Header == header {
+			return i
+		}
+	}
+	t.Fatalf("column %s not found", header)
+	return -1
+}
+
+func TestJSONEncoderMatchesMarshalIndent(t *testing.T) {
+	records := exportTestRecords()
+	for _, n := range []int{0, 1, len(records)} {
+		var buf bytes.Buffer
+		count, err := streamRecords(&buf, ExportFormatJSON, &browseTable, exportTestEach(records[:n], 0))
+		if err != nil || count != n {
+			t.Fatalf("stream %d records: count %d, err %v", n, count, err)
+		}
+		want, _ := json.MarshalIndent(records[:n], "", "  ")
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("%d records: output differs from MarshalIndent\ngot:  %s\nwant: %s", n, buf.Bytes(), want)
+		}
+	}
+}
+
+// xlsxTestSheet 是工作表 XML 中测试用到的部分
+type xlsxTestSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref   string `xml:"r,attr"`
+			Style string `xml:"s,attr"`
+			Type  string `xml:"t,attr"`
+			Value string `xml:"v"`
+			Text  string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXEncoder(t *testing.T) {
+	records := exportTestRecords()
+	var buf bytes.Buffer
+	if _, err := streamRecords(&buf, ExportFormatXLSX, &browseTable, exportTestEach(records, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("output is not a zip: %v", err)
+	}
+	parts := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+		// 每个部件都必须是格式正确的 XML
+		dec := xml.NewDecoder(bytes.NewReader(parts[f.Name]))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not valid XML: %v", f.Name, err)
+			}
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing xlsx part %s", name)
+		}
+	}
+
+	var sheet xlsxTestSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != len(records)+1 || len(sheet.Rows[0].Cells) != len(browseTable.Columns) {
+		t.Fatalf("expected header and %d rows, got %d", len(records), len(sheet.Rows))
+	}
+	cells := make(map[string]struct{ style, typ, value, text string })
+	for _, row := range sheet.Rows {
+		for _, c := range row.Cells {
+			cells[c.Ref] = struct{ style, typ, value, text string }{c.Style, c.Type, c.Value, c.Text}
+		}
+	}
+
+	if c := cells["A1"]; c.typ != "inlineStr" || c.style != "2" || c.text != "ID" {
+		t.Errorf("unexpected header cell %+v", c)
+	}
+	if c := cells[xlsxCellRef(exportColumnIndex(t, "Title"), 2)]; c.typ != "inlineStr" || c.text != records[0].Title {
+		t.Errorf("unexpected text cell %+v", c)
+	}
+	// 数字列写为数值
+	if c := cells[xlsxCellRef(exportColumnIndex(t, "LikeCount"), 2)]; c.typ != "" || c.value != "42" {
+		t.Errorf("unexpected numeric cell %+v", c)
+	}
+	// 时间列写为带日期格式的序列值，零值时间留空
+	browseTime := exportColumnIndex(t, "BrowseTime")
+	c := cells[xlsxCellRef(browseTime, 2)]
+	if serial, err := strconv.ParseFloat(c.value, 64); c.style != "1" || c.typ != "" || err != nil || serial != 46082.5 {
+		t.Errorf("unexpected date cell %+v", c)
+	}
+	if c, ok := cells[xlsxCellRef(browseTime, 4)]; ok {
+		t.Errorf("expected zero time to be empty, got %+v", c)
+	}
+}
+
+func TestSQLiteEncoder(t *testing.T) {
+	records := exportTestRecords()
+	var buf bytes.Buffer
+	count, err := streamRecords(&buf, ExportFormatSQLite, &browseTable, exportTestEach(records, 0))
+	if err != nil || count != len(records) {
+		t.Fatalf("count %d, err %v", count, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "export.db")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var rows int
+	if err := db.QueryRow("SELECT COUNT(*) FROM browse_history").Scan(&rows); err != nil || rows != len(records) {
+		t.Fatalf("expected %d rows, got %d %v", len(records), rows, err)
+	}
+	var recordCount string
+	if err := db.QueryRow("SELECT value FROM export_info WHERE key = 'record_count'").Scan(&recordCount); err != nil || recordCount != "3" {
+		t.Errorf("unexpected record_count %q %v", recordCount, err)
+	}
+	var likes int64
+	var browseTime string
+	var firstSeen sql.NullString
+	err = db.QueryRow("SELECT like_count, browse_time, first_seen FROM browse_history WHERE id = 'b1'").Scan(&likes, &browseTime, &firstSeen)
+	if err != nil || likes != 42 || browseTime != records[0].BrowseTime.Format(time.RFC3339) || firstSeen.Valid {
+		t.Errorf("unexpected row values %d %s %v %v", likes, browseTime, firstSeen, err)
+	}
+}
+
+func TestSQLiteEncoderAbort(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	var buf bytes.Buffer
+	if _, err := streamRecords(&buf, ExportFormatSQLite, &browseTable, exportTestEach(exportTestRecords(), 1)); err == nil {
+		t.Fatal("expected query error")
+	}
+	// 中途失败时删除临时目录
+	if entries, _ := os.ReadDir(tmp); len(entries) != 0 {
+		t.Errorf("expected temp dir to be removed, found %d entries", len(entries))
+	}
+}
+
+// roundTripFunc 以函数实现 http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// stubHTMLCovers 替换封面下载，covers 中没有的地址返回 404，并记录请求次数
+func stubHTMLCovers(t *testing.T, covers map[string][]byte) *int {
+	t.Helper()
+	requests := 0
+	old := htmlCoverClient
+	htmlCoverClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		data, ok := covers[r.URL.String()]
+		status := http.StatusOK
+		if !ok {
+			status = http.StatusNotFound
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(data)), ContentLength: int64(len(data)), Request: r}, nil
+	})}
+	t.Cleanup(func() { htmlCoverClient = old })
+	return &requests
+}
+
+func TestHTMLEncoderEscapes(t *testing.T) {
+	stubHTMLCovers(t, nil)
+	records := []database.BrowseRecord{
+		{ID: "b1", Title: `<script>alert("x")</script>`, Author: `A&B "作者"`, CoverURL: `https://cover/1?a=1&b="x"`},
+		{ID: "b2", Title: "封面无效", Author: "<i>作者</i>", CoverURL: "javascript:alert(1)"},
+	}
+	var buf bytes.Buffer
+	if _, err := streamRecords(&buf, ExportFormatHTML, &browseTable, exportTestEach(records, 0)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, unsafe := range []string{"<script>", "<i>作者</i>", "javascript:", `b="x"`} {
+		if strings.Contains(out, unsafe) {
+			t.Errorf("output contains unescaped %q", unsafe)
+		}
+	}
+	for _, escaped := range []string{
+		"&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;",
+		"A&amp;B &#34;作者&#34;",
+		"&lt;i&gt;作者&lt;/i&gt;（1）",
+		`src="https://cover/1?a=1&amp;b=&#34;x&#34;"`,
+	} {
+		if !strings.Contains(out, escaped) {
+			t.Errorf("output is missing %q", escaped)
+		}
+	}
+	if !strings.HasSuffix(out, "</main></body></html>\n") {
+		t.Error("report is not complete")
+	}
+}
+
+func TestHTMLEncoderEmbedsCovers(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	requests := stubHTMLCovers(t, map[string][]byte{
+		"https://cover/png":   png,
+		"https://cover/large": append(append([]byte{}, png...), make([]byte, 64)...),
+		"https://cover/text":  []byte("<html>not an image</html>"),
+	})
+	oldMaxBytes, oldMaxCovers := htmlMaxCoverBytes, htmlMaxCovers
+	htmlMaxCoverBytes, htmlMaxCovers = 32, 2
+	t.Cleanup(func() { htmlMaxCoverBytes, htmlMaxCovers = oldMaxBytes, oldMaxCovers })
+
+	records := []database.BrowseRecord{
+		{ID: "b1", CoverURL: "https://cover/png"},
+		{ID: "b2", CoverURL: "https://cover/large"},
+		{ID: "b3", CoverURL: "https://cover/text"},
+		{ID: "b4", CoverURL: "https://cover/missing"},
+		{ID: "b5", CoverURL: "https://cover/png"},
+		{ID: "b6", CoverURL: "https://cover/png"},
+	}
+	var buf bytes.Buffer
+	if _, err := streamRecords(&buf, ExportFormatHTML, &browseTable, exportTestEach(records, 0)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	embedded := `src="data:image/png;base64,` + base64.StdEncoding.EncodeToString(png) + `"`
+	if n := strings.Count(out, embedded); n != htmlMaxCovers {
+		t.Errorf("expected %d embedded covers, got %d", htmlMaxCovers, n)
+	}
+	// 超出大小、非图片、下载失败以及超出数量上限的封面引用远程地址
+	for _, remote := range []string{`src="https://cover/large"`, `src="https://cover/text"`, `src="https://cover/missing"`, `src="https://cover/png"`} {
+		if !strings.Contains(out, remote) {
+			t.Errorf("expected remote cover %s", remote)
+		}
+	}
+	if *requests != 5 {
+		t.Errorf("expected no download after reaching the cover limit, got %d requests", *requests)
+	}
+}
+
+func TestHTMLEncoderStopsAfterCoverFailures(t *testing.T) {
+	requests := stubHTMLCovers(t, nil)
+	records := make([]database.BrowseRecord, htmlMaxCoverFailures+3)
+	for i := range records {
+		records[i] = database.BrowseRecord{ID: strconv.Itoa(i), CoverURL: "https://cover/" + strconv.Itoa(i)}
+	}
+	if _, err := streamRecords(io.Discard, ExportFormatHTML, &browseTable, exportTestEach(records, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if *requests != htmlMaxCoverFailures {
+		t.Errorf("expected %d requests before giving up, got %d", htmlMaxCoverFailures, *requests)
+	}
+}
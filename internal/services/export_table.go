@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"wx_channel/internal/database"
+)
+
+// columnKind 表示导出列的数据类型，XLSX 和 SQLite 按类型写入
+type columnKind int
+
+const (
+	columnText columnKind = iota
+	columnInt
+	columnTime
+)
+
+// exportColumn 描述一个导出列
+type exportColumn[T any] struct {
+	Header string // XLSX/HTML 表头
+	Name   string // SQLite 列名
+	Kind   columnKind
+	Value  func(*T) interface{} // 返回 string、int64 或 time.Time
+}
+
+// exportTable 描述一类记录的导出方式
+type exportTable[T any] struct {
+	Name      string // SQLite 表名与 XLSX 工作表名
+	Title     string // HTML 报告标题
+	Columns   []exportColumn[T]
+	CSVHeader []string
+	CSVRow    func(*T) []string // 与旧版 CSV 导出保持一致，导入时按相同列解析
+	Summary   func(*T) reportItem
+}
+
+// reportItem 是 HTML 报告中一条记录的展示内容
+type reportItem struct {
+	Title    string
+	Author   string
+	CoverURL string
+	Time     time.Time
+	Duration int64 // 毫秒
+	Size     int64 // 字节
+	Status   string
+	Likes    int64
+	Comments int64
+}
+
+// browseTable 浏览记录的导出列
+var browseTable = exportTable[database.BrowseRecord]{
+	Name:  "browse_history",
+	Title: "浏览记录",
+	Columns: []exportColumn[database.BrowseRecord]{
+		{"ID", "id", columnText, func(r *database.BrowseRecord) interface{} { return r.ID }},
+		{"Title", "title", columnText, func(r *database.BrowseRecord) interface{} { return r.Title }},
+		{"Author", "author", columnText, func(r *database.BrowseRecord) interface{} { return r.Author }},
+		{"AuthorID", "author_id", columnText, func(r *database.BrowseRecord) interface{} { return r.AuthorID }},
+		{"Duration (ms)", "duration", columnInt, func(r *database.BrowseRecord) interface{} { return r.Duration }},
+		{"Size (bytes)", "size", columnInt, func(r *database.BrowseRecord) interface{} { return r.Size }},
+		{"Resolution", "resolution", columnText, func(r *database.BrowseRecord) interface{} { return r.Resolution }},
+		{"CoverURL", "cover_url", columnText, func(r *database.BrowseRecord) interface{} { return r.CoverURL }},
+		{"VideoURL", "video_url", columnText, func(r *database.BrowseRecord) interface{} { return r.VideoURL }},
+		{"DecryptKey", "decrypt_key", columnText, func(r *database.BrowseRecord) interface{} { return r.DecryptKey }},
+		{"BrowseTime", "browse_time", columnTime, func(r *database.BrowseRecord) interface{} { return r.BrowseTime }},
+		{"LikeCount", "like_count", columnInt, func(r *database.BrowseRecord) interface{} { return r.LikeCount }},
+		{"CommentCount", "comment_count", columnInt, func(r *database.BrowseRecord) interface{} { return r.CommentCount }},
+		{"FavCount", "fav_count", columnInt, func(r *database.BrowseRecord) interface{} { return r.FavCount }},
+		{"ForwardCount", "forward_count", columnInt, func(r *database.BrowseRecord) interface{} { return r.ForwardCount }},
+		{"PageURL", "page_url", columnText, func(r *database.BrowseRecord) interface{} { return r.PageURL }},
+		{"FirstSeen", "first_seen", columnTime, func(r *database.BrowseRecord) interface{} { return r.FirstSeen }},
+		{"ViewCount", "view_count", columnInt, func(r *database.BrowseRecord) interface{} { return r.ViewCount }},
+		{"Sources", "sources", columnText, func(r *database.BrowseRecord) interface{} { return strings.Join(r.Sources, ",") }},
+		{"CreatedAt", "created_at", columnTime, func(r *database.BrowseRecord) interface{} { return r.CreatedAt }},
+		{"UpdatedAt", "updated_at", columnTime, func(r *database.BrowseRecord) interface{} { return r.UpdatedAt }},
+	},
+	CSVHeader: []string{
+		"ID", "Title", "Author", "AuthorID", "Duration", "Size", "Resolution",
+		"CoverURL", "VideoURL", "DecryptKey", "BrowseTime", "LikeCount",
+		"CommentCount", "FavCount", "ForwardCount", "PageURL", "CreatedAt", "UpdatedAt",
+	},
+	CSVRow: func(record *database.BrowseRecord) []string {
+		return []string{
+			record.ID,
+			record.Title,
+			record.Author,
+			record.AuthorID,
+			fmt.Sprintf("%d", record.Duration),
+			fmt.Sprintf("%d", record.Size),
+			record.Resolution,
+			record.CoverURL,
+			record.VideoURL,
+			record.DecryptKey,
+			record.BrowseTime.Format(time.RFC3339),
+			fmt.Sprintf("%d", record.LikeCount),
+			fmt.Sprintf("%d", record.CommentCount),
+			fmt.Sprintf("%d", record.FavCount),
+			fmt.Sprintf("%d", record.ForwardCount),
+			record.PageURL,
+			record.CreatedAt.Format(time.RFC3339),
+			record.UpdatedAt.Format(time.RFC3339),
+		}
+	},
+	Summary: func(r *database.BrowseRecord) reportItem {
+		return reportItem{
+			Title: r.Title, Author: r.Author, CoverURL: r.CoverURL, Time: r.BrowseTime,
+			Duration: r.Duration, Size: r.Size, Likes: r.LikeCount, Comments: r.CommentCount,
+		}
+	},
+}
+
+// downloadTable 下载记录的导出列
+var downloadTable = exportTable[database.DownloadRecord]{
+	Name:  "download_records",
+	Title: "下载记录",
+	Columns: []exportColumn[database.DownloadRecord]{
+		{"ID", "id", columnText, func(r *database.DownloadRecord) interface{} { return r.ID }},
+		{"VideoID", "video_id", columnText, func(r *database.DownloadRecord) interface{} { return r.VideoID }},
+		{"Title", "title", columnText, func(r *database.DownloadRecord) interface{} { return r.Title }},
+		{"Author", "author", columnText, func(r *database.DownloadRecord) interface{} { return r.Author }},
+		{"AuthorID", "author_id", columnText, func(r *database.DownloadRecord) interface{} { return r.AuthorID }},
+		{"CoverURL", "cover_url", columnText, func(r *database.DownloadRecord) interface{} { return r.CoverURL }},
+		{"Duration (ms)", "duration", columnInt, func(r *database.DownloadRecord) interface{} { return r.Duration }},
+		{"FileSize (bytes)", "file_size", columnInt, func(r *database.DownloadRecord) interface{} { return r.FileSize }},
+		{"FilePath", "file_path", columnText, func(r *database.DownloadRecord) interface{} { return r.FilePath }},
+		{"Format", "format", columnText, func(r *database.DownloadRecord) interface{} { return r.Format }},
+		{"Resolution", "resolution", columnText, func(r *database.DownloadRecord) interface{} { return r.Resolution }},
+		{"Status", "status", columnText, func(r *database.DownloadRecord) interface{} { return r.Status }},
+		{"DownloadTime", "download_time", columnTime, func(r *database.DownloadRecord) interface{} { return r.DownloadTime }},
+		{"LikeCount", "like_count", columnInt, func(r *database.DownloadRecord) interface{} { return r.LikeCount }},
+		{"CommentCount", "comment_count", columnInt, func(r *database.DownloadRecord) interface{} { return r.CommentCount }},
+		{"ForwardCount", "forward_count", columnInt, func(r *database.DownloadRecord) interface{} { return r.ForwardCount }},
+		{"FavCount", "fav_count", columnInt, func(r *database.DownloadRecord) interface{} { return r.FavCount }},
+		{"ErrorMessage", "error_message", columnText, func(r *database.DownloadRecord) interface{} { return r.ErrorMessage }},
+		{"CreatedAt", "created_at", columnTime, func(r *database.DownloadRecord) interface{} { return r.CreatedAt }},
+		{"UpdatedAt", "updated_at", columnTime, func(r *database.DownloadRecord) interface{} { return r.UpdatedAt }},
+	},
+	CSVHeader: []string{
+		"ID", "VideoID", "Title", "Author", "Duration", "FileSize",
+		"FilePath", "Format", "Resolution", "Status", "DownloadTime",
+		"LikeCount", "CommentCount", "ForwardCount", "FavCount",
+		"ErrorMessage", "CreatedAt", "UpdatedAt",
+	},
+	CSVRow: func(record *database.DownloadRecord) []string {
+		return []string{
+			record.ID,
+			record.VideoID,
+			record.Title,
+			record.Author,
+			formatDuration(record.Duration),
+			formatFileSize(record.FileSize),
+			record.FilePath,
+			record.Format,
+			record.Resolution,
+			record.Status,
+			record.DownloadTime.Format(time.RFC3339),
+			fmt.Sprintf("%d", record.LikeCount),
+			fmt.Sprintf("%d", record.CommentCount),
+			fmt.Sprintf("%d", record.ForwardCount),
+			fmt.Sprintf("%d", record.FavCount),
+			record.ErrorMessage,
+			record.CreatedAt.Format(time.RFC3339),
+			record.UpdatedAt.Format(time.RFC3339),
+		}
+	},
+	Summary: func(r *database.DownloadRecord) reportItem {
+		return reportItem{
+			Title: r.Title, Author: r.Author, CoverURL: r.CoverURL, Time: r.DownloadTime,
+			Duration: r.Duration, Size: r.FileSize, Status: r.Status, Likes: r.LikeCount, Comments: r.CommentCount,
+		}
+	},
+}
@@ -0,0 +1,454 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"wx_channel/internal/database"
+)
+
+// ImportStrategy 表示导入记录与已有记录 ID 冲突时的处理方式
+type ImportStrategy string
+
+const (
+	ImportStrategySkip      ImportStrategy = "skip"      // 保留已有记录
+	ImportStrategyOverwrite ImportStrategy = "overwrite" // 用导入的记录覆盖
+	ImportStrategyNewest    ImportStrategy = "newest"    // 按 UpdatedAt 保留较新的一条
+)
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	Format   ExportFormat   // 为空时根据内容自动识别
+	Strategy ImportStrategy // 为空时使用 skip
+	DryRun   bool           // 只预览结果，不写入数据库
+}
+
+// ImportRowError 表示单行导入失败的原因，Row 从 1 开始（CSV 不含表头）
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport 导入结果
+type ImportReport struct {
+	Format   ExportFormat     `json:"format"`
+	Strategy ImportStrategy   `json:"strategy"`
+	DryRun   bool             `json:"dryRun"`
+	Total    int              `json:"total"`
+	Created  int              `json:"created"`
+	Updated  int              `json:"updated"`
+	Skipped  int              `json:"skipped"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// importRow 表示解析出的一行记录，Err 非空时该行无法导入
+type importRow[T any] struct {
+	Row    int
+	Record T
+	Err    error
+}
+
+// ImportService 将 ExportService 导出的 JSON/CSV 文件导入回数据库
+type ImportService struct {
+	browseRepo   *database.BrowseHistoryRepository
+	downloadRepo *database.DownloadRecordRepository
+}
+
+// NewImportService 创建一个新的 ImportService
+func NewImportService() *ImportService {
+	return &ImportService{
+		browseRepo:   database.NewBrowseHistoryRepository(),
+		downloadRepo: database.NewDownloadRecordRepository(),
+	}
+}
+
+// ParseImportStrategy 解析冲突处理方式，空字符串表示 skip
+func ParseImportStrategy(value string) (ImportStrategy, error) {
+	switch strategy := ImportStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case "":
+		return ImportStrategySkip, nil
+	case ImportStrategySkip, ImportStrategyOverwrite, ImportStrategyNewest:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unsupported import strategy: %s (expected skip, overwrite or newest)", value)
+	}
+}
+
+// ImportBrowseHistory 导入浏览记录
+func (s *ImportService) ImportBrowseHistory(data []byte, opts ImportOptions) (*ImportReport, error) {
+	report, err := newImportReport(data, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow[database.BrowseRecord]
+	if opts.Format == ExportFormatJSON {
+		rows, err = parseJSONRows[database.BrowseRecord](data)
+	} else {
+		rows, err = parseCSVRows(data, "ID", browseRecordFromCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]time.Time)
+	for _, row := range rows {
+		record := row.Record
+		if row.Err == nil && record.ID == "" {
+			row.Err = fmt.Errorf("missing id")
+		}
+		if row.Err != nil {
+			report.fail(row.Row, record.ID, row.Err)
+			continue
+		}
+		if record.BrowseTime.IsZero() {
+			record.BrowseTime = record.CreatedAt
+		}
+
+		existing, ok := seen[record.ID]
+		if !ok {
+			current, err := s.browseRepo.GetByID(record.ID)
+			if err != nil {
+				report.fail(row.Row, record.ID, err)
+				continue
+			}
+			if current != nil {
+				existing, ok = current.UpdatedAt, true
+			}
+		}
+		report.apply(row.Row, record.ID, ok, existing, record.UpdatedAt, seen, func() error {
+			return s.browseRepo.Import(&record)
+		})
+	}
+	return report, nil
+}
+
+// ImportDownloadRecords 导入下载记录
+func (s *ImportService) ImportDownloadRecords(data []byte, opts ImportOptions) (*ImportReport, error) {
+	report, err := newImportReport(data, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow[database.DownloadRecord]
+	if opts.Format == ExportFormatJSON {
+		rows, err = parseJSONRows[database.DownloadRecord](data)
+	} else {
+		rows, err = parseCSVRows(data, "ID", downloadRecordFromCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]time.Time)
+	for _, row := range rows {
+		record := row.Record
+		if row.Err == nil {
+			row.Err = validateImportedDownload(&record)
+		}
+		if row.Err != nil {
+			report.fail(row.Row, record.ID, row.Err)
+			continue
+		}
+
+		existing, ok := seen[record.ID]
+		if !ok {
+			current, err := s.downloadRepo.GetByID(record.ID)
+			if err != nil {
+				report.fail(row.Row, record.ID, err)
+				continue
+			}
+			if current != nil {
+				existing, ok = current.UpdatedAt, true
+			}
+		}
+		report.apply(row.Row, record.ID, ok, existing, record.UpdatedAt, seen, func() error {
+			return s.downloadRepo.Import(&record)
+		})
+	}
+	return report, nil
+}
+
+// validateImportedDownload 校验下载记录必填字段和状态
+func validateImportedDownload(record *database.DownloadRecord) error {
+	if record.ID == "" {
+		return fmt.Errorf("missing id")
+	}
+	if record.VideoID == "" {
+		return fmt.Errorf("missing videoId")
+	}
+	switch record.Status {
+	case "":
+		record.Status = database.DownloadStatusCompleted
+	case database.DownloadStatusPending, database.DownloadStatusInProgress,
+		database.DownloadStatusCompleted, database.DownloadStatusFailed:
+	default:
+		return fmt.Errorf("invalid status: %s", record.Status)
+	}
+	return nil
+}
+
+// newImportReport 补全选项（识别格式、默认策略）并创建空的导入结果
+func newImportReport(data []byte, opts *ImportOptions) (*ImportReport, error) {
+	if opts.Strategy == "" {
+		opts.Strategy = ImportStrategySkip
+	}
+	if opts.Format == "" {
+		opts.Format = detectImportFormat(data)
+	}
+	if opts.Format != ExportFormatJSON && opts.Format != ExportFormatCSV {
+		return nil, fmt.Errorf("unsupported import format: %s", opts.Format)
+	}
+	return &ImportReport{
+		Format:   opts.Format,
+		Strategy: opts.Strategy,
+		DryRun:   opts.DryRun,
+		Errors:   []ImportRowError{},
+	}, nil
+}
+
+// detectImportFormat 以 [ 开头的内容视为 JSON，否则视为 CSV
+func detectImportFormat(data []byte) ExportFormat {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return ExportFormatJSON
+	}
+	return ExportFormatCSV
+}
+
+// fail 记录失败的行
+func (r *ImportReport) fail(row int, id string, err error) {
+	r.Total++
+	r.Failed++
+	r.Errors = append(r.Errors, ImportRowError{Row: row, ID: id, Error: err.Error()})
+}
+
+// apply 按冲突策略决定新增、覆盖或跳过，非预览模式下执行写入；
+// seen 记录本次已写入的 ID，文件内重复的 ID 按已存在处理
+func (r *ImportReport) apply(row int, id string, exists bool, existing, incoming time.Time,
+	seen map[string]time.Time, write func() error) {
+	r.Total++
+	if exists {
+		switch r.Strategy {
+		case ImportStrategySkip:
+			r.Skipped++
+			return
+		case ImportStrategyNewest:
+			if !incoming.After(existing) {
+				r.Skipped++
+				return
+			}
+		}
+	}
+
+	if !r.DryRun {
+		if err := write(); err != nil {
+			r.Failed++
+			r.Errors = append(r.Errors, ImportRowError{Row: row, ID: id, Error: err.Error()})
+			return
+		}
+	}
+	seen[id] = incoming
+	if exists {
+		r.Updated++
+	} else {
+		r.Created++
+	}
+}
+
+// parseJSONRows 逐条解析 JSON 数组，单条格式错误只影响该行
+func parseJSONRows[T any](data []byte) ([]importRow[T], error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(bytes.TrimPrefix(data, utf8BOM), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	rows := make([]importRow[T], len(items))
+	for i, item := range items {
+		rows[i].Row = i + 1
+		rows[i].Err = json.Unmarshal(item, &rows[i].Record)
+	}
+	return rows, nil
+}
+
+// utf8BOM 导出 CSV 时写入的 UTF-8 BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvRecord 按表头名（不区分大小写）读取 CSV 行，记录遇到的第一个字段错误
+type csvRecord struct {
+	fields []string
+	index  map[string]int
+	err    error
+}
+
+func (c *csvRecord) str(name string) string {
+	if i, ok := c.index[strings.ToLower(name)]; ok && i < len(c.fields) {
+		return strings.TrimSpace(c.fields[i])
+	}
+	return ""
+}
+
+func (c *csvRecord) int64(name string) int64 {
+	value := c.str(name)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil && c.err == nil {
+		c.err = fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return n
+}
+
+func (c *csvRecord) time(name string) time.Time {
+	value := c.str(name)
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil && c.err == nil {
+		c.err = fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return t
+}
+
+// list 解析逗号分隔的列表
+func (c *csvRecord) list(name string) []string {
+	var items []string
+	for _, item := range strings.Split(c.str(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// duration 解析时长：毫秒数，或下载记录导出时使用的 MM:SS
+func (c *csvRecord) duration(name string) int64 {
+	value := c.str(name)
+	if minutes, seconds, ok := strings.Cut(value, ":"); ok {
+		m, err1 := strconv.ParseInt(minutes, 10, 64)
+		s, err2 := strconv.ParseInt(seconds, 10, 64)
+		if (err1 != nil || err2 != nil) && c.err == nil {
+			c.err = fmt.Errorf("invalid %s: %s", name, value)
+		}
+		return (m*60 + s) * 1000
+	}
+	return c.int64(name)
+}
+
+// fileSize 解析文件大小：字节数，或下载记录导出时使用的 "1.50 MB"（只能还原到两位小数的精度）
+func (c *csvRecord) fileSize(name string) int64 {
+	value := c.str(name)
+	number, unit, ok := strings.Cut(value, " ")
+	if !ok {
+		return c.int64(name)
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	exp := strings.Index("BKMGTPE", strings.TrimSuffix(unit, "B"))
+	if unit == "B" {
+		exp = 0
+	}
+	if (err != nil || exp < 0 || len(unit) > 2) && c.err == nil {
+		c.err = fmt.Errorf("invalid %s: %s", name, value)
+		return 0
+	}
+	for ; exp > 0; exp-- {
+		f *= 1024
+	}
+	return int64(f + 0.5)
+}
+
+// parseCSVRows 解析带表头的 CSV，按列名读取字段，key 为必须存在的列，为空表示不检查
+func parseCSVRows[T any](data []byte, key string, build func(*csvRecord) T) ([]importRow[T], error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index[strings.ToLower(key)]; key != "" && !ok {
+		return nil, fmt.Errorf("CSV header is missing the %s column", key)
+	}
+
+	var rows []importRow[T]
+	for n := 1; ; n++ {
+		fields, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			rows = append(rows, importRow[T]{Row: n, Err: err})
+			continue
+		}
+		record := &csvRecord{fields: fields, index: index}
+		row := importRow[T]{Row: n, Record: build(record)}
+		row.Err = record.err
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// browseRecordFromCSV 按 browseTable 的 CSV 列读取浏览记录，缺少的列按零值处理
+func browseRecordFromCSV(c *csvRecord) database.BrowseRecord {
+	return database.BrowseRecord{
+		ID:           c.str("ID"),
+		Title:        c.str("Title"),
+		Author:       c.str("Author"),
+		AuthorID:     c.str("AuthorID"),
+		Duration:     c.duration("Duration"),
+		Size:         c.int64("Size"),
+		Resolution:   c.str("Resolution"),
+		CoverURL:     c.str("CoverURL"),
+		VideoURL:     c.str("VideoURL"),
+		DecryptKey:   c.str("DecryptKey"),
+		BrowseTime:   c.time("BrowseTime"),
+		LikeCount:    c.int64("LikeCount"),
+		CommentCount: c.int64("CommentCount"),
+		FavCount:     c.int64("FavCount"),
+		ForwardCount: c.int64("ForwardCount"),
+		PageURL:      c.str("PageURL"),
+		FirstSeen:    c.time("FirstSeen"),
+		ViewCount:    c.int64("ViewCount"),
+		Sources:      c.list("Sources"),
+		CreatedAt:    c.time("CreatedAt"),
+		UpdatedAt:    c.time("UpdatedAt"),
+	}
+}
+
+// downloadRecordFromCSV 按 downloadTable 的 CSV 列读取下载记录
+func downloadRecordFromCSV(c *csvRecord) database.DownloadRecord {
+	return database.DownloadRecord{
+		ID:           c.str("ID"),
+		VideoID:      c.str("VideoID"),
+		Title:        c.str("Title"),
+		Author:       c.str("Author"),
+		AuthorID:     c.str("AuthorID"),
+		CoverURL:     c.str("CoverURL"),
+		Duration:     c.duration("Duration"),
+		FileSize:     c.fileSize("FileSize"),
+		FilePath:     c.str("FilePath"),
+		Format:       c.str("Format"),
+		Resolution:   c.str("Resolution"),
+		Status:       c.str("Status"),
+		DownloadTime: c.time("DownloadTime"),
+		LikeCount:    c.int64("LikeCount"),
+		CommentCount: c.int64("CommentCount"),
+		ForwardCount: c.int64("ForwardCount"),
+		FavCount:     c.int64("FavCount"),
+		ErrorMessage: c.str("ErrorMessage"),
+		CreatedAt:    c.time("CreatedAt"),
+		UpdatedAt:    c.time("UpdatedAt"),
+	}
+}
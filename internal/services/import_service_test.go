@@ -0,0 +1,249 @@
+package services
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"wx_channel/internal/database"
+)
+
+// setupServiceDB 在临时目录中初始化数据库，测试结束后关闭
+func setupServiceDB(t *testing.T) {
+	t.Helper()
+	if err := database.Initialize(&database.Config{DBPath: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+}
+
+func TestImportDownloadRoundTrip(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format   ExportFormat
+		strategy ImportStrategy
+		created  int
+		updated  int
+		skipped  int
+		d1Title  string // 本地较新的记录
+		d3Title  string // 本地较旧的记录
+	}{
+		{ExportFormatJSON, ImportStrategySkip, 1, 0, 2, "本地修改", "本地旧版"},
+		{ExportFormatJSON, ImportStrategyOverwrite, 1, 2, 0, "视频1", "视频3"},
+		{ExportFormatJSON, ImportStrategyNewest, 1, 1, 1, "本地修改", "视频3"},
+		{ExportFormatCSV, ImportStrategySkip, 1, 0, 2, "本地修改", "本地旧版"},
+		{ExportFormatCSV, ImportStrategyOverwrite, 1, 2, 0, "视频1", "视频3"},
+		{ExportFormatCSV, ImportStrategyNewest, 1, 1, 1, "本地修改", "视频3"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format)+"/"+string(tt.strategy), func(t *testing.T) {
+			setupServiceDB(t)
+			repo := database.NewDownloadRecordRepository()
+			for i, id := range []string{"d1", "d2", "d3"} {
+				record := &database.DownloadRecord{
+					ID: id, VideoID: "v" + id, Title: "视频" + id[1:], Author: "作者", Status: database.DownloadStatusCompleted,
+					Duration: 90500, FileSize: 1572864, DownloadTime: base, CreatedAt: base, UpdatedAt: base.Add(time.Duration(i) * time.Minute),
+				}
+				if err := repo.Import(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			exported, err := NewExportService().ExportDownloadRecords(tt.format, nil)
+			if err != nil || exported.RecordCount != 3 {
+				t.Fatalf("export failed: %v", err)
+			}
+
+			// 导出后本地修改：d1 变新，d2 删除，d3 变旧
+			d1, _ := repo.GetByID("d1")
+			d1.Title, d1.UpdatedAt = "本地修改", base.Add(time.Hour)
+			d3, _ := repo.GetByID("d3")
+			d3.Title, d3.UpdatedAt = "本地旧版", base.Add(-time.Hour)
+			for _, record := range []*database.DownloadRecord{d1, d3} {
+				if err := repo.Import(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := repo.Delete("d2"); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := NewImportService().ImportDownloadRecords(exported.Data, ImportOptions{Strategy: tt.strategy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Format != tt.format || report.Total != 3 || report.Created != tt.created ||
+				report.Updated != tt.updated || report.Skipped != tt.skipped || report.Failed != 0 {
+				t.Fatalf("unexpected report %+v", report)
+			}
+
+			if got, _ := repo.GetByID("d1"); got == nil || got.Title != tt.d1Title {
+				t.Errorf("d1: expected title %s, got %+v", tt.d1Title, got)
+			}
+			if got, _ := repo.GetByID("d3"); got == nil || got.Title != tt.d3Title {
+				t.Errorf("d3: expected title %s, got %+v", tt.d3Title, got)
+			}
+			d2, _ := repo.GetByID("d2")
+			if d2 == nil || d2.Title != "视频2" || d2.VideoID != "vd2" || d2.FileSize != 1572864 {
+				t.Fatalf("d2 was not restored: %+v", d2)
+			}
+			// CSV 中的时长为 MM:SS，只能还原到秒
+			wantDuration := int64(90500)
+			if tt.format == ExportFormatCSV {
+				wantDuration = 90000
+			}
+			if d2.Duration != wantDuration || !d2.UpdatedAt.Equal(base.Add(time.Minute)) {
+				t.Errorf("d2: unexpected duration or updatedAt %+v", d2)
+			}
+		})
+	}
+}
+
+func TestImportBrowseRoundTrip(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, format := range []ExportFormat{ExportFormatJSON, ExportFormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			setupServiceDB(t)
+			repo := database.NewBrowseHistoryRepository()
+			record := &database.BrowseRecord{
+				ID: "b1", Title: "标题, 带逗号", Author: "作者", AuthorID: "u1", Duration: 61000, Size: 2048,
+				CoverURL: "https://cover/1", BrowseTime: base, LikeCount: 7, CreatedAt: base, UpdatedAt: base,
+			}
+			if err := repo.Import(record); err != nil {
+				t.Fatal(err)
+			}
+			exported, err := NewExportService().ExportBrowseHistory(format, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := repo.Clear(); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := NewImportService().ImportBrowseHistory(exported.Data, ImportOptions{})
+			if err != nil || report.Created != 1 || report.Failed != 0 {
+				t.Fatalf("unexpected report %+v %v", report, err)
+			}
+			got, _ := repo.GetByID("b1")
+			if got == nil || got.Title != record.Title || got.AuthorID != "u1" || got.Duration != 61000 ||
+				got.Size != 2048 || got.LikeCount != 7 || !got.BrowseTime.Equal(base) || !got.UpdatedAt.Equal(base) {
+				t.Errorf("unexpected imported record %+v", got)
+			}
+		})
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	setupServiceDB(t)
+	data := "ID,VideoID,Title,Status\nd1,v1,视频1,completed\nd2,v2,视频2,failed\n"
+	report, err := NewImportService().ImportDownloadRecords([]byte(data), ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || report.Format != ExportFormatCSV || report.Strategy != ImportStrategySkip || report.Created != 2 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if count, _ := database.NewDownloadRecordRepository().Count(); count != 0 {
+		t.Errorf("dry run should not write records, got %d", count)
+	}
+}
+
+func TestImportRowErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		created int
+		errRows []int
+	}{
+		{
+			name: "csv",
+			data: "ID,VideoID,Status,Duration,FileSize,UpdatedAt\n" +
+				"d1,v1,completed,01:30,1.50 MB,\n" +
+				",v2,completed,,,\n" +
+				"d3,,completed,,,\n" +
+				"d4,v4,unknown,,,\n" +
+				"d5,v5,,1:xx,,\n" +
+				"d6,v6,,,1.5 QB,\n" +
+				"d7,v7,,,,yesterday\n",
+			created: 1,
+			errRows: []int{2, 3, 4, 5, 6, 7},
+		},
+		{
+			name:    "json",
+			data:    `[{"id":"d1","videoId":"v1"},{"id":2},{"videoId":"v3"},{"id":"d4","videoId":"v4","status":"done"}]`,
+			created: 1,
+			errRows: []int{2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupServiceDB(t)
+			report, err := NewImportService().ImportDownloadRecords([]byte(tt.data), ImportOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Created != tt.created || report.Failed != len(tt.errRows) || len(report.Errors) != len(tt.errRows) {
+				t.Fatalf("unexpected report %+v", report)
+			}
+			for i, row := range tt.errRows {
+				if report.Errors[i].Row != row || report.Errors[i].Error == "" {
+					t.Errorf("expected error on row %d, got %+v", row, report.Errors[i])
+				}
+			}
+		})
+	}
+
+	// 无法识别的 JSON 或缺少 ID 列时整体失败
+	if _, err := NewImportService().ImportDownloadRecords([]byte("[{"), ImportOptions{}); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+	if _, err := NewImportService().ImportDownloadRecords([]byte("Title\nx\n"), ImportOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "ID") {
+		t.Errorf("expected missing ID column error, got %v", err)
+	}
+}
+
+func TestCSVRecordParsing(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(*csvRecord) int64
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"duration mm:ss", func(c *csvRecord) int64 { return c.duration("v") }, "01:30", 90000, false},
+		{"duration long", func(c *csvRecord) int64 { return c.duration("v") }, "125:05", 7505000, false},
+		{"duration ms", func(c *csvRecord) int64 { return c.duration("v") }, "90500", 90500, false},
+		{"duration empty", func(c *csvRecord) int64 { return c.duration("v") }, "", 0, false},
+		{"duration invalid", func(c *csvRecord) int64 { return c.duration("v") }, "1:xx", 0, true},
+		{"size MB", func(c *csvRecord) int64 { return c.fileSize("v") }, "1.50 MB", 1572864, false},
+		{"size KB", func(c *csvRecord) int64 { return c.fileSize("v") }, "2.00 KB", 2048, false},
+		{"size GB", func(c *csvRecord) int64 { return c.fileSize("v") }, "1.25 GB", 1342177280, false},
+		{"size B", func(c *csvRecord) int64 { return c.fileSize("v") }, "512 B", 512, false},
+		{"size bytes", func(c *csvRecord) int64 { return c.fileSize("v") }, "1048576", 1048576, false},
+		{"size unknown unit", func(c *csvRecord) int64 { return c.fileSize("v") }, "1.5 QB", 0, true},
+		{"size long unit", func(c *csvRecord) int64 { return c.fileSize("v") }, "1.5 MiB", 0, true},
+		{"size invalid number", func(c *csvRecord) int64 { return c.fileSize("v") }, "x MB", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &csvRecord{fields: []string{tt.value}, index: map[string]int{"v": 0}}
+			got := tt.parse(c)
+			if (c.err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", c.err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	// formatFileSize 的输出可以还原
+	for _, size := range []int64{0, 1023, 1572864, 5 << 30} {
+		c := &csvRecord{fields: []string{formatFileSize(size)}, index: map[string]int{"v": 0}}
+		if got := c.fileSize("v"); got != size || c.err != nil {
+			t.Errorf("formatFileSize(%d) round trip got %d %v", size, got, c.err)
+		}
+	}
+}
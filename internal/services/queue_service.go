@@ -32,12 +32,18 @@ type VideoInfo struct {
 	VideoID    string `json:"videoId"`
 	Title      string `json:"title"`
 	Author     string `json:"author"`
+	AuthorID   string `json:"authorId"`
 	CoverURL   string `json:"coverUrl"`
 	VideoURL   string `json:"videoUrl"`
 	DecryptKey string `json:"decryptKey"`
 	Duration   int64  `json:"duration"`
 	Resolution string `json:"resolution"`
 	Size       int64  `json:"size"`
+
+	// 以下由雷达按监控目标的分组设置，不接受客户端传入
+	Group         string `json:"-"` // 来源分组
+	SubDir        string `json:"-"` // 下载子目录（以 / 分隔），为空时按作者分目录
+	GroupPriority int    `json:"-"` // 分组优先级，越大越先下载
 }
 
 // AddToQueue 将视频添加到下载队列
@@ -74,6 +80,7 @@ func (s *QueueService) AddToQueue(videos []VideoInfo) ([]database.QueueItem, err
 			VideoID:         video.VideoID,
 			Title:           video.Title,
 			Author:          video.Author,
+			AuthorID:        video.AuthorID,
 			CoverURL:        video.CoverURL,
 			VideoURL:        video.VideoURL,
 			DecryptKey:      video.DecryptKey,
@@ -83,6 +90,9 @@ func (s *QueueService) AddToQueue(videos []VideoInfo) ([]database.QueueItem, err
 			DownloadedSize:  0,
 			Status:          database.QueueStatusPending,
 			Priority:        maxPriority + len(videos) - i, // 较早的项目优先级更高
+			GroupPriority:   video.GroupPriority,
+			Group:           video.Group,
+			SubDir:          video.SubDir,
 			AddedTime:       now,
 			Speed:           0,
 			ChunkSize:       chunkSize,
@@ -178,6 +188,11 @@ func (s *QueueService) GetQueue() ([]database.QueueItem, error) {
 	return s.repo.List()
 }
 
+// ListPage 按队列顺序分页返回队列项目，status、group 为空时返回全部状态和分组
+func (s *QueueService) ListPage(params *database.PaginationParams, status, group string) (*database.PagedResult[database.QueueItem], error) {
+	return s.repo.ListPage(params, status, group)
+}
+
 // GetByID 按 ID 返回队列项目
 func (s *QueueService) GetByID(id string) (*database.QueueItem, error) {
 
@@ -249,8 +264,8 @@ func (s *QueueService) CompleteDownload(id string) error {
 	}
 
 	// 根据批量下载约定计算文件路径
-	// 路径格式: {baseDir}/downloads/{authorFolder}/{cleanFilename}.mp4
-	filePath := calculateDownloadFilePath(item.Author, item.Title)
+	// 路径格式: {baseDir}/downloads/{authorFolder 或 subDir}/{cleanFilename}.mp4
+	filePath := calculateDownloadFilePath(item.SubDir, item.Author, item.Title)
 
 	downloadRepo := database.NewDownloadRecordRepository()
 
@@ -277,6 +292,7 @@ func (s *QueueService) CompleteDownload(id string) error {
 		VideoID:      item.VideoID,
 		Title:        item.Title,
 		Author:       item.Author,
+		AuthorID:     item.AuthorID,
 		CoverURL:     item.CoverURL,
 		Duration:     item.Duration,
 		FileSize:     item.TotalSize,
@@ -285,6 +301,18 @@ func (s *QueueService) CompleteDownload(id string) error {
 		Resolution:   item.Resolution, // 使用队列项目中的分辨率
 		Status:       database.DownloadStatusCompleted,
 		DownloadTime: time.Now(),
+		Group:        item.Group,
+	}
+
+	// 队列项目不含互动数据，从 videos 表补全
+	if video, _ := database.NewVideoRepository().GetByID(item.VideoID); video != nil {
+		downloadRecord.LikeCount = video.LikeCount
+		downloadRecord.CommentCount = video.CommentCount
+		downloadRecord.FavCount = video.FavCount
+		downloadRecord.ForwardCount = video.ForwardCount
+		if downloadRecord.CoverURL == "" {
+			downloadRecord.CoverURL = video.CoverURL
+		}
 	}
 
 	if err := downloadRepo.Create(downloadRecord); err != nil {
@@ -295,31 +323,32 @@ func (s *QueueService) CompleteDownload(id string) error {
 	return nil
 }
 
-// calculateDownloadFilePath 计算下载视频的预期文件路径
-func calculateDownloadFilePath(author, title string) string {
-	// 从当前配置获取下载目录
-	cfg := config.Get()
-	var downloadsDir string
-	var err error
-
-	if cfg != nil {
-		downloadsDir, err = cfg.GetResolvedDownloadsDir()
-	}
-
-	if err != nil || downloadsDir == "" {
-		// 回退到软件基础目录 + downloads
-		baseDir, baseErr := utils.GetBaseDir()
-		if baseErr != nil {
-			baseDir = "."
+// resolveDownloadsDir 获取当前下载目录，未配置时回退到程序目录下的 downloads
+func resolveDownloadsDir() string {
+	if cfg := config.Get(); cfg != nil {
+		if dir, err := cfg.GetResolvedDownloadsDir(); err == nil && dir != "" {
+			return dir
 		}
-		downloadsDir = filepath.Join(baseDir, "downloads")
 	}
+	baseDir, err := utils.GetBaseDir()
+	if err != nil {
+		baseDir = "."
+	}
+	return filepath.Join(baseDir, "downloads")
+}
+
+// calculateDownloadFilePath 计算下载视频的预期文件路径，subDir 为空时按作者分目录
+func calculateDownloadFilePath(subDir, author, title string) string {
+	downloadsDir := resolveDownloadsDir()
 
 	// 清理作者名作为文件夹名
 	authorFolder := cleanFolderName(author)
 	if authorFolder == "" {
 		authorFolder = "未知作者"
 	}
+	if subDir != "" {
+		authorFolder = filepath.FromSlash(subDir)
+	}
 
 	// 清理标题作为文件名
 	cleanTitle := cleanFilename(title)
@@ -333,7 +362,7 @@ func calculateDownloadFilePath(author, title string) string {
 	}
 
 	// 使用正确的下载目录返回绝对路径
-	// 路径格式: {downloadsDir}/{author}/{title}.mp4
+	// 路径格式: {downloadsDir}/{author 或 subDir}/{title}.mp4
 	return filepath.Join(downloadsDir, authorFolder, cleanTitle)
 }
 
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"wx_channel/internal/database"
+	"wx_channel/internal/utils"
+)
+
+// saveAccountSnapshot 记录本次检测时账号的资料快照：粉丝数、作品数、昵称和头像（返回数据中有时），
+// 以及按已保存视频统计的发布频率
+func (s *RadarService) saveAccountSnapshot(target database.RadarTarget, page *feedPage) {
+	snapshot := &database.RadarAccountSnapshot{
+		TargetID:   target.ID,
+		Username:   target.Username,
+		Nickname:   target.AuthorName,
+		FansCount:  page.fansCount,
+		FeedsCount: page.feedsCount,
+	}
+	// 资料取返回数据中的 contact，没有时取第一条视频附带的 contact
+	contact := page.contact
+	if contact == nil && len(page.objects) > 0 {
+		if objMap, ok := page.objects[0].(map[string]interface{}); ok {
+			contact, _ = objMap["contact"].(map[string]interface{})
+		}
+	}
+	if nickname, _ := contact["nickname"].(string); nickname != "" {
+		snapshot.Nickname = nickname
+	}
+	snapshot.AvatarURL, _ = contact["headUrl"].(string)
+
+	if err := s.repo.AddAccountSnapshot(snapshot); err != nil {
+		utils.LogWarn("[Radar] 保存账号快照失败 [%s]: %v", target.AuthorName, err)
+	}
+}
+
+// jsonCount 返回第一个存在的 JSON 数字，都不存在时返回 nil
+func jsonCount(values ...interface{}) *int64 {
+	for _, v := range values {
+		if v != nil {
+			n := jsonInt64(v)
+			return &n
+		}
+	}
+	return nil
+}
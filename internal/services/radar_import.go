@@ -0,0 +1,316 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"wx_channel/internal/database"
+	"wx_channel/internal/websocket"
+)
+
+// radarResolveInterval 通过搜索解析昵称时两次请求的最小间隔
+const radarResolveInterval = time.Second
+
+// radarImportMaxResolve 一次导入中最多通过搜索解析的昵称数量，超出的行报错，需提供 username 或分批导入
+const radarImportMaxResolve = 20
+
+// radarImportDefaultInterval 导入时未指定检测间隔、分组也没有默认间隔时使用的值（分钟）
+const radarImportDefaultInterval = 60
+
+// RadarImportService 批量导入导出雷达监控目标
+type RadarImportService struct {
+	repo   *database.RadarRepository
+	hub    *websocket.Hub
+	budget *RadarService // 昵称搜索与雷达轮询共用每小时请求额度
+
+	mu         sync.Mutex
+	lastSearch time.Time // 受 mu 保护，多个导入请求共用搜索间隔
+}
+
+// NewRadarImportService 创建一个新的 RadarImportService，hub 用于把昵称解析为 username，
+// 解析时的搜索请求计入 budget 的每小时请求额度
+func NewRadarImportService(hub *websocket.Hub, budget *RadarService) *RadarImportService {
+	return &RadarImportService{
+		repo:   database.NewRadarRepository(),
+		hub:    hub,
+		budget: budget,
+	}
+}
+
+// radarTargetTable 监控目标的导出列，只支持与导入一致的 JSON 和 CSV
+var radarTargetTable = exportTable[database.RadarTarget]{
+	Name:  "radar_targets",
+	Title: "雷达监控目标",
+	CSVHeader: []string{
+		"Type", "Username", "Name", "Keyword", "Interval", "Schedule", "Group", "Action", "Status", "Filter",
+		"UpdatedAt",
+	},
+	CSVRow: func(t *database.RadarTarget) []string {
+		filter := ""
+		if !t.Filter.IsEmpty() {
+			if data, err := json.Marshal(t.Filter); err == nil {
+				filter = string(data)
+			}
+		}
+		return []string{
+			t.Type, t.Username, t.AuthorName, t.Keyword, strconv.Itoa(t.IntervalMinutes), t.Schedule,
+			t.Group, t.Action, string(t.Status), filter, t.UpdatedAt.Format(time.RFC3339),
+		}
+	},
+}
+
+// ExportTargets 导出监控目标，group 非空时只导出该分组，返回导出的数量
+func (s *RadarImportService) ExportTargets(w io.Writer, format ExportFormat, group string) (int, error) {
+	if format != ExportFormatJSON && format != ExportFormatCSV {
+		return 0, fmt.Errorf("unsupported export format: %s (expected json or csv)", format)
+	}
+	var targets []database.RadarTarget
+	var err error
+	if group != "" {
+		targets, err = s.repo.GetByGroup(group)
+	} else {
+		targets, err = s.repo.GetAll()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return streamRecords(w, format, &radarTargetTable, func(emit func(*database.RadarTarget) error) error {
+		for i := range targets {
+			if err := emit(&targets[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ImportTargets 导入监控目标：JSON 数组（与导出的字段相同）或带表头的 CSV（Type、Username、Name、Keyword、
+// Interval、Schedule、Group、Action、Status、Filter、UpdatedAt，列名不区分大小写，Filter 为 JSON）。
+// 账号目标按 username、关键词目标按关键词判断是否已存在；只给出昵称的账号通过账号搜索解析 username，
+// 每次导入最多解析 radarImportMaxResolve 个昵称，每小时请求额度用完时整体失败，不写入任何目标。
+func (s *RadarImportService) ImportTargets(data []byte, opts ImportOptions) (*ImportReport, error) {
+	report, err := newImportReport(data, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow[database.RadarTarget]
+	if opts.Format == ExportFormatJSON {
+		rows, err = parseJSONRows[database.RadarTarget](data)
+	} else {
+		rows, err = parseCSVRows(data, "", radarTargetFromCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 先准备所有行（包括搜索昵称），再写入
+	resolved := make(map[string]resolvedName)
+	targets := make([]database.RadarTarget, len(rows))
+	keys := make([]string, len(rows))
+	for i := range rows {
+		targets[i] = rows[i].Record
+		keys[i] = targets[i].Username
+		if rows[i].Err == nil {
+			rows[i].Err = s.prepareImportedTarget(&targets[i], resolved)
+			keys[i] = radarImportKey(&targets[i])
+			if IsRadarBudgetExhausted(rows[i].Err) {
+				return nil, rows[i].Err
+			}
+		}
+	}
+
+	seen := make(map[string]time.Time)
+	for i, row := range rows {
+		target, key := targets[i], keys[i]
+		if row.Err != nil {
+			report.fail(row.Row, key, row.Err)
+			continue
+		}
+
+		existing, ok := seen[key]
+		var current *database.RadarTarget
+		if !ok {
+			if current, err = s.findTarget(&target); err != nil {
+				report.fail(row.Row, key, err)
+				continue
+			}
+			if current != nil {
+				existing, ok = current.UpdatedAt, true
+			}
+		}
+		report.apply(row.Row, key, ok, existing, target.UpdatedAt, seen, func() error {
+			if current == nil && ok {
+				// 文件内重复的目标，覆盖前一行写入的记录
+				var err error
+				if current, err = s.findTarget(&target); err != nil {
+					return err
+				}
+			}
+			if current == nil {
+				return s.repo.Add(&target)
+			}
+			// 覆盖配置，保留检测进度和健康状态
+			target.ID = current.ID
+			target.LastCheckTime = current.LastCheckTime
+			return s.repo.Update(&target)
+		})
+	}
+	return report, nil
+}
+
+// findTarget 查找与导入目标相同的已有目标，不存在时返回 nil
+func (s *RadarImportService) findTarget(target *database.RadarTarget) (*database.RadarTarget, error) {
+	if target.IsKeyword() {
+		return s.repo.GetByKeyword(target.Keyword)
+	}
+	return s.repo.GetByUsername(target.Username)
+}
+
+// prepareImportedTarget 清除导入内容中的运行状态，解析昵称并校验配置
+func (s *RadarImportService) prepareImportedTarget(target *database.RadarTarget, resolved map[string]resolvedName) error {
+	*target = database.RadarTarget{
+		Type:            target.Type,
+		Username:        strings.TrimSpace(target.Username),
+		AuthorName:      strings.TrimSpace(target.AuthorName),
+		Keyword:         target.Keyword,
+		Action:          target.Action,
+		Group:           target.Group,
+		IntervalMinutes: target.IntervalMinutes,
+		Schedule:        target.Schedule,
+		Status:          target.Status,
+		Filter:          target.Filter,
+		UpdatedAt:       target.UpdatedAt,
+	}
+	if name := strings.TrimSpace(target.Group); name != "" {
+		if group, err := s.repo.GetGroup(name); err == nil {
+			group.ApplyDefaults(target)
+		}
+	}
+	if target.IntervalMinutes == 0 {
+		target.IntervalMinutes = radarImportDefaultInterval
+	}
+	if target.Type == "" || target.Type == database.RadarTargetAccount {
+		if target.Username == "" && target.AuthorName != "" {
+			result, ok := resolved[target.AuthorName]
+			if !ok {
+				if len(resolved) >= radarImportMaxResolve {
+					return fmt.Errorf("一次导入最多通过昵称查找 %d 个账号，请提供 username 或分批导入", radarImportMaxResolve)
+				}
+				result.username, result.err = s.resolveUsername(target.AuthorName)
+				resolved[target.AuthorName] = result
+			}
+			if result.err != nil {
+				return result.err
+			}
+			target.Username = result.username
+		}
+		if target.AuthorName == "" {
+			target.AuthorName = target.Username
+		}
+	}
+	return target.Normalize()
+}
+
+// resolvedName 记录一次导入中昵称的解析结果，失败的昵称不再重复搜索
+type resolvedName struct {
+	username string
+	err      error
+}
+
+// radarImportKey 返回导入时判断重复的键，同时用于错误报告
+func radarImportKey(target *database.RadarTarget) string {
+	if target.IsKeyword() {
+		return "keyword:" + target.Keyword
+	}
+	return target.Username
+}
+
+// resolveUsername 通过账号搜索（contact_list，type 1）把昵称解析为 username，只接受昵称完全一致且唯一的结果
+func (s *RadarImportService) resolveUsername(name string) (string, error) {
+	if s.hub == nil || s.hub.ClientCount() == 0 {
+		return "", errors.New("微信客户端未连接，无法通过昵称查找账号，请提供 username")
+	}
+	if s.budget != nil && !s.budget.takeCall(time.Now()) {
+		return "", errRadarBudgetExhausted
+	}
+	// 在锁内预约下一次搜索的时间，并发的导入依次间隔 radarResolveInterval
+	s.mu.Lock()
+	now := time.Now()
+	next := s.lastSearch.Add(radarResolveInterval)
+	if next.Before(now) {
+		next = now
+	}
+	s.lastSearch = next
+	s.mu.Unlock()
+	time.Sleep(next.Sub(now))
+
+	body := websocket.SearchContactBody{Keyword: name, Type: 1}
+	data, err := s.hub.CallAPI("key:channels:contact_list", body, 60*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("搜索账号 %s 失败: %w", name, err)
+	}
+	var resp struct {
+		Data struct {
+			InfoList []struct {
+				Contact struct {
+					Username string `json:"username"`
+					Nickname string `json:"nickname"`
+				} `json:"contact"`
+			} `json:"infoList"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", fmt.Errorf("解析账号搜索结果失败: %w", err)
+	}
+
+	var matches []string
+	for _, info := range resp.Data.InfoList {
+		if info.Contact.Username != "" && strings.TrimSpace(info.Contact.Nickname) == name {
+			matches = append(matches, info.Contact.Username)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("未找到昵称为 %s 的账号", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("找到 %d 个昵称为 %s 的账号，请提供 username", len(matches), name)
+	}
+}
+
+// radarTargetFromCSV 按 radarTargetTable 的 CSV 列读取监控目标，Name 也可以写作 AuthorName，Interval 也可以写作 IntervalMinutes
+func radarTargetFromCSV(c *csvRecord) database.RadarTarget {
+	target := database.RadarTarget{
+		Type:            c.str("Type"),
+		Username:        c.str("Username"),
+		AuthorName:      c.str("Name"),
+		Keyword:         c.str("Keyword"),
+		IntervalMinutes: int(c.int64("Interval")),
+		Schedule:        c.str("Schedule"),
+		Group:           c.str("Group"),
+		Action:          c.str("Action"),
+		Status:          database.RadarTargetStatus(c.str("Status")),
+		UpdatedAt:       c.time("UpdatedAt"),
+	}
+	if target.AuthorName == "" {
+		target.AuthorName = c.str("AuthorName")
+	}
+	if target.IntervalMinutes == 0 {
+		target.IntervalMinutes = int(c.int64("IntervalMinutes"))
+	}
+	if rules := c.str("Filter"); rules != "" {
+		target.Filter = &database.RadarFilter{}
+		if err := json.Unmarshal([]byte(rules), target.Filter); err != nil && c.err == nil {
+			c.err = fmt.Errorf("invalid Filter: %w", err)
+		}
+	}
+	return target
+}
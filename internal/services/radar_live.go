@@ -0,0 +1,311 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"wx_channel/internal/config"
+	"wx_channel/internal/database"
+	"wx_channel/internal/utils"
+	"wx_channel/internal/websocket"
+)
+
+// 直播事件类型
+const (
+	RadarLiveStart = "start" // 检测到开播
+	RadarLiveEnd   = "end"   // 检测到下播
+)
+
+// RadarLiveEvent 表示监控账号开播或下播
+type RadarLiveEvent struct {
+	Action  string                     `json:"action"` // start 或 end
+	Session *database.RadarLiveSession `json:"session"`
+}
+
+// OnLiveEvent 注册直播事件回调，回调在轮询协程中同步执行，不应阻塞
+func (s *RadarService) OnLiveEvent(fn func(RadarLiveEvent)) {
+	s.mu.Lock()
+	s.liveHandlers = append(s.liveHandlers, fn)
+	s.mu.Unlock()
+}
+
+// checkLive 按本次检测拿到的直播状态更新账号的直播场次，开播时交给录制命令，并发出开播和下播事件。
+// 视频列表没有带直播状态时，按配置 radar_live_search 搜索直播确认（计入每小时请求额度）。
+func (s *RadarService) checkLive(target database.RadarTarget, page *feedPage) {
+	live, known := page.live, page.liveKnown
+	if !known {
+		cfg := config.Get()
+		if cfg == nil || !cfg.RadarLiveSearch || target.AuthorName == "" || !s.takeCall(time.Now()) {
+			return
+		}
+		if live, known = s.searchLive(target); !known {
+			return
+		}
+	}
+
+	started, ended, err := s.repo.UpdateLiveStatus(&target, live, time.Now())
+	if err != nil {
+		utils.LogError("[Radar] 更新直播状态失败 [%s]: %v", target.AuthorName, err)
+		return
+	}
+	if ended != nil {
+		utils.LogInfo("[Radar] 账号 [%s] 已下播", target.AuthorName)
+		s.stopRecorder(ended.ID)
+		s.emitLive(RadarLiveEvent{Action: RadarLiveEnd, Session: ended})
+	}
+	if started != nil {
+		utils.LogInfo("[Radar] 账号 [%s] 开播了: %s", target.AuthorName, started.Description)
+		s.startRecorder(started)
+		s.emitLive(RadarLiveEvent{Action: RadarLiveStart, Session: started})
+	}
+}
+
+// searchLive 按昵称搜索直播（contact_list，type 2），在结果中查找目标账号，known 为 false 表示请求失败
+func (s *RadarService) searchLive(target database.RadarTarget) (*database.RadarLiveSession, bool) {
+	body := websocket.SearchContactBody{Keyword: target.AuthorName, Type: 2}
+	data, err := s.hub.CallAPI("key:channels:contact_list", body, 60*time.Second)
+	if err != nil {
+		utils.LogWarn("[Radar] 搜索直播失败 [%s]: %v", target.AuthorName, err)
+		return nil, false
+	}
+
+	var rawResp struct {
+		Data struct {
+			BaseResponse struct {
+				Ret int `json:"Ret"`
+			} `json:"BaseResponse"`
+			ObjectList []interface{} `json:"objectList"`
+			InfoList   []interface{} `json:"infoList"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &rawResp); err != nil || rawResp.Data.BaseResponse.Ret != 0 {
+		utils.LogWarn("[Radar] 搜索直播失败 [%s]: 返回数据无效", target.AuthorName)
+		return nil, false
+	}
+
+	for _, list := range [][]interface{}{rawResp.Data.ObjectList, rawResp.Data.InfoList} {
+		for _, entryInter := range list {
+			entry, ok := entryInter.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if username, _ := objectAuthor(entry); username != target.Username {
+				continue
+			}
+			live, known := liveFromEntry(entry)
+			if known && live == nil {
+				return nil, true
+			}
+			if live == nil {
+				// 直播搜索结果中出现即为正在直播
+				live = &database.RadarLiveSession{}
+			}
+			live.Source = database.RadarLiveSourceSearch
+			return live, true
+		}
+	}
+	return nil, true
+}
+
+// liveFromEntry 从带 liveStatus、liveInfo 的数据（账号资料、搜索到的账号或直播）解析直播状态，
+// 未在直播时返回 nil；known 为 false 表示数据中没有直播状态
+func liveFromEntry(entry map[string]interface{}) (*database.RadarLiveSession, bool) {
+	info, _ := entry["liveInfo"].(map[string]interface{})
+	status := entry["liveStatus"]
+	if status == nil && info != nil {
+		status = info["liveStatus"]
+	}
+	streamURL, _ := info["streamUrl"].(string)
+	if status != nil {
+		if jsonInt64(status) != 1 {
+			return nil, true
+		}
+	} else if streamURL == "" {
+		return nil, false
+	}
+
+	live := &database.RadarLiveSession{StreamURL: streamURL, LiveID: jsonString(info["liveId"])}
+	live.Description, _ = info["description"].(string)
+	if media, ok := info["media"].([]interface{}); ok && len(media) > 0 {
+		if m, ok := media[0].(map[string]interface{}); ok {
+			live.CoverURL, _ = m["thumbUrl"].(string)
+		}
+	}
+	if live.LiveID == "" {
+		live.LiveID = jsonString(entry["liveId"])
+	}
+	return live, true
+}
+
+// emitLive 通知注册的回调，并按配置 radar_live_webhook 发送通知
+func (s *RadarService) emitLive(event RadarLiveEvent) {
+	s.mu.Lock()
+	handlers := s.liveHandlers
+	s.mu.Unlock()
+	for _, fn := range handlers {
+		fn(event)
+	}
+
+	cfg := config.Get()
+	if cfg == nil || cfg.RadarLiveWebhook == "" {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	go func(url string) {
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			utils.LogWarn("[Radar] 发送直播通知失败: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			utils.LogWarn("[Radar] 发送直播通知失败: HTTP %d", resp.StatusCode)
+		}
+	}(cfg.RadarLiveWebhook)
+}
+
+// radarRecorderStopTimeout 通知录制命令退出后等待的时间，超时后强制结束
+const radarRecorderStopTimeout = 10 * time.Second
+
+// liveRecorder 是一场直播正在运行的录制命令
+type liveRecorder struct {
+	cmd  *exec.Cmd
+	done chan struct{} // 录制命令退出后关闭
+}
+
+// startRecorder 按配置 radar_live_recorder 启动录制命令。命令按空白拆分为参数后替换占位符，不经过 shell；
+// {file} 为下载目录下作者文件夹中的“直播_开播时间”（不含扩展名）。检测到下播或服务停止时由 stopRecorder 结束。
+func (s *RadarService) startRecorder(session *database.RadarLiveSession) {
+	cfg := config.Get()
+	if cfg == nil {
+		return
+	}
+	args := strings.Fields(cfg.RadarLiveRecorder)
+	if len(args) == 0 {
+		return
+	}
+	if session.StreamURL == "" {
+		utils.LogWarn("[Radar] 账号 [%s] 的直播没有拉流地址，跳过录制", session.AuthorName)
+		return
+	}
+
+	authorFolder := cleanFolderName(session.AuthorName)
+	if authorFolder == "" {
+		authorFolder = "未知作者"
+	}
+	file := filepath.Join(resolveDownloadsDir(), authorFolder, "直播_"+session.StartTime.Format("20060102_150405"))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		utils.LogError("[Radar] 创建录制目录失败: %v", err)
+		return
+	}
+
+	replacer := strings.NewReplacer("{url}", session.StreamURL, "{file}", file, "{author}", session.AuthorName)
+	for i := range args {
+		args[i] = replacer.Replace(args[i])
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		utils.LogError("[Radar] 启动录制命令失败 [%s]: %v", session.AuthorName, err)
+		return
+	}
+	utils.LogInfo("[Radar] 开始录制 [%s] 的直播: %s", session.AuthorName, file)
+	s.watchRecorder(session.ID, session.AuthorName, cmd)
+
+	session.RecordFile = file
+	if err := s.repo.SetLiveRecordFile(session.ID, file); err != nil {
+		utils.LogWarn("[Radar] 保存录制文件路径失败 [%s]: %v", session.ID, err)
+	}
+}
+
+// watchRecorder 记录已启动的录制命令，命令退出后移除
+func (s *RadarService) watchRecorder(sessionID, author string, cmd *exec.Cmd) *liveRecorder {
+	recorder := &liveRecorder{cmd: cmd, done: make(chan struct{})}
+	s.mu.Lock()
+	if s.recorders == nil {
+		s.recorders = make(map[string]*liveRecorder)
+	}
+	s.recorders[sessionID] = recorder
+	s.mu.Unlock()
+
+	go func() {
+		err := cmd.Wait()
+		s.mu.Lock()
+		if s.recorders[sessionID] == recorder {
+			delete(s.recorders, sessionID)
+		}
+		s.mu.Unlock()
+		close(recorder.done)
+		if err != nil {
+			utils.LogWarn("[Radar] 录制命令退出 [%s]: %v", author, err)
+			return
+		}
+		utils.LogInfo("[Radar] 录制结束 [%s]", author)
+	}()
+	return recorder
+}
+
+// stopRecorder 结束直播场次的录制命令，没有在录制时不做任何事
+func (s *RadarService) stopRecorder(sessionID string) {
+	s.mu.Lock()
+	recorder := s.recorders[sessionID]
+	delete(s.recorders, sessionID)
+	s.mu.Unlock()
+	if recorder != nil {
+		recorder.stop()
+	}
+}
+
+// stopRecorders 结束所有录制命令并等待退出，服务停止时调用
+func (s *RadarService) stopRecorders() {
+	s.mu.Lock()
+	recorders := s.recorders
+	s.recorders = nil
+	s.mu.Unlock()
+	for _, recorder := range recorders {
+		recorder.stop()
+	}
+	for _, recorder := range recorders {
+		<-recorder.done
+	}
+}
+
+// stop 发送中断信号让录制命令正常收尾（如 ffmpeg 写完文件尾），不支持信号的系统上直接结束进程；
+// radarRecorderStopTimeout 内未退出时强制结束
+func (r *liveRecorder) stop() {
+	if err := r.cmd.Process.Signal(os.Interrupt); err != nil {
+		r.cmd.Process.Kill()
+		return
+	}
+	go func() {
+		select {
+		case <-r.done:
+		case <-time.After(radarRecorderStopTimeout):
+			r.cmd.Process.Kill()
+		}
+	}()
+}
+
+// jsonString 将 JSON 解码出的字符串或数字转换为字符串
+func jsonString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
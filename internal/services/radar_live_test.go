@@ -0,0 +1,59 @@
+package services
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"wx_channel/internal/utils"
+)
+
+// startTestRecorder 启动一个长时间运行的命令代替录制命令
+func startTestRecorder(t *testing.T, s *RadarService, sessionID string) *liveRecorder {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep is not available: %v", err)
+	}
+	return s.watchRecorder(sessionID, "作者", cmd)
+}
+
+func waitRecorderDone(t *testing.T, recorder *liveRecorder) {
+	t.Helper()
+	select {
+	case <-recorder.done:
+	case <-time.After(5 * time.Second):
+		recorder.cmd.Process.Kill()
+		t.Fatal("recorder did not exit")
+	}
+}
+
+func TestRadarRecorderStop(t *testing.T) {
+	utils.GetLogger() // 日志延迟初始化，先在测试协程中完成，避免录制协程并发初始化
+	s := &RadarService{}
+	first := startTestRecorder(t, s, "s1")
+	second := startTestRecorder(t, s, "s2")
+
+	// 下播只结束对应场次的录制
+	s.stopRecorder("s1")
+	waitRecorderDone(t, first)
+	s.mu.Lock()
+	_, running := s.recorders["s2"]
+	remaining := len(s.recorders)
+	s.mu.Unlock()
+	if !running || remaining != 1 {
+		t.Fatalf("expected only s2 to be recording, got %d", remaining)
+	}
+	s.stopRecorder("unknown")
+
+	// 服务停止时结束所有录制并等待退出
+	s.stopRecorders()
+	select {
+	case <-second.done:
+	default:
+		t.Fatal("stopRecorders returned before the recorder exited")
+	}
+	if len(s.recorders) != 0 {
+		t.Errorf("expected no recorders, got %d", len(s.recorders))
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"wx_channel/internal/database"
+)
+
+// 预览中视频的判断结果
+const (
+	RadarPreviewNew      = "new"      // 新视频，实际检测时会加入队列（或仅通知）
+	RadarPreviewKnown    = "known"    // 已下载、已在队列中或关键词之前搜到过
+	RadarPreviewFiltered = "filtered" // 新视频，但不符合过滤规则或无法提取地址
+)
+
+// RadarPreviewVideo 是预览中一个视频的判断结果
+type RadarPreviewVideo struct {
+	VideoID     string `json:"video_id"`
+	Title       string `json:"title"`
+	Author      string `json:"author,omitempty"`       // 关键词目标的搜索结果来自不同作者
+	PublishedAt int64  `json:"published_at,omitempty"` // 发布时间（Unix 秒）
+	Status      string `json:"status"`                 // new、known 或 filtered
+	Reason      string `json:"reason"`
+}
+
+// RadarPreview 是预览一次检测的结果
+type RadarPreview struct {
+	Videos   []RadarPreviewVideo `json:"videos"`
+	New      int                 `json:"new"`
+	Known    int                 `json:"known"`
+	Filtered int                 `json:"filtered"`
+	HasMore  bool                `json:"has_more"` // 还有下一页，预览只拉取第一页
+	Gap      bool                `json:"gap"`      // 第一页没有已知视频，实际检测时会从第二页开始补抓
+}
+
+// Preview 按当前的过滤规则和去重逻辑预览一次检测：只拉取第一页，不保存元数据、不记录关键词命中、不加入队列，
+// 也不影响目标的健康状态。请求计入每小时请求额度，额度用完时返回错误。target 可以是尚未保存的目标。
+func (s *RadarService) Preview(target database.RadarTarget) (*RadarPreview, error) {
+	if s.hub == nil || s.hub.ClientCount() == 0 {
+		return nil, errRadarNoClient
+	}
+	if breaker, err := s.settings.GetRadarBreaker(); err == nil && breaker.IsOpen(time.Now()) {
+		return nil, errors.New("请求过于频繁，雷达断路器冷却中，请稍后再试")
+	}
+
+	if !s.takeCall(time.Now()) {
+		return nil, errRadarBudgetExhausted
+	}
+	page, err := s.requestPage(target, "")
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &RadarPreview{Videos: []RadarPreviewVideo{}, HasMore: page.nextMarker != ""}
+	if !target.IsKeyword() && len(page.objects) > 0 {
+		preview.Gap = s.detectGap(target, page.objects)
+	}
+	scan := s.newRadarScan(target)
+	scan.dryRun = true
+	scan.handle(page.objects)
+
+	for _, video := range scan.preview {
+		switch video.Status {
+		case RadarPreviewNew:
+			preview.New++
+		case RadarPreviewKnown:
+			preview.Known++
+		case RadarPreviewFiltered:
+			preview.Filtered++
+		}
+	}
+	if len(scan.preview) > 0 {
+		preview.Videos = scan.preview
+	}
+	return preview, nil
+}
+
+// previewVideo 根据视频摘要和去重结果生成预览中的判断结果
+func previewVideo(summary *database.RadarVideoSummary, knownReason string, notifyOnly bool, publishedAt int64) RadarPreviewVideo {
+	video := RadarPreviewVideo{
+		VideoID:     summary.VideoID,
+		Title:       summary.Title,
+		Author:      summary.Author,
+		PublishedAt: publishedAt,
+	}
+	switch {
+	case knownReason != "":
+		video.Status, video.Reason = RadarPreviewKnown, knownReason
+	case summary.Skipped:
+		video.Status, video.Reason = RadarPreviewFiltered, summary.SkipReason
+	case notifyOnly:
+		video.Status, video.Reason = RadarPreviewNew, "仅通知，不下载"
+	default:
+		video.Status, video.Reason = RadarPreviewNew, "将加入下载队列"
+	}
+	return video
+}
@@ -3,11 +3,16 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"wx_channel/internal/config"
 	"wx_channel/internal/database"
 	"wx_channel/internal/utils"
 	"wx_channel/internal/websocket"
@@ -27,6 +32,13 @@ type RadarService struct {
 	wg     sync.WaitGroup
 
 	ticker *time.Ticker
+
+	liveHandlers []func(RadarLiveEvent)   // 直播事件回调，见 OnLiveEvent
+	calls        []time.Time              // 最近一小时内 feed_list 的调用时间，轮询和预览共用，受 mu 保护
+	recorders    map[string]*liveRecorder // 直播场次 ID 到正在运行的录制命令，受 mu 保护
+
+	// 以下字段仅在轮询协程中访问
+	failedTargets map[string]bool // 上次成功请求以来失败的目标
 }
 
 // NewRadarService 创建一个新的雷达服务
@@ -99,9 +111,10 @@ func (s *RadarService) Stop() {
 		ticker.Stop()
 	}
 	s.wg.Wait()
+	s.stopRecorders()
 }
 
-// checkTargets 遍历并检查所有活动的雷达目标
+// checkTargets 遍历并检查所有到期的雷达目标
 func (s *RadarService) checkTargets() {
 	targets, err := s.repo.GetActive()
 	if err != nil {
@@ -114,20 +127,39 @@ func (s *RadarService) checkTargets() {
 	}
 
 	now := time.Now()
+	jitter, quiet := radarScheduleConfig()
+	if quiet != nil && quiet.Contains(now) {
+		return // 免打扰时段不发起请求，到期的目标在时段结束后检测
+	}
 	hasClient := s.hub.ClientCount() > 0
 
+	// 断路器冷却期间暂停所有请求；冷却结束后先试探一个目标，成功才恢复
+	breaker, err := s.settings.GetRadarBreaker()
+	if err != nil {
+		utils.LogWarn("[Radar] 读取断路器状态失败: %v", err)
+		breaker = &database.RadarBreaker{}
+	}
+	if breaker.IsOpen(now) {
+		return
+	}
+	probing := breaker.Trips > 0
+
+	// 到期的目标按计划时间排序，等待最久的优先，请求额度不足时剩下的留到下一轮
+	var due []database.RadarTarget
 	for _, target := range targets {
-		// 检查是否到了该检测的时间
-		if target.LastCheckTime != nil {
-			elapsed := now.Sub(*target.LastCheckTime)
-			if elapsed < time.Duration(target.IntervalMinutes)*time.Minute {
-				continue // 还没到时间
-			}
+		if target.NextRunTime == nil || !target.NextRunTime.After(now) {
+			due = append(due, target)
 		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextRunTime.Before(*due[j].NextRunTime)
+	})
 
+	for _, target := range due {
 		if !hasClient {
 			// 更新最后检测时间
 			_ = s.repo.UpdateLastCheckTime(target.ID, now)
+			_ = s.repo.UpdateNextRunTime(target.ID, nextRunAfter(target, now, jitter))
 			// 插入错误日志
 			_ = s.repo.AddLog(&database.RadarLog{
 				TargetID:     target.ID,
@@ -138,14 +170,187 @@ func (s *RadarService) checkTargets() {
 			continue // 跳过实际检测
 		}
 
+		if s.callBudget(time.Now()) == 0 {
+			utils.LogWarn("[Radar] 已达到每小时请求上限，%d 个到期目标顺延到下一轮", len(due))
+			break
+		}
+		due = due[1:]
+
 		// 执行检测
-		s.processTarget(target)
+		err := s.processTarget(target)
+		s.recordHealth(&target, err, jitter)
+		if s.updateBreaker(breaker, target.ID, err) {
+			return
+		}
+		if probing && breaker.Trips > 0 {
+			return // 试探请求没有成功（如客户端断开），下一轮再试
+		}
+	}
+
+	if !hasClient || breaker.Trips > 0 {
+		return
+	}
+	// 继续未完成的回填（包括本轮检测发现缺口后发起的补抓）
+	targets, err = s.repo.GetActive()
+	if err != nil {
+		utils.LogError("获取活动雷达目标失败: %v", err)
+		return
+	}
+	for _, target := range targets {
+		if target.Backfill != nil && target.Backfill.Status == database.RadarBackfillPending && s.ctx.Err() == nil && breaker.Trips == 0 {
+			s.runBackfill(target, len(targets), breaker)
+		}
+	}
+}
+
+// errRadarNoClient 表示没有可用的微信客户端，不计入目标的失败次数
+var errRadarNoClient = errors.New("微信客户端未连接或已退出")
+
+// recordHealth 按检测结果更新目标的健康状态并计划下次检测，失败时按指数退避延后
+func (s *RadarService) recordHealth(target *database.RadarTarget, err error, jitter time.Duration) {
+	now := time.Now()
+	switch {
+	case err == nil:
+		if target.Health != database.RadarHealthHealthy {
+			utils.LogInfo("[Radar] 账号 [%s] 已恢复正常", target.AuthorName)
+		}
+		target.RecordSuccess()
+	case errors.Is(err, errRadarNoClient):
+		// 客户端问题与目标无关，按正常间隔重试
+	default:
+		target.RecordFailure(err.Error(), now)
+		utils.LogWarn("[Radar] 账号 [%s] 连续失败 %d 次，状态 %s", target.AuthorName, target.ConsecutiveFailures, target.Health)
+	}
+	if err := s.repo.UpdateHealth(target); err != nil {
+		utils.LogError("[Radar] 保存健康状态失败 [%s]: %v", target.ID, err)
 	}
+	_ = s.repo.UpdateNextRunTime(target.ID, nextRunAfter(*target, now, jitter))
+}
+
+// updateBreaker 按一次 feed_list 请求的结果更新全局断路器，返回断路器是否因此打开。
+// 自上次成功以来接连失败的目标数达到 radar_breaker_threshold 时打开，试探失败时重新打开并加倍冷却时间。
+func (s *RadarService) updateBreaker(breaker *database.RadarBreaker, targetID string, err error) bool {
+	if errors.Is(err, errRadarNoClient) {
+		return false
+	}
+	if err == nil {
+		s.failedTargets = nil
+		if breaker.Trips > 0 {
+			breaker.Close()
+			_ = s.settings.SetRadarBreaker(breaker)
+			utils.LogInfo("[Radar] 试探请求成功，断路器已关闭")
+		}
+		return false
+	}
+
+	threshold, cooldown := 0, 30*time.Minute
+	if cfg := config.Get(); cfg != nil {
+		threshold, cooldown = cfg.RadarBreakerThreshold, cfg.RadarBreakerCooldown
+	}
+	if threshold <= 0 {
+		return false
+	}
+	if s.failedTargets == nil {
+		s.failedTargets = make(map[string]bool)
+	}
+	s.failedTargets[targetID] = true
+	if len(s.failedTargets) < threshold && breaker.Trips == 0 {
+		return false
+	}
+
+	reason := fmt.Sprintf("%d 个目标接连失败: %v", len(s.failedTargets), err)
+	if breaker.Trips > 0 {
+		reason = "试探请求失败: " + err.Error()
+	}
+	breaker.Open(time.Now(), cooldown, reason)
+	s.failedTargets = nil
+	if err := s.settings.SetRadarBreaker(breaker); err != nil {
+		utils.LogError("[Radar] 保存断路器状态失败: %v", err)
+	}
+	utils.LogWarn("[Radar] 断路器打开，暂停所有请求至 %s（%s）", breaker.OpenUntil.Format("15:04:05"), reason)
+	return true
+}
+
+// radarScheduleConfig 读取当前的随机延迟和免打扰时段配置
+func radarScheduleConfig() (time.Duration, *utils.TimeWindow) {
+	cfg := config.Get()
+	if cfg == nil {
+		return 0, nil
+	}
+	var quiet *utils.TimeWindow
+	if cfg.RadarQuietHours != "" {
+		quiet, _ = utils.ParseTimeWindow(cfg.RadarQuietHours)
+	}
+	return cfg.RadarJitter, quiet
+}
+
+// nextRunAfter 计算检测之后的下次检测时间（连续失败时按退避时间），附加随机延迟使各目标的请求错开
+func nextRunAfter(target database.RadarTarget, t time.Time, jitter time.Duration) time.Time {
+	next := target.ScheduledAfter(t)
+	if target.ConsecutiveFailures > 0 {
+		next = target.BackoffAfter(t)
+	}
+	if jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(jitter))))
+	}
+	return next
+}
+
+// errRadarBudgetExhausted 表示本小时的请求额度已用完
+var errRadarBudgetExhausted = errors.New("已达到每小时请求上限，请稍后再试")
+
+// IsRadarBudgetExhausted 判断错误是否因每小时请求额度用完
+func IsRadarBudgetExhausted(err error) bool {
+	return errors.Is(err, errRadarBudgetExhausted)
+}
+
+// callBudget 返回最近一小时内剩余的 feed_list 调用次数，未限制（radar_hourly_budget 为 0）时返回 -1
+func (s *RadarService) callBudget(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.callBudgetLocked(now)
+}
+
+// takeCall 在额度内记录一次调用，额度已用完时返回 false
+func (s *RadarService) takeCall(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.callBudgetLocked(now) == 0 {
+		return false
+	}
+	s.calls = append(s.calls, now)
+	return true
+}
+
+func (s *RadarService) callBudgetLocked(now time.Time) int {
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(s.calls) && !s.calls[i].After(cutoff) {
+		i++
+	}
+	s.calls = s.calls[i:]
+
+	limit := 0
+	if cfg := config.Get(); cfg != nil {
+		limit = cfg.RadarHourlyBudget
+	}
+	if limit <= 0 {
+		return -1
+	}
+	if left := limit - len(s.calls); left > 0 {
+		return left
+	}
+	return 0
 }
 
 // processTarget 处理单个雷达监控目标的拉取与对比逻辑
-func (s *RadarService) processTarget(target database.RadarTarget) {
-	utils.LogInfo("[Radar] 开始检测账号: %s (%s)", target.AuthorName, target.Username)
+// 返回获取视频列表的错误，用于更新健康状态
+func (s *RadarService) processTarget(target database.RadarTarget) error {
+	if target.IsKeyword() {
+		utils.LogInfo("[Radar] 开始搜索关键词: %s", target.Keyword)
+	} else {
+		utils.LogInfo("[Radar] 开始检测账号: %s (%s)", target.AuthorName, target.Username)
+	}
 
 	// 更新最后检测时间
 	now := time.Now()
@@ -158,84 +363,400 @@ func (s *RadarService) processTarget(target database.RadarTarget) {
 		TargetID:  target.ID,
 		CheckTime: now,
 		Status:    "success",
+		Mode:      database.RadarLogScan,
+	}
+
+	// 1. 获取第一页（最新的视频或搜索结果）
+	page, err := s.fetchPage(target, "")
+	if err != nil {
+		radarLog.Status = "error"
+		radarLog.ErrorMessage = err.Error()
+		_ = s.repo.AddLog(radarLog)
+		return err
+	}
+
+	radarLog.FoundVideos = len(page.objects)
+
+	// 账号目标同时检测是否开播
+	if !target.IsKeyword() {
+		s.checkLive(target, page)
+	}
+
+	if radarLog.FoundVideos == 0 {
+		utils.LogInfo("[Radar] 账号 [%s] 暂无视频数据(Raw Data Size: %d)", target.AuthorName, page.size)
+		_ = s.repo.AddLog(radarLog)
+		if !target.IsKeyword() {
+			s.saveAccountSnapshot(target, page)
+		}
+		return nil
+	}
+
+	// 搜索结果按相关度排序且来自不同作者，不做缺口检测，也不更新作者资料
+	gap := false
+	if !target.IsKeyword() {
+		// 缺口检测需在写入本页视频之前进行
+		gap = s.detectGap(target, page.objects)
+
+		// 作者资料取第一条视频附带的 contact
+		s.saveAuthor(target, page.objects)
+	}
+
+	// 2. 逐个处理视频，新视频加入下载队列
+	scan := s.newRadarScan(target)
+	scan.handle(page.objects)
+
+	radarLog.NewVideos = scan.newVideos
+	radarLog.VideoList = scan.videoList()
+	_ = s.repo.AddLog(radarLog)
+
+	// 本页视频保存后再记录账号快照，发布频率包含本次发现的视频
+	if !target.IsKeyword() {
+		s.saveAccountSnapshot(target, page)
+	}
+
+	if scan.newVideos > 0 {
+		if target.Action == database.RadarActionNotify {
+			utils.LogInfo("[Radar] [%s] 检测完毕，发现 %d 个新视频（仅通知）", target.AuthorName, scan.newVideos)
+		} else {
+			utils.LogInfo("[Radar] [%s] 检测完毕，新增 %d 个视频并加入下载队列", target.AuthorName, scan.newVideos)
+		}
+	}
+
+	// 3. 第一页全是没见过的视频，说明两次检测之间发布的视频超过一页，从第二页开始补抓
+	if gap && page.nextMarker != "" && (target.Backfill == nil || target.Backfill.Status != database.RadarBackfillPending) {
+		utils.LogWarn("[Radar] 账号 [%s] 第一页没有已知视频，开始补抓", target.AuthorName)
+		backfill := &database.RadarBackfill{
+			Status: database.RadarBackfillPending,
+			Reason: database.RadarBackfillGap,
+			Cursor: page.nextMarker,
+			Pages:  1,
+		}
+		if err := s.repo.UpdateBackfill(target.ID, backfill); err != nil {
+			utils.LogError("[Radar] 保存回填进度失败 [%s]: %v", target.ID, err)
+		}
+	}
+	return nil
+}
+
+// detectGap 判断第一页是否出现缺口：本页没有任何已知视频，但之前见过该作者的视频
+func (s *RadarService) detectGap(target database.RadarTarget, objects []interface{}) bool {
+	videoRepo := database.NewVideoRepository()
+	for _, objInter := range objects {
+		if objMap, ok := objInter.(map[string]interface{}); ok && objMap["id"] != nil {
+			if known, err := videoRepo.Exists(fmt.Sprintf("%v", objMap["id"])); err != nil || known {
+				return false
+			}
+		}
+	}
+	// 从未见过该作者的视频（新添加的目标），不算缺口
+	videos, err := videoRepo.ListByAuthor(target.Username, 1)
+	return err == nil && len(videos) > 0
+}
+
+// runBackfill 从保存的游标继续回填，直到遇到已知视频（补抓）、达到页数上限、早于指定日期或没有更多视频。
+// 每页之后保存游标，两页之间按 radar_backfill_page_delay 间隔。
+//
+// 请求额度有限时为各目标的定时检测保留 reserve 次调用，额度不足时暂停，下一轮继续。
+func (s *RadarService) runBackfill(target database.RadarTarget, reserve int, breaker *database.RadarBreaker) {
+	backfill := target.Backfill
+	maxPages := backfill.MaxPages
+	delay := 5 * time.Second
+	if cfg := config.Get(); cfg != nil {
+		if maxPages <= 0 {
+			maxPages = cfg.RadarBackfillMaxPages
+		}
+		delay = cfg.RadarBackfillPageDelay
+	}
+	if maxPages <= 0 {
+		maxPages = 20
+	}
+
+	utils.LogInfo("[Radar] 开始回填账号: %s (%s)，已拉取 %d 页", target.AuthorName, target.Username, backfill.Pages)
+	radarLog := &database.RadarLog{
+		TargetID:  target.ID,
+		CheckTime: time.Now(),
+		Status:    "success",
+		Mode:      database.RadarLogBackfill,
+	}
+	scan := s.newRadarScan(target)
+	if backfill.Since != nil {
+		scan.since = backfill.Since.Unix()
+	}
+	startPages := backfill.Pages
+
+	// 服务停止时退出，游标已保存，重启后继续
+	for backfill.Status == database.RadarBackfillPending && s.ctx.Err() == nil {
+		if left := s.callBudget(time.Now()); left >= 0 && left <= reserve {
+			backfill.Message = "已达到每小时请求上限，稍后继续"
+			_ = s.repo.UpdateBackfill(target.ID, backfill)
+			break
+		}
+		page, err := s.fetchPage(target, backfill.Cursor)
+		s.updateBreaker(breaker, target.ID, err)
+		if err != nil {
+			// 保留游标，下一轮检测时重试
+			radarLog.Status = "error"
+			radarLog.ErrorMessage = err.Error()
+			backfill.Message = err.Error()
+			break
+		}
+		backfill.Pages++
+		radarLog.FoundVideos += len(page.objects)
+		scan.handle(page.objects)
+		backfill.Cursor = page.nextMarker
+		backfill.Message = ""
+
+		switch {
+		case page.nextMarker == "" || len(page.objects) == 0:
+			backfill.Status, backfill.Message = database.RadarBackfillDone, "已到最早的视频"
+		case backfill.Reason == database.RadarBackfillGap && scan.known > 0:
+			backfill.Status, backfill.Message = database.RadarBackfillDone, "已衔接到已知视频"
+		case scan.since > 0 && scan.oldest > 0 && scan.oldest < scan.since:
+			backfill.Status, backfill.Message = database.RadarBackfillDone, "已到设定日期"
+		case backfill.Pages >= maxPages:
+			backfill.Status, backfill.Message = database.RadarBackfillDone, fmt.Sprintf("已达到页数上限 %d", maxPages)
+		}
+		if backfill.Status == database.RadarBackfillDone {
+			backfill.Cursor = ""
+		}
+		backfill.NewVideos += scan.newVideos - radarLog.NewVideos
+		radarLog.NewVideos = scan.newVideos
+		if err := s.repo.UpdateBackfill(target.ID, backfill); err != nil {
+			utils.LogError("[Radar] 保存回填进度失败 [%s]: %v", target.ID, err)
+			break
+		}
+
+		if backfill.Status == database.RadarBackfillPending {
+			select {
+			case <-s.ctx.Done():
+			case <-time.After(delay):
+			}
+		}
 	}
+	if radarLog.Status == "error" {
+		_ = s.repo.UpdateBackfill(target.ID, backfill)
+	} else if backfill.Pages == startPages {
+		return // 本轮没有拉取
+	}
+
+	radarLog.VideoList = scan.videoList()
+	_ = s.repo.AddLog(radarLog)
+	utils.LogInfo("[Radar] 账号 [%s] 回填 %d 页，新增 %d 个视频。%s", target.AuthorName, backfill.Pages, backfill.NewVideos, backfill.Message)
+}
+
+// feedPage 是 feed_list 返回的一页视频
+type feedPage struct {
+	objects    []interface{}
+	nextMarker string // 下一页的游标，为空表示没有更多
+	size       int    // 原始数据大小
+
+	live      *database.RadarLiveSession // 账号的直播状态，未在直播时为 nil
+	liveKnown bool                       // 返回数据中带有直播状态
+
+	contact    map[string]interface{} // 账号资料，没有时为 nil
+	fansCount  *int64                 // 粉丝数，返回数据中没有时为 nil
+	feedsCount *int64                 // 作品数
+}
 
-	// 1. 调用 WebSocket 获取用户视频列表 (feed_list)
+// fetchPage 获取监控目标的一页视频并计入每小时请求额度，调用前由轮询协程检查剩余额度
+func (s *RadarService) fetchPage(target database.RadarTarget, marker string) (*feedPage, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, time.Now())
+	s.mu.Unlock()
+	return s.requestPage(target, marker)
+}
+
+// requestPage 获取监控目标的一页视频：账号目标为视频列表，关键词目标为搜索结果
+func (s *RadarService) requestPage(target database.RadarTarget, marker string) (*feedPage, error) {
+	if target.IsKeyword() {
+		return s.fetchSearchPage(target, marker)
+	}
+	return s.fetchFeedPage(target, marker)
+}
+
+// fetchFeedPage 调用 WebSocket 获取一页用户视频列表 (feed_list)，错误信息可直接写入日志
+func (s *RadarService) fetchFeedPage(target database.RadarTarget, marker string) (*feedPage, error) {
 	body := websocket.FeedListBody{
 		Username:   target.Username,
-		NextMarker: "", // 只需要第一页最新数据
+		NextMarker: marker,
 	}
 
 	// 限制 30 秒超时
 	data, err := s.hub.CallAPI("key:channels:feed_list", body, 30*time.Second)
 	if err != nil {
-		radarLog.Status = "error"
 		if strings.Contains(err.Error(), "no available client") {
-			radarLog.ErrorMessage = "微信客户端未连接或已退出"
-			utils.LogWarn("[Radar] 检测失败 [%s]: %s", target.AuthorName, radarLog.ErrorMessage)
-		} else {
-			radarLog.ErrorMessage = err.Error()
-			utils.LogError("[Radar] 获取视频列表失败 [%s]: %v", target.AuthorName, err)
+			utils.LogWarn("[Radar] 检测失败 [%s]: %v", target.AuthorName, errRadarNoClient)
+			return nil, errRadarNoClient
 		}
-		_ = s.repo.AddLog(radarLog)
-		return
+		utils.LogError("[Radar] 获取视频列表失败 [%s]: %v", target.AuthorName, err)
+		return nil, err
 	}
 
-	// 2. 解析返回列表数据
+	// 解析返回列表数据
 	var rawResp struct {
 		Data struct {
 			BaseResponse struct {
 				Ret int `json:"Ret"`
 			} `json:"BaseResponse"`
-			ObjectList []interface{} `json:"objectList"`
-			Object     []interface{} `json:"object"`
+			ObjectList   []interface{} `json:"objectList"`
+			Object       []interface{} `json:"object"`
+			LastBuffer   string        `json:"lastBuffer"`
+			ContinueFlag *int          `json:"continueFlag"`
+
+			// 账号资料和直播状态，直播状态、粉丝数和作品数可能在 contact 中或与之并列
+			Contact    map[string]interface{} `json:"contact"`
+			LiveStatus interface{}            `json:"liveStatus"`
+			LiveInfo   interface{}            `json:"liveInfo"`
+			FansCount  interface{}            `json:"fansCount"`
+			FeedsCount interface{}            `json:"feedsCount"`
 		} `json:"data"`
 	}
 
 	if err := json.Unmarshal(data, &rawResp); err != nil {
-		radarLog.Status = "error"
-		radarLog.ErrorMessage = "解析返回数据失败: " + err.Error()
 		utils.LogError("[Radar] 解析视频列表失败 [%s]: %v", target.AuthorName, err)
-		_ = s.repo.AddLog(radarLog)
-		return
+		return nil, fmt.Errorf("解析返回数据失败: %w", err)
 	}
 
 	if rawResp.Data.BaseResponse.Ret != 0 {
-		radarLog.Status = "error"
-		radarLog.ErrorMessage = fmt.Sprintf("微信接口返回失败，状态码: %d (可能是请求过于频繁或账号异常)", rawResp.Data.BaseResponse.Ret)
 		utils.LogWarn("[Radar] 账号 [%s] 获取数据被微信拒绝(Ret:%d)", target.AuthorName, rawResp.Data.BaseResponse.Ret)
-		_ = s.repo.AddLog(radarLog)
-		return
+		return nil, fmt.Errorf("微信接口返回失败，状态码: %d (可能是请求过于频繁或账号异常)", rawResp.Data.BaseResponse.Ret)
 	}
 
 	// 兼容老版本或新版本 WeChat 可能返回的字段
-	allObjects := rawResp.Data.ObjectList
-	if len(allObjects) == 0 && len(rawResp.Data.Object) > 0 {
-		allObjects = rawResp.Data.Object
+	page := &feedPage{objects: rawResp.Data.ObjectList, size: len(data)}
+	if len(page.objects) == 0 && len(rawResp.Data.Object) > 0 {
+		page.objects = rawResp.Data.Object
+	}
+	// continueFlag 为 0 表示没有更多
+	if rawResp.Data.ContinueFlag == nil || *rawResp.Data.ContinueFlag != 0 {
+		page.nextMarker = rawResp.Data.LastBuffer
+	}
+	page.contact = rawResp.Data.Contact
+	page.fansCount = jsonCount(rawResp.Data.FansCount, rawResp.Data.Contact["fansCount"])
+	page.feedsCount = jsonCount(rawResp.Data.FeedsCount, rawResp.Data.Contact["feedsCount"])
+	page.live, page.liveKnown = liveFromEntry(rawResp.Data.Contact)
+	if !page.liveKnown {
+		page.live, page.liveKnown = liveFromEntry(map[string]interface{}{
+			"liveStatus": rawResp.Data.LiveStatus,
+			"liveInfo":   rawResp.Data.LiveInfo,
+		})
+	}
+	if page.live != nil {
+		page.live.Source = database.RadarLiveSourceFeed
 	}
+	return page, nil
+}
 
-	radarLog.FoundVideos = len(allObjects)
+// fetchSearchPage 调用 WebSocket 按关键词搜索一页视频 (contact_list，type 3)，与 feed_list 共用请求额度
+func (s *RadarService) fetchSearchPage(target database.RadarTarget, marker string) (*feedPage, error) {
+	body := websocket.SearchContactBody{
+		Keyword:    target.Keyword,
+		Type:       3,
+		NextMarker: marker,
+	}
 
-	if radarLog.FoundVideos == 0 {
-		utils.LogInfo("[Radar] 账号 [%s] 暂无视频数据(Raw Data Size: %d)", target.AuthorName, len(data))
-		_ = s.repo.AddLog(radarLog)
-		return
+	data, err := s.hub.CallAPI("key:channels:contact_list", body, 60*time.Second)
+	if err != nil {
+		if strings.Contains(err.Error(), "no available client") {
+			utils.LogWarn("[Radar] 搜索失败 [%s]: %v", target.Keyword, errRadarNoClient)
+			return nil, errRadarNoClient
+		}
+		utils.LogError("[Radar] 搜索视频失败 [%s]: %v", target.Keyword, err)
+		return nil, err
 	}
 
-	// 3. 提取视频 ID 并检查库中是否已存在
-	newVideoCount := 0
-	settings, _ := s.settings.Load()
-	if settings == nil {
-		settings = database.DefaultSettings()
+	// finderSearch 的 BaseResponse 可能在顶层或 data 中
+	type baseResponse struct {
+		Ret int `json:"Ret"`
+	}
+	var rawResp struct {
+		BaseResponse baseResponse `json:"BaseResponse"`
+		Data         struct {
+			BaseResponse baseResponse  `json:"BaseResponse"`
+			ObjectList   []interface{} `json:"objectList"`
+			LastBuff     string        `json:"lastBuff"`
+			ContinueFlag *int          `json:"continueFlag"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &rawResp); err != nil {
+		utils.LogError("[Radar] 解析搜索结果失败 [%s]: %v", target.Keyword, err)
+		return nil, fmt.Errorf("解析返回数据失败: %w", err)
+	}
+	ret := rawResp.BaseResponse.Ret
+	if ret == 0 {
+		ret = rawResp.Data.BaseResponse.Ret
+	}
+	if ret != 0 {
+		utils.LogWarn("[Radar] 关键词 [%s] 搜索被微信拒绝(Ret:%d)", target.Keyword, ret)
+		return nil, fmt.Errorf("微信接口返回失败，状态码: %d (可能是请求过于频繁或账号异常)", ret)
 	}
 
-	// 获取下载记录的 Repo
-	downloadRepo := database.NewDownloadRecordRepository()
+	page := &feedPage{objects: rawResp.Data.ObjectList, size: len(data)}
+	if rawResp.Data.ContinueFlag == nil || *rawResp.Data.ContinueFlag != 0 {
+		page.nextMarker = rawResp.Data.LastBuff
+	}
+	return page, nil
+}
+
+// radarScan 处理一次检测或回填中拉取到的视频，并汇总结果
+type radarScan struct {
+	s            *RadarService
+	target       database.RadarTarget
+	globalFilter *database.RadarFilter
+	group        *database.RadarGroup // 目标所在分组的设置，未分组或分组没有设置时为 nil
+	since        int64                // 回填时只入队该时间（Unix 秒）之后发布的视频，0 表示不限
+	dryRun       bool                 // 预览：只判断不写入，不保存元数据、不记录关键词命中、不加入队列
 
-	// 用于记录本次扫描的所有视频摘要
-	var videoSummaries []database.RadarVideoSummary
+	downloadRepo *database.DownloadRecordRepository
+	videoRepo    *database.VideoRepository
+	statsRepo    *database.VideoStatsRepository
+
+	summaries []database.RadarVideoSummary // 所有视频摘要
+	preview   []RadarPreviewVideo          // 预览时每个视频的判断结果
+	newVideos int                          // 加入队列的视频数
+	known     int                          // 处理前已有元数据的视频数
+	oldest    int64                        // 最早的发布时间（Unix 秒）
+}
 
-	for _, objInter := range allObjects {
+func (s *RadarService) newRadarScan(target database.RadarTarget) *radarScan {
+	// 全局过滤规则与目标自身的规则同时生效
+	globalFilter, err := s.settings.GetRadarFilter()
+	if err != nil {
+		utils.LogWarn("[Radar] 读取全局过滤规则失败，本次不使用全局规则: %v", err)
+	}
+	var group *database.RadarGroup
+	if target.Group != "" {
+		if group, err = s.repo.GetGroup(target.Group); err != nil {
+			utils.LogWarn("[Radar] 读取分组 %s 的设置失败，本次按未分组处理: %v", target.Group, err)
+		}
+	}
+	return &radarScan{
+		s:            s,
+		target:       target,
+		globalFilter: globalFilter,
+		group:        group,
+		downloadRepo: database.NewDownloadRecordRepository(),
+		videoRepo:    database.NewVideoRepository(),
+		statsRepo:    database.NewVideoStatsRepository(),
+	}
+}
+
+// videoList 将视频摘要序列化后用于存入日志
+func (scan *radarScan) videoList() string {
+	if len(scan.summaries) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(scan.summaries)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// handle 保存视频元数据，并把需要下载的新视频加入队列
+func (scan *radarScan) handle(objects []interface{}) {
+	target := scan.target
+	for _, objInter := range objects {
 		objMap, ok := objInter.(map[string]interface{})
 		if !ok {
 			continue
@@ -256,12 +777,20 @@ func (s *RadarService) processTarget(target database.RadarTarget) {
 		var fileSize int64
 		var duration int64
 		resolution := ""
+		mediaType := ""
 
 		if descInter, ok := objMap["objectDesc"]; ok {
 			if descMap, ok := descInter.(map[string]interface{}); ok {
 				if t, ok := descMap["description"].(string); ok {
 					title = t
 				}
+				// mediaType: 4 为视频，2 为图片
+				switch jsonInt64(descMap["mediaType"]) {
+				case 4:
+					mediaType = database.RadarMediaVideo
+				case 2:
+					mediaType = database.RadarMediaImage
+				}
 				// 遍历媒体列表，取第一条视频媒体
 				if mediaList, ok := descMap["media"].([]interface{}); ok && len(mediaList) > 0 {
 					if m, ok := mediaList[0].(map[string]interface{}); ok {
@@ -286,72 +815,225 @@ func (s *RadarService) processTarget(target database.RadarTarget) {
 			}
 		}
 
+		if known, err := scan.videoRepo.Exists(videoID); err == nil && known {
+			scan.known++
+		}
+
+		// 关键词的搜索结果来自不同作者，作者取视频附带的 contact
+		authorID, authorName := target.Username, target.AuthorName
+		if target.IsKeyword() {
+			authorID, authorName = objectAuthor(objMap)
+		}
+
+		// 写入规范化视频元数据，供视频/作者详情聚合
+		video := &database.Video{
+			ID:           videoID,
+			Title:        title,
+			AuthorID:     authorID,
+			AuthorName:   authorName,
+			CoverURL:     coverURL,
+			Duration:     duration,
+			Size:         fileSize,
+			Resolution:   resolution,
+			LikeCount:    jsonInt64(objMap["likeCount"]),
+			CommentCount: jsonInt64(objMap["commentCount"]),
+			FavCount:     jsonInt64(objMap["favCount"]),
+			ForwardCount: jsonInt64(objMap["forwardCount"]),
+			PublishedAt:  jsonInt64(objMap["createtime"]),
+		}
+		video.NonceID, _ = objMap["objectNonceId"].(string)
+		if !scan.dryRun {
+			if err := scan.videoRepo.Upsert(video); err != nil {
+				utils.LogWarn("[Radar] 保存视频元数据失败 [%s]: %v", videoID, err)
+			}
+			if err := scan.statsRepo.Add(statsSnapshotOf(video, database.StatsSourceRadar)); err != nil {
+				utils.LogWarn("[Radar] 保存互动数据快照失败 [%s]: %v", videoID, err)
+			}
+		}
+		if video.PublishedAt > 0 && (scan.oldest == 0 || video.PublishedAt < scan.oldest) {
+			scan.oldest = video.PublishedAt
+		}
+
+		media := &database.RadarMedia{
+			Title:      title,
+			MediaType:  mediaType,
+			Duration:   duration,
+			Size:       fileSize,
+			Resolution: resolution,
+		}
 		if title == "" {
 			title = fmt.Sprintf("RadarV_%s", videoID)
 		}
 
-		// 4. 判断是否需要下载
-		isNew := true
+		// 判断是否需要下载；关键词之前搜到过的视频不再重复处理
+		knownReason := ""
+		if target.IsKeyword() {
+			if scan.dryRun {
+				if hit, err := scan.s.repo.HasKeywordHit(target.Keyword, videoID); err == nil && hit {
+					knownReason = "关键词之前搜到过"
+				}
+			} else {
+				first, err := scan.s.repo.AddKeywordHit(&database.RadarKeywordHit{
+					Keyword:    target.Keyword,
+					VideoID:    videoID,
+					TargetID:   target.ID,
+					Title:      video.Title,
+					AuthorID:   authorID,
+					AuthorName: authorName,
+				})
+				if err != nil {
+					utils.LogWarn("[Radar] 记录关键词命中失败 [%s]: %v", videoID, err)
+				} else if !first {
+					knownReason = "关键词之前搜到过"
+				}
+			}
+		}
 
-		record, _ := downloadRepo.GetByVideoID(videoID)
+		record, _ := scan.downloadRepo.GetByVideoID(videoID)
 		if record != nil && (record.Status == database.DownloadStatusCompleted || record.Status == database.DownloadStatusInProgress) {
-			isNew = false
+			knownReason = "已有下载记录"
 		}
 
-		if isNew {
-			queueItem, _ := s.queueService.GetByVideoID(videoID)
+		if knownReason == "" {
+			queueItem, _ := scan.s.queueService.GetByVideoID(videoID)
 			if queueItem != nil && (queueItem.Status == database.QueueStatusPending || queueItem.Status == database.QueueStatusDownloading || queueItem.Status == database.QueueStatusCompleted) {
-				isNew = false
+				knownReason = "已在下载队列中"
 			}
 		}
+		isNew := knownReason == ""
 
-		// 记录视频摘要
-		videoSummaries = append(videoSummaries, database.RadarVideoSummary{
+		// 记录视频摘要，不符合过滤规则的新视频也记录跳过原因
+		summary := database.RadarVideoSummary{
 			VideoID: videoID,
 			Title:   title,
 			IsNew:   isNew,
-		})
-
+		}
+		if target.IsKeyword() {
+			summary.Author, summary.Keyword = authorName, target.Keyword
+		}
+		notifyOnly := target.Action == database.RadarActionNotify
 		if isNew {
-			if videoURL == "" {
-				utils.LogWarn("[Radar] 新视频 [%s] 无法提取 URL，跳过: %s", target.AuthorName, videoID)
+			if scan.since > 0 && video.PublishedAt > 0 && video.PublishedAt < scan.since {
+				summary.Skipped, summary.SkipReason = true, "早于回填起始日期"
+			} else if reason := radarSkipReason(scan.globalFilter, scan.groupFilter(), target.Filter, media); reason != "" {
+				summary.Skipped, summary.SkipReason = true, reason
+				if !scan.dryRun {
+					utils.LogInfo("[Radar] 跳过新视频 [%s]: %s (%s)", target.AuthorName, title, reason)
+				}
+			} else if videoURL == "" && !notifyOnly {
+				summary.Skipped, summary.SkipReason = true, "无法提取视频地址"
+				if !scan.dryRun {
+					utils.LogWarn("[Radar] 新视频 [%s] 无法提取 URL，跳过: %s", target.AuthorName, videoID)
+				}
+			}
+		}
+		scan.summaries = append(scan.summaries, summary)
+		if scan.dryRun {
+			scan.preview = append(scan.preview, previewVideo(&summary, knownReason, notifyOnly, video.PublishedAt))
+		}
+
+		if isNew && !summary.Skipped {
+			scan.newVideos++
+			if scan.dryRun {
 				continue
 			}
 			utils.LogInfo("[Radar] 发现新视频 [%s]: %s (%s)", target.AuthorName, title, videoID)
-			newVideoCount++
+			if notifyOnly {
+				continue // 只记录到日志，不下载
+			}
 
 			// 直接从 feed_list 数据入队，无需额外请求 feed_profile
 			req := []VideoInfo{{
 				VideoID:    videoID,
 				Title:      title,
-				Author:     target.AuthorName,
+				Author:     authorName,
+				AuthorID:   authorID,
 				VideoURL:   videoURL,
 				CoverURL:   coverURL,
 				Size:       fileSize,
 				DecryptKey: decodeKey,
 				Duration:   duration,
 				Resolution: resolution,
+				Group:      target.Group,
+				SubDir:     scan.group.SubDir(authorName, target.Keyword, time.Now()),
 			}}
-			if _, err := s.queueService.AddToQueue(req); err != nil {
+			if scan.group != nil {
+				req[0].GroupPriority = scan.group.Priority
+			}
+			if _, err := scan.s.queueService.AddToQueue(req); err != nil {
 				utils.LogError("[Radar] 添加视频到下载队列失败 [%s]-[%s]: %v", target.AuthorName, title, err)
 			} else {
 				utils.LogInfo("[Radar] 成功加入队列: %s", title)
 			}
 		}
 	}
+}
 
-	radarLog.NewVideos = newVideoCount
+// groupFilter 返回目标所在分组的过滤规则，没有时返回 nil
+func (scan *radarScan) groupFilter() *database.RadarFilter {
+	if scan.group == nil {
+		return nil
+	}
+	return scan.group.Filter
+}
 
-	// 将视频摘要序列化后存入日志
-	if len(videoSummaries) > 0 {
-		if b, err := json.Marshal(videoSummaries); err == nil {
-			radarLog.VideoList = string(b)
-		}
+// radarSkipReason 依次按全局规则、分组规则和目标规则检查视频，返回跳过原因，符合规则时返回空字符串
+func radarSkipReason(global, group, own *database.RadarFilter, media *database.RadarMedia) string {
+	if reason := global.Check(media); reason != "" {
+		return "全局规则: " + reason
+	}
+	if reason := group.Check(media); reason != "" {
+		return "分组规则: " + reason
+	}
+	if reason := own.Check(media); reason != "" {
+		return "目标规则: " + reason
+	}
+	return ""
+}
+
+// objectAuthor 取视频附带的 contact 中的作者 username 和昵称
+func objectAuthor(objMap map[string]interface{}) (string, string) {
+	contact, ok := objMap["contact"].(map[string]interface{})
+	if !ok {
+		return "", ""
 	}
+	username, _ := contact["username"].(string)
+	nickname, _ := contact["nickname"].(string)
+	return username, nickname
+}
 
-	_ = s.repo.AddLog(radarLog)
+// saveAuthor 用 feed_list 返回的 contact 信息更新作者资料
+func (s *RadarService) saveAuthor(target database.RadarTarget, objects []interface{}) {
+	author := &database.Author{ID: target.Username, Nickname: target.AuthorName}
+	for _, objInter := range objects {
+		objMap, ok := objInter.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contact, ok := objMap["contact"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nickname, _ := contact["nickname"].(string); nickname != "" {
+			author.Nickname = nickname
+		}
+		author.AvatarURL, _ = contact["headUrl"].(string)
+		author.Signature, _ = contact["signature"].(string)
+		break
+	}
+	if err := database.NewAuthorRepository().Upsert(author); err != nil {
+		utils.LogWarn("[Radar] 保存作者资料失败 [%s]: %v", target.Username, err)
+	}
+}
 
-	if newVideoCount > 0 {
-		utils.LogInfo("[Radar] 账号 [%s] 检测完毕，新增 %d 个视频并加入下载队列", target.AuthorName, newVideoCount)
+// jsonInt64 将 JSON 解码出的数字（float64 或字符串）转换为 int64
+func jsonInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
 	}
+	return 0
 }
@@ -11,13 +11,19 @@ type SearchResult struct {
 	DownloadResults []database.DownloadRecord `json:"downloadResults"`
 	BrowseCount     int64                     `json:"browseCount"`
 	DownloadCount   int64                     `json:"downloadCount"`
+	CommentCount    int64                     `json:"commentCount"`
 	TotalCount      int64                     `json:"totalCount"`
+	// Hits 跨浏览、下载和评论按相关度排序的命中，标题和摘要已高亮
+	Hits []database.SearchHit `json:"hits"`
+	// Mode 检索方式：fts5（全文索引）或 like
+	Mode string `json:"mode"`
 }
 
 // SearchService 处理全局搜索业务逻辑
 type SearchService struct {
 	browseRepo   *database.BrowseHistoryRepository
 	downloadRepo *database.DownloadRecordRepository
+	searchRepo   *database.SearchRepository
 }
 
 // NewSearchService 创建一个新的 SearchService
@@ -25,6 +31,7 @@ func NewSearchService() *SearchService {
 	return &SearchService{
 		browseRepo:   database.NewBrowseHistoryRepository(),
 		downloadRepo: database.NewDownloadRecordRepository(),
+		searchRepo:   database.NewSearchRepository(),
 	}
 }
 
@@ -32,6 +39,11 @@ func NewSearchService() *SearchService {
 // Requirements: 12.1 - 搜索浏览和下载记录
 // Requirements: 12.2 - 按来源分组并显示计数
 func (s *SearchService) Search(query string, limit int) (*SearchResult, error) {
+	return s.SearchTagged(query, nil, limit)
+}
+
+// SearchTagged 同 Search，结果限定为带有全部指定标签的视频
+func (s *SearchService) SearchTagged(query string, tags []string, limit int) (*SearchResult, error) {
 	if limit < 1 {
 		limit = 20
 	}
@@ -39,16 +51,26 @@ func (s *SearchService) Search(query string, limit int) (*SearchResult, error) {
 	result := &SearchResult{
 		BrowseResults:   []database.BrowseRecord{},
 		DownloadResults: []database.DownloadRecord{},
+		Hits:            []database.SearchHit{},
+	}
+
+	var filter *database.RecordFilter
+	if len(tags) > 0 {
+		filter = &database.RecordFilter{Tags: tags}
 	}
 
 	// 搜索浏览记录
-	browseParams := &database.PaginationParams{
-		Page:     1,
-		PageSize: limit,
-		SortBy:   "browse_time",
-		SortDesc: true,
+	browseParams := &database.FilterParams{
+		PaginationParams: database.PaginationParams{
+			Page:     1,
+			PageSize: limit,
+			SortBy:   "browse_time",
+			SortDesc: true,
+		},
+		Query:  query,
+		Filter: filter,
 	}
-	browseResult, err := s.browseRepo.Search(query, browseParams)
+	browseResult, err := s.browseRepo.ListFiltered(browseParams)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +85,8 @@ func (s *SearchService) Search(query string, limit int) (*SearchResult, error) {
 			SortBy:   "download_time",
 			SortDesc: true,
 		},
-		Query: query,
+		Query:  query,
+		Filter: filter,
 	}
 	downloadResult, err := s.downloadRepo.List(downloadParams)
 	if err != nil {
@@ -72,8 +95,17 @@ func (s *SearchService) Search(query string, limit int) (*SearchResult, error) {
 	result.DownloadResults = downloadResult.Items
 	result.DownloadCount = downloadResult.Total
 
+	// 跨类型的相关度排序结果（含评论）
+	hits, err := s.searchRepo.SearchTagged(query, tags, limit)
+	if err != nil {
+		return nil, err
+	}
+	result.Hits = hits.Hits
+	result.Mode = hits.Mode
+	result.CommentCount = hits.Counts[database.SearchTypeComment]
+
 	// 计算总数
-	result.TotalCount = result.BrowseCount + result.DownloadCount
+	result.TotalCount = result.BrowseCount + result.DownloadCount + result.CommentCount
 
 	return result, nil
 }
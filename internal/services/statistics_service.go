@@ -1,6 +1,8 @@
 package services
 
 import (
+	"time"
+
 	"wx_channel/internal/database"
 )
 
@@ -20,17 +22,47 @@ type ChartData struct {
 	Values []int64  `json:"values"`
 }
 
+// AuthorAnalyticsPage 表示分页的作者统计列表
+type AuthorAnalyticsPage struct {
+	Items  []database.AuthorAnalytics `json:"items"`
+	Total  int64                      `json:"total"`
+	Limit  int                        `json:"limit"`
+	Offset int                        `json:"offset"`
+	Sort   string                     `json:"sort"`
+}
+
+// AuthorAnalyticsDetail 表示单个作者的统计与表现最好的视频
+type AuthorAnalyticsDetail struct {
+	database.AuthorAnalytics
+	TopVideos []database.Video `json:"topVideos"`
+}
+
+// ActivityStats 表示浏览活跃度热力图及统计范围
+type ActivityStats struct {
+	*database.ActivityHeatmap
+	Days     int    `json:"days"`
+	AuthorID string `json:"authorId,omitempty"`
+}
+
+const (
+	maxAuthorAnalyticsLimit = 200 // 作者统计单页上限
+	topVideosLimit          = 10  // 作者统计返回的最佳视频数
+	maxActivityDays         = 365 // 活跃度热力图最大统计天数
+)
+
 // StatisticsService 处理统计业务逻辑
 type StatisticsService struct {
-	browseRepo   *database.BrowseHistoryRepository
-	downloadRepo *database.DownloadRecordRepository
+	browseRepo    *database.BrowseHistoryRepository
+	downloadRepo  *database.DownloadRecordRepository
+	analyticsRepo *database.AnalyticsRepository
 }
 
 // NewStatisticsService 创建一个新的 StatisticsService
 func NewStatisticsService() *StatisticsService {
 	return &StatisticsService{
-		browseRepo:   database.NewBrowseHistoryRepository(),
-		downloadRepo: database.NewDownloadRecordRepository(),
+		browseRepo:    database.NewBrowseHistoryRepository(),
+		downloadRepo:  database.NewDownloadRecordRepository(),
+		analyticsRepo: database.NewAnalyticsRepository(),
 	}
 }
 
@@ -121,3 +153,51 @@ func (s *StatisticsService) GetRecentDownload(limit int) ([]database.DownloadRec
 	}
 	return s.downloadRepo.GetRecent(limit)
 }
+
+// GetAuthorAnalytics 返回按作者聚合的统计列表，sort 可选 videos/downloads/bytes/engagement/recent
+func (s *StatisticsService) GetAuthorAnalytics(sort string, limit, offset int) (*AuthorAnalyticsPage, error) {
+	if limit < 1 || limit > maxAuthorAnalyticsLimit {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if sort == "" {
+		sort = database.AuthorSortVideos
+	}
+	items, err := s.analyticsRepo.AuthorAnalytics("", sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.analyticsRepo.CountAuthors()
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorAnalyticsPage{Items: items, Total: total, Limit: limit, Offset: offset, Sort: sort}, nil
+}
+
+// GetAuthorAnalyticsDetail 返回单个作者的统计和点赞最多的视频，作者没有视频记录时返回 nil
+func (s *StatisticsService) GetAuthorAnalyticsDetail(authorID string) (*AuthorAnalyticsDetail, error) {
+	items, err := s.analyticsRepo.AuthorAnalytics(authorID, "", 1, 0)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	detail := &AuthorAnalyticsDetail{AuthorAnalytics: items[0]}
+	if detail.TopVideos, err = s.analyticsRepo.TopVideos(authorID, topVideosLimit); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// GetActivity 返回最近 days 天浏览时间按星期和小时分布的热力图
+func (s *StatisticsService) GetActivity(authorID string, days int) (*ActivityStats, error) {
+	if days < 1 || days > maxActivityDays {
+		days = 30
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	heatmap, err := s.analyticsRepo.ActivityHeatmap(authorID, since)
+	if err != nil {
+		return nil, err
+	}
+	return &ActivityStats{ActivityHeatmap: heatmap, Days: days, AuthorID: authorID}, nil
+}
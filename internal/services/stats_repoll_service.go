@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"wx_channel/internal/config"
+	"wx_channel/internal/database"
+	"wx_channel/internal/utils"
+	"wx_channel/internal/websocket"
+)
+
+const (
+	// statsRepollBatch 每轮最多重新拉取的视频数
+	statsRepollBatch = 50
+	// statsRepollGap 两次 feed_profile 调用之间的间隔，避免触发微信频率限制
+	statsRepollGap = 3 * time.Second
+	// defaultStatsRepollInterval 未配置 stats_repoll_interval 时的默认间隔
+	defaultStatsRepollInterval = 6 * time.Hour
+)
+
+// StatsRepollService 定时通过 feed_profile 重新拉取被跟踪视频的互动数据并写入快照
+type StatsRepollService struct {
+	hub    *websocket.Hub
+	videos *database.VideoRepository
+	stats  *database.VideoStatsRepository
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	running bool
+	lastRun time.Time
+}
+
+// NewStatsRepollService 创建一个新的互动数据重新拉取服务
+func NewStatsRepollService(hub *websocket.Hub) *StatsRepollService {
+	return &StatsRepollService{
+		hub:    hub,
+		videos: database.NewVideoRepository(),
+		stats:  database.NewVideoStatsRepository(),
+	}
+}
+
+// Start 启动定时重新拉取
+func (s *StatsRepollService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return
+	}
+	s.running = true
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go func(ctx context.Context) {
+		defer s.wg.Done()
+		utils.LogInfo("[Stats] 互动数据重新拉取已启动")
+
+		// 间隔在每次检查时读取配置，修改后无需重启
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				utils.LogInfo("[Stats] 互动数据重新拉取已停止")
+				return
+			case <-ticker.C:
+				if time.Since(s.lastRun) >= repollInterval() {
+					s.lastRun = time.Now()
+					s.RepollOnce(ctx)
+				}
+			}
+		}
+	}(s.ctx)
+}
+
+// Stop 停止定时重新拉取
+func (s *StatsRepollService) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	cancel()
+	s.wg.Wait()
+}
+
+func repollInterval() time.Duration {
+	if cfg := config.Get(); cfg != nil && cfg.StatsRepollInterval > 0 {
+		return cfg.StatsRepollInterval
+	}
+	return defaultStatsRepollInterval
+}
+
+// RepollOnce 重新拉取一批被跟踪的视频，返回成功数量
+func (s *StatsRepollService) RepollOnce(ctx context.Context) int {
+	if s.hub == nil || s.hub.ClientCount() == 0 {
+		utils.LogWarn("[Stats] 微信客户端未连接，跳过本轮重新拉取")
+		return 0
+	}
+
+	videos, err := s.videos.ListStatsTracked(statsRepollBatch)
+	if err != nil {
+		utils.LogError("[Stats] 获取跟踪视频失败: %v", err)
+		return 0
+	}
+
+	refreshed := 0
+	for i, video := range videos {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return refreshed
+			case <-time.After(statsRepollGap):
+			}
+		}
+		if err := s.videos.MarkStatsAttempted(video.ID, time.Now()); err != nil {
+			utils.LogWarn("[Stats] 记录拉取时间失败 [%s]: %v", video.ID, err)
+		}
+		if err := s.repollVideo(video); err != nil {
+			utils.LogWarn("[Stats] 重新拉取失败 [%s]: %v", video.ID, err)
+			continue
+		}
+		refreshed++
+	}
+	if len(videos) > 0 {
+		utils.LogInfo("[Stats] 本轮重新拉取 %d/%d 个视频", refreshed, len(videos))
+	}
+	return refreshed
+}
+
+func (s *StatsRepollService) repollVideo(video database.Video) error {
+	data, err := s.hub.CallAPI("key:channels:feed_profile", websocket.FeedProfileBody{
+		ObjectID: video.ID,
+		NonceID:  video.NonceID,
+	}, 30*time.Second)
+	if err != nil {
+		return err
+	}
+
+	latest, err := parseFeedProfileStats(video.ID, data)
+	if err != nil {
+		return err
+	}
+	if err := s.videos.Upsert(latest); err != nil {
+		return err
+	}
+	return s.stats.Add(statsSnapshotOf(latest, database.StatsSourceRepoll))
+}
+
+// parseFeedProfileStats 从 feed_profile 返回中提取互动数据
+func parseFeedProfileStats(videoID string, data []byte) (*database.Video, error) {
+	var resp struct {
+		Data struct {
+			BaseResponse struct {
+				Ret int `json:"Ret"`
+			} `json:"BaseResponse"`
+			Object map[string]interface{} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("解析返回数据失败: %w", err)
+	}
+	if resp.Data.BaseResponse.Ret != 0 {
+		return nil, fmt.Errorf("微信接口返回失败，状态码: %d", resp.Data.BaseResponse.Ret)
+	}
+	obj := resp.Data.Object
+	if obj == nil {
+		return nil, fmt.Errorf("返回数据中没有视频对象")
+	}
+	return &database.Video{
+		ID:           videoID,
+		LikeCount:    jsonInt64(obj["likeCount"]),
+		CommentCount: jsonInt64(obj["commentCount"]),
+		FavCount:     jsonInt64(obj["favCount"]),
+		ForwardCount: jsonInt64(obj["forwardCount"]),
+	}, nil
+}
+
+// statsSnapshotOf 用视频当前的互动数据生成快照
+func statsSnapshotOf(v *database.Video, source string) *database.StatsSnapshot {
+	return &database.StatsSnapshot{
+		VideoID:      v.ID,
+		Source:       source,
+		LikeCount:    v.LikeCount,
+		CommentCount: v.CommentCount,
+		FavCount:     v.FavCount,
+		ForwardCount: v.ForwardCount,
+	}
+}
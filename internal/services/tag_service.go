@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+
+	"wx_channel/internal/database"
+)
+
+// 批量操作中记录 ID 的类型
+const (
+	RecordTypeBrowse   = "browse"
+	RecordTypeDownload = "download"
+)
+
+// TagService 处理标签业务逻辑
+type TagService struct {
+	repo *database.TagRepository
+}
+
+// NewTagService 创建一个新的 TagService
+func NewTagService() *TagService {
+	return &TagService{
+		repo: database.NewTagRepository(),
+	}
+}
+
+// List 获取所有标签；videoID 不为空时只返回该视频的标签
+func (s *TagService) List(videoID string) ([]database.Tag, error) {
+	if videoID != "" {
+		return s.repo.ListByVideo(videoID)
+	}
+	return s.repo.List()
+}
+
+// Create 创建标签
+func (s *TagService) Create(tag *database.Tag) error {
+	return s.repo.Create(tag)
+}
+
+// Update 更新标签
+func (s *TagService) Update(tag *database.Tag) error {
+	return s.repo.Update(tag)
+}
+
+// Delete 删除标签
+func (s *TagService) Delete(id string) error {
+	return s.repo.Delete(id)
+}
+
+// Tag 给记录批量添加标签，recordType 决定 ids 是浏览记录 ID 还是下载记录 ID
+func (s *TagService) Tag(recordType string, ids, tags []string) (int64, error) {
+	videoIDs, err := resolveVideoIDs(recordType, ids)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.TagVideos(videoIDs, tags)
+}
+
+// Untag 批量移除记录上的标签
+func (s *TagService) Untag(recordType string, ids, tags []string) (int64, error) {
+	videoIDs, err := resolveVideoIDs(recordType, ids)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.UntagVideos(videoIDs, tags)
+}
+
+// resolveVideoIDs 把记录 ID 转换为视频 ID
+// 浏览记录的 ID 即视频 ID；下载记录通过 video_id 字段关联
+func resolveVideoIDs(recordType string, ids []string) ([]string, error) {
+	switch recordType {
+	case "", RecordTypeBrowse:
+		return ids, nil
+	case RecordTypeDownload:
+		records, err := database.NewDownloadRecordRepository().GetByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		var videoIDs []string
+		for _, r := range records {
+			if r.VideoID != "" && !seen[r.VideoID] {
+				seen[r.VideoID] = true
+				videoIDs = append(videoIDs, r.VideoID)
+			}
+		}
+		return videoIDs, nil
+	default:
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+}
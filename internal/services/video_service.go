@@ -0,0 +1,120 @@
+package services
+
+import (
+	"time"
+
+	"wx_channel/internal/database"
+)
+
+// VideoDetail 聚合一个视频在本地的全部信息
+type VideoDetail struct {
+	Video         *database.Video           `json:"video"`
+	Author        *database.Author          `json:"author"`
+	Browse        *database.BrowseRecord    `json:"browse"`
+	Downloads     []database.DownloadRecord `json:"downloads"`
+	Queue         *database.QueueItem       `json:"queue"`
+	Tags          []database.Tag            `json:"tags"`
+	Collections   []database.Collection     `json:"collections"`
+	CommentCount  int64                     `json:"commentCount"`  // 已采集的评论数
+	RadarKeywords []string                  `json:"radarKeywords"` // 雷达关键词目标中搜到该视频的关键词
+}
+
+// AuthorDetail 聚合一个作者在本地的全部信息
+type AuthorDetail struct {
+	Author      *database.Author      `json:"author"`
+	Stats       *database.AuthorStats `json:"stats"`
+	Videos      []database.Video      `json:"videos"`
+	RadarTarget *database.RadarTarget `json:"radarTarget"` // 未加入雷达监控时为 nil
+}
+
+// authorVideoLimit 作者详情中返回的最大视频数
+const authorVideoLimit = 200
+
+// maxStatsHistoryPoints 单次返回的最大快照数
+const maxStatsHistoryPoints = 5000
+
+// VideoService 提供视频与作者的详情聚合
+type VideoService struct {
+	videos  *database.VideoRepository
+	authors *database.AuthorRepository
+	stats   *database.VideoStatsRepository
+}
+
+// NewVideoService 创建一个新的 VideoService
+func NewVideoService() *VideoService {
+	return &VideoService{
+		videos:  database.NewVideoRepository(),
+		authors: database.NewAuthorRepository(),
+		stats:   database.NewVideoStatsRepository(),
+	}
+}
+
+// VideoDetail 获取视频详情，视频不存在时返回 nil
+func (s *VideoService) VideoDetail(id string) (*VideoDetail, error) {
+	video, err := s.videos.GetByID(id)
+	if err != nil || video == nil {
+		return nil, err
+	}
+
+	detail := &VideoDetail{Video: video}
+	if video.AuthorID != "" {
+		if detail.Author, err = s.authors.GetByID(video.AuthorID); err != nil {
+			return nil, err
+		}
+	}
+	if detail.Browse, err = database.NewBrowseHistoryRepository().GetByID(id); err != nil {
+		return nil, err
+	}
+	if detail.Downloads, err = database.NewDownloadRecordRepository().ListByVideoID(id); err != nil {
+		return nil, err
+	}
+	if detail.Queue, err = database.NewQueueRepository().GetByVideoID(id); err != nil {
+		return nil, err
+	}
+	if detail.Tags, err = database.NewTagRepository().ListByVideo(id); err != nil {
+		return nil, err
+	}
+	if detail.Collections, err = database.NewCollectionRepository().ListByVideo(id); err != nil {
+		return nil, err
+	}
+	if detail.CommentCount, err = database.NewCommentRepository().CountByVideo(id); err != nil {
+		return nil, err
+	}
+	if detail.RadarKeywords, err = database.NewRadarRepository().GetKeywordsByVideoID(id); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// AuthorDetail 获取作者详情，作者不存在时返回 nil
+func (s *VideoService) AuthorDetail(id string) (*AuthorDetail, error) {
+	author, err := s.authors.GetByID(id)
+	if err != nil || author == nil {
+		return nil, err
+	}
+
+	detail := &AuthorDetail{Author: author}
+	if detail.Stats, err = s.authors.Stats(id); err != nil {
+		return nil, err
+	}
+	if detail.Videos, err = s.videos.ListByAuthor(id, authorVideoLimit); err != nil {
+		return nil, err
+	}
+	if detail.RadarTarget, err = database.NewRadarRepository().GetByUsername(id); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// StatsHistory 获取视频互动数据的时间序列，limit 超出范围时取上限
+func (s *VideoService) StatsHistory(videoID string, since, until time.Time, limit int) ([]database.StatsSnapshot, error) {
+	if limit <= 0 || limit > maxStatsHistoryPoints {
+		limit = maxStatsHistoryPoints
+	}
+	return s.stats.History(videoID, since, until, limit)
+}
+
+// SetStatsTracked 设置视频是否参与互动数据定时重新拉取
+func (s *VideoService) SetStatsTracked(videoID string, tracked bool) error {
+	return s.videos.SetStatsTracked(videoID, tracked)
+}
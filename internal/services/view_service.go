@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"wx_channel/internal/database"
+)
+
+// ViewService 管理已保存视图，并把查询语法解析为记录过滤条件
+type ViewService struct {
+	repo *database.SettingsRepository
+}
+
+// NewViewService 创建一个新的 ViewService
+func NewViewService() *ViewService {
+	return &ViewService{
+		repo: database.NewSettingsRepository(),
+	}
+}
+
+// List 获取所有已保存视图
+func (s *ViewService) List() ([]database.SavedView, error) {
+	return s.repo.ListViews()
+}
+
+// Get 按名称获取视图
+func (s *ViewService) Get(name string) (*database.SavedView, error) {
+	return s.repo.GetView(name)
+}
+
+// Save 创建或更新视图
+func (s *ViewService) Save(view *database.SavedView) error {
+	return s.repo.SaveView(view)
+}
+
+// Delete 删除视图
+func (s *ViewService) Delete(name string) error {
+	return s.repo.DeleteView(name)
+}
+
+// ResolveFilter 解析 params.Query 中的结构化条件，并合并 viewName 指定的视图
+// 视图条件在前、请求条件在后，同一字段以请求为准；sortGiven 为 false 时使用视图的排序
+func (s *ViewService) ResolveFilter(params *database.FilterParams, viewName, target string, sortGiven bool) error {
+	query := params.Query
+	if viewName != "" {
+		view, err := s.repo.GetView(viewName)
+		if err != nil {
+			return err
+		}
+		if view == nil {
+			return fmt.Errorf("view not found: %s", viewName)
+		}
+		if view.Target != "" && view.Target != target {
+			return fmt.Errorf("view %s is for %s", viewName, view.Target)
+		}
+		query = strings.TrimSpace(view.Query + " " + query)
+		if !sortGiven && view.SortBy != "" {
+			params.SortBy = view.SortBy
+			params.SortDesc = view.SortDesc
+		}
+	}
+
+	filter, text, err := database.ParseRecordQuery(query)
+	if err != nil {
+		return err
+	}
+	params.Query = text
+	if !filter.IsZero() {
+		params.Filter = filter
+	}
+	return nil
+}
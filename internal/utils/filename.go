@@ -176,4 +176,42 @@ func GenerateVideoFilename(title, videoID string) string {
 	}
 
 	return filename
-}
\ No newline at end of file
+}
+
+// folderTemplateVar 匹配子目录模板中的 {变量}
+var folderTemplateVar = regexp.MustCompile(`\{(\w+)\}`)
+
+// ExpandFolderTemplate 按 vars 替换子目录模板（以 / 分隔，如 "{group}/{author}"）中的 {变量}，
+// 每一级分别清理为合法的文件夹名，替换后为空的层级省略，返回以 / 分隔的相对路径
+func ExpandFolderTemplate(template string, vars map[string]string) string {
+	var parts []string
+	for _, segment := range strings.FieldsFunc(template, func(r rune) bool { return r == '/' || r == '\\' }) {
+		segment = folderTemplateVar.ReplaceAllStringFunc(segment, func(m string) string {
+			return vars[m[1:len(m)-1]]
+		})
+		if strings.TrimSpace(segment) == "" {
+			continue
+		}
+		parts = append(parts, CleanFolderName(segment))
+	}
+	return strings.Join(parts, "/")
+}
+
+// ValidateFolderTemplate 检查子目录模板只使用 allowed 中的变量，且不含 . 或 .. 层级
+func ValidateFolderTemplate(template string, allowed ...string) error {
+	for _, segment := range strings.FieldsFunc(template, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if s := strings.TrimSpace(segment); s == "." || s == ".." {
+			return fmt.Errorf("invalid folder segment %q", segment)
+		}
+		for _, m := range folderTemplateVar.FindAllStringSubmatch(segment, -1) {
+			known := false
+			for _, name := range allowed {
+				known = known || m[1] == name
+			}
+			if !known {
+				return fmt.Errorf("unknown folder variable {%s}", m[1])
+			}
+		}
+	}
+	return nil
+}
@@ -109,3 +109,32 @@ func TestCleanFolderName_TrailingDots(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandFolderTemplate(t *testing.T) {
+	vars := map[string]string{"group": "竞品", "author": "老王/摄影", "keyword": ""}
+	testCases := []struct {
+		template string
+		expected string
+	}{
+		{"{group}/{author}", "竞品/老王_摄影"},
+		{"雷达\\{group}", "雷达/竞品"},
+		{"{keyword}/{author}", "老王_摄影"},
+		{"{group}-{keyword}", "竞品-"},
+		{"{unknown}", ""},
+		{"..", "未知作者"},
+	}
+	for _, tc := range testCases {
+		if got := ExpandFolderTemplate(tc.template, vars); got != tc.expected {
+			t.Errorf("模板: %q, 期望: %q, 实际: %q", tc.template, tc.expected, got)
+		}
+	}
+
+	if err := ValidateFolderTemplate("{group}/{author}", "group", "author"); err != nil {
+		t.Errorf("合法模板不应报错: %v", err)
+	}
+	for _, template := range []string{"{group}/../x", "{title}"} {
+		if err := ValidateFolderTemplate(template, "group", "author"); err == nil {
+			t.Errorf("模板 %q 应该报错", template)
+		}
+	}
+}
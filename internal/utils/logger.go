@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +22,22 @@ const (
 	ERROR
 )
 
+// ParseLogLevel 解析配置中的日志级别名称（空字符串视为 info）
+func ParseLogLevel(name string) (LogLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DEBUG, true
+	case "", "info":
+		return INFO, true
+	case "warn", "warning":
+		return WARN, true
+	case "error":
+		return ERROR, true
+	default:
+		return INFO, false
+	}
+}
+
 var (
 	// 保持对外的 Logger 结构，但内部换成 zerolog
 	defaultLogger *Logger
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 是解析后的 5 段 cron 表达式（分 时 日 月 周），每段用位集合表示允许的取值
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// 日和周中有一个为 * 时两者同时满足即可，都有限制时满足其一即可（与 Vixie cron 一致）
+	domStar, dowStar bool
+}
+
+// cronMacros 常用的预定义表达式
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// ParseCron 解析 5 段 cron 表达式，支持 *、a-b、*/n、a-b/n、逗号列表和 @hourly 等预定义表达式。
+// 周的取值为 0-7，0 和 7 都表示周日。
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+
+	s := &CronSchedule{}
+	bounds := []struct {
+		name     string
+		min, max int
+		target   *uint64
+	}{
+		{"minute", 0, 59, &s.minute},
+		{"hour", 0, 23, &s.hour},
+		{"day of month", 1, 31, &s.dom},
+		{"month", 1, 12, &s.month},
+		{"day of week", 0, 7, &s.dow},
+	}
+	for i, b := range bounds {
+		bits, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", b.name, fields[i], err)
+		}
+		*b.target = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+	return s, nil
+}
+
+// parseCronField 解析一段表达式为位集合
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		lo, hi, step := min, max, 1
+		rangePart := part
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			step, rangePart = n, part[:i]
+		}
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max // 5/15 表示从 5 开始每 15 个
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range %d-%d", min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next 返回 t 之后（不含 t 所在的分钟）第一个满足表达式的时间，五年内没有时返回零值
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// MinInterval 返回表达式相邻两次触发之间的最短间隔。按分钟集合计算同一小时内的间隔，
+// 存在相邻的两个小时（包括 23 点到 0 点）时再计入跨小时的间隔；只在日期上有限制时结果偏保守。
+func (s *CronSchedule) MinInterval() time.Duration {
+	var minutes []int
+	for m := 0; m < 60; m++ {
+		if s.minute&(1<<uint(m)) != 0 {
+			minutes = append(minutes, m)
+		}
+	}
+	gap := 24 * 60
+	for i := 1; i < len(minutes); i++ {
+		if d := minutes[i] - minutes[i-1]; d < gap {
+			gap = d
+		}
+	}
+	for h := 0; h < 24; h++ {
+		if s.hour&(1<<uint(h)) != 0 && s.hour&(1<<uint((h+1)%24)) != 0 {
+			if d := 60 - minutes[len(minutes)-1] + minutes[0]; d < gap {
+				gap = d
+			}
+			break
+		}
+	}
+	return time.Duration(gap) * time.Minute
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// TimeWindow 表示每天的一个时间段，如 23:00-07:00，可以跨越零点
+type TimeWindow struct {
+	start, end int // 距零点的分钟数
+}
+
+// ParseTimeWindow 解析 HH:MM-HH:MM 形式的时间段
+func ParseTimeWindow(s string) (*TimeWindow, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("time window must be HH:MM-HH:MM, got %q", s)
+	}
+	var minutes [2]int
+	for i, p := range parts {
+		clock, err := time.Parse("15:04", strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q in window %q", p, s)
+		}
+		minutes[i] = clock.Hour()*60 + clock.Minute()
+	}
+	if minutes[0] == minutes[1] {
+		return nil, fmt.Errorf("time window %q is empty", s)
+	}
+	return &TimeWindow{start: minutes[0], end: minutes[1]}, nil
+}
+
+// Contains 判断 t 是否在时间段内（含开始，不含结束）
+func (w *TimeWindow) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return m >= w.start && m < w.end
+	}
+	return m >= w.start || m < w.end
+}
+
+// End 返回 t 之后时间段的下一个结束时间
+func (w *TimeWindow) End(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), w.end/60, w.end%60, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// String 返回 HH:MM-HH:MM 形式
+func (w *TimeWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.start/60, w.start%60, w.end/60, w.end%60)
+}
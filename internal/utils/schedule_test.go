@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2026, 3, 14, 10, 17, 30, 0, time.Local) // 周六
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 30, 0, 0, time.Local)},
+		{"0 9-18/3 * * *", time.Date(2026, 3, 14, 12, 0, 0, 0, time.Local)},
+		{"30 8 * * 1-5", time.Date(2026, 3, 16, 8, 30, 0, 0, time.Local)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, time.Local)},
+		{"5,20 10 * * *", time.Date(2026, 3, 14, 10, 20, 0, 0, time.Local)},
+		{"@daily", time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local)},
+		// 日和周都有限制时满足其一即可
+		{"0 0 20 * 1", time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("%q: next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("expected %q to be invalid", expr)
+		}
+	}
+}
+
+func TestCronMinInterval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want time.Duration
+	}{
+		{"* * * * *", time.Minute},
+		{"*/5 * * * *", 5 * time.Minute},
+		{"0,58 * * * *", 2 * time.Minute},
+		{"0,58 */2 * * *", 58 * time.Minute}, // 相邻两小时不会同时触发，不计跨小时间隔
+		{"0 * * * *", time.Hour},
+		{"30 8 * * 1-5", 24 * time.Hour},
+		{"0-2 9 * * *", time.Minute},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := s.MinInterval(); got != tt.want {
+			t.Errorf("%q: min interval = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	w, err := ParseTimeWindow("23:30-07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 14, h, m, 0, 0, time.Local) }
+	for _, tt := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(23, 30), true}, {at(2, 0), true}, {at(6, 59), true}, {at(7, 0), false}, {at(12, 0), false}, {at(23, 29), false},
+	} {
+		if got := w.Contains(tt.t); got != tt.want {
+			t.Errorf("Contains(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+	if got, want := w.End(at(23, 45)), time.Date(2026, 3, 15, 7, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("End = %v, want %v", got, want)
+	}
+	if got, want := w.End(at(3, 0)), at(7, 0); !got.Equal(want) {
+		t.Errorf("End = %v, want %v", got, want)
+	}
+
+	for _, s := range []string{"", "23:00", "25:00-01:00", "08:00-08:00"} {
+		if _, err := ParseTimeWindow(s); err == nil {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/coder/websocket"
 )
@@ -11,6 +12,7 @@ import (
 // Handler WebSocket HTTP 处理器
 type Handler struct {
 	hub            *Hub
+	mu             sync.RWMutex
 	allowedOrigins []string
 	secretToken    string
 }
@@ -24,10 +26,21 @@ func NewHandler(hub *Hub, allowedOrigins []string, secretToken string) *Handler
 	}
 }
 
+// SetAllowedOrigins 热更新允许连接的 Origin 列表
+func (h *Handler) SetAllowedOrigins(origins []string) {
+	h.mu.Lock()
+	h.allowedOrigins = origins
+	h.mu.Unlock()
+}
+
 // ServeHTTP 处理 WebSocket 连接请求
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	allowedOrigins := h.allowedOrigins
+	h.mu.RUnlock()
+
 	origin := r.Header.Get("Origin")
-	if !isOriginAllowed(origin, h.allowedOrigins) {
+	if !isOriginAllowed(origin, allowedOrigins) {
 		http.Error(w, "forbidden origin", http.StatusForbidden)
 		return
 	}
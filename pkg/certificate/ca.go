@@ -0,0 +1,255 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// LegacyCertName 旧版本内置的公共根证书名称，所有安装共用同一私钥，需要移除
+	LegacyCertName = "SunnyNet"
+	// ExportFileName 供用户下载或手动安装的根证书文件名
+	ExportFileName = "WXChannelRootCA.cer"
+
+	caOrganization = "WXChannel"
+	caCertFile     = "ca.crt"
+	caKeyFile      = "ca.key"
+	caValidity     = 10 * 365 * 24 * time.Hour
+)
+
+// CA 本机生成的根证书及私钥
+type CA struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// CAInfo 根证书摘要信息
+type CAInfo struct {
+	Name            string    `json:"name"`
+	Fingerprint     string    `json:"fingerprint"`      // SHA-256
+	FingerprintSHA1 string    `json:"fingerprint_sha1"` // Windows 证书管理器显示的指纹
+	NotBefore       time.Time `json:"not_before"`
+	NotAfter        time.Time `json:"not_after"`
+	ExpiresInDays   int       `json:"expires_in_days"`
+	CertPath        string    `json:"cert_path,omitempty"`
+}
+
+// DefaultCADir 根证书存放目录（用户配置目录下的 wx_channel/ca）
+func DefaultCADir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "wx_channel", "ca")
+}
+
+// GenerateCA 生成新的根证书，CN 带随机后缀以区分不同安装
+func GenerateCA() (*CA, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("生成私钥失败: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("生成序列号失败: %v", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         fmt.Sprintf("%s Root CA %s", caOrganization, hex.EncodeToString(suffix)),
+			Organization:       []string{caOrganization},
+			OrganizationalUnit: []string{caOrganization},
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("生成根证书失败: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{
+		Cert:    cert,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}, nil
+}
+
+// LoadCA 从目录读取根证书，不存在时返回 os.ErrNotExist
+func LoadCA(dir string) (*CA, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, caCertFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, caKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("根证书文件格式错误: %s", filepath.Join(dir, caCertFile))
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析根证书失败: %v", err)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, fmt.Errorf("私钥文件格式错误: %s", filepath.Join(dir, caKeyFile))
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %v", err)
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, errors.New("根证书与私钥不匹配")
+	}
+	return &CA{Cert: cert, CertPEM: certPEM, KeyPEM: keyPEM}, nil
+}
+
+// Save 写入目录，私钥仅当前用户可读
+func (ca *CA) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("创建证书目录失败: %v", err)
+	}
+	// 先写临时文件再重命名，避免中断时留下不完整的私钥
+	write := func(name string, data []byte, perm os.FileMode) error {
+		tmp := filepath.Join(dir, name+".tmp")
+		if err := os.WriteFile(tmp, data, perm); err != nil {
+			return err
+		}
+		if err := os.Chmod(tmp, perm); err != nil {
+			return err
+		}
+		return os.Rename(tmp, filepath.Join(dir, name))
+	}
+	if err := write(caKeyFile, ca.KeyPEM, 0600); err != nil {
+		return fmt.Errorf("保存私钥失败: %v", err)
+	}
+	if err := write(caCertFile, ca.CertPEM, 0644); err != nil {
+		return fmt.Errorf("保存根证书失败: %v", err)
+	}
+	return nil
+}
+
+// LoadOrCreateCA 读取本机根证书，首次运行时生成并保存
+// 返回的 created 表示是否新生成
+func LoadOrCreateCA(dir string) (ca *CA, created bool, err error) {
+	ca, err = LoadCA(dir)
+	if err == nil {
+		return ca, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, false, err
+	}
+	ca, err = GenerateCA()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := ca.Save(dir); err != nil {
+		return nil, false, err
+	}
+	return ca, true, nil
+}
+
+// CertPath 根证书文件路径
+func CertPath(dir string) string {
+	return filepath.Join(dir, caCertFile)
+}
+
+// KeyPath 私钥文件路径
+func KeyPath(dir string) string {
+	return filepath.Join(dir, caKeyFile)
+}
+
+// Name 证书名称（CN），用于检查、安装和卸载
+func (ca *CA) Name() string {
+	return ca.Cert.Subject.CommonName
+}
+
+// Fingerprint SHA-256 指纹，格式如 AB:CD:...
+func (ca *CA) Fingerprint() string {
+	sum := sha256.Sum256(ca.Cert.Raw)
+	return formatFingerprint(sum[:])
+}
+
+// Info 返回证书摘要
+func (ca *CA) Info() CAInfo {
+	sum := sha1.Sum(ca.Cert.Raw)
+	return CAInfo{
+		Name:            ca.Name(),
+		Fingerprint:     ca.Fingerprint(),
+		FingerprintSHA1: formatFingerprint(sum[:]),
+		NotBefore:       ca.Cert.NotBefore,
+		NotAfter:        ca.Cert.NotAfter,
+		ExpiresInDays:   int(time.Until(ca.Cert.NotAfter).Hours() / 24),
+	}
+}
+
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// RotateCA 生成新的根证书替换旧证书：安装新证书、覆盖保存到目录，再从系统中移除旧证书
+// 新证书安装或保存失败时不做任何修改（已安装的新证书会被移除）；旧私钥随之删除，不再保留
+func RotateCA(dir string) (newCA *CA, oldCA *CA, err error) {
+	oldCA, err = LoadCA(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	newCA, err = GenerateCA()
+	if err != nil {
+		return nil, oldCA, err
+	}
+	if err := InstallCertificate(newCA.CertPEM); err != nil {
+		return nil, oldCA, fmt.Errorf("安装新根证书失败，未做任何修改: %v", err)
+	}
+
+	if err := newCA.Save(dir); err != nil {
+		// 私钥未能保存，新证书无法使用，不能留在系统信任列表中
+		if rmErr := RemoveCertificate(newCA.Name()); rmErr != nil {
+			return nil, oldCA, fmt.Errorf("保存新根证书失败: %v；移除已安装的新证书 %s 也失败，请手动移除: %v", err, newCA.Name(), rmErr)
+		}
+		return nil, oldCA, fmt.Errorf("保存新根证书失败，未做任何修改: %v", err)
+	}
+
+	if oldCA != nil {
+		if installed, _ := CheckCertificate(oldCA.Name()); installed {
+			if err := RemoveCertificate(oldCA.Name()); err != nil {
+				return newCA, oldCA, fmt.Errorf("新根证书已启用，但移除旧证书 %s 失败: %v", oldCA.Name(), err)
+			}
+		}
+	}
+	return newCA, oldCA, nil
+}
@@ -0,0 +1,57 @@
+package certificate
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoadOrCreateCA(t *testing.T) {
+	dir := t.TempDir() + "/ca"
+
+	ca, created, err := LoadOrCreateCA(dir)
+	if err != nil || !created {
+		t.Fatalf("expected new CA, got created=%v err=%v", created, err)
+	}
+	if !ca.Cert.IsCA || !strings.HasPrefix(ca.Name(), caOrganization+" Root CA ") {
+		t.Errorf("unexpected certificate: CN=%q IsCA=%v", ca.Name(), ca.Cert.IsCA)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(KeyPath(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("expected key permission 0600, got %o", perm)
+		}
+	}
+
+	again, created, err := LoadOrCreateCA(dir)
+	if err != nil || created {
+		t.Fatalf("expected existing CA, got created=%v err=%v", created, err)
+	}
+	if again.Fingerprint() != ca.Fingerprint() {
+		t.Errorf("fingerprint changed after reload: %s != %s", again.Fingerprint(), ca.Fingerprint())
+	}
+	if len(strings.Split(ca.Fingerprint(), ":")) != 32 {
+		t.Errorf("unexpected fingerprint format: %s", ca.Fingerprint())
+	}
+
+	// 每次生成的证书都不同
+	other, err := GenerateCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Name() == ca.Name() || other.Fingerprint() == ca.Fingerprint() {
+		t.Error("expected a unique CA per generation")
+	}
+
+	// 证书与私钥不匹配时拒绝加载
+	if err := os.WriteFile(KeyPath(dir), other.KeyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCA(dir); err == nil {
+		t.Error("expected mismatched key to be rejected")
+	}
+}
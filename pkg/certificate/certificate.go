@@ -26,38 +26,54 @@ type Certificate struct {
 func fetchCertificatesInWindows() ([]Certificate, error) {
 	var certificates []Certificate
 
-	// 尝试从 LocalMachine 获取证书
-	cmd := "Get-ChildItem Cert:\\LocalMachine\\Root | Where-Object {$_.Subject -like '*SunnyNet*' -or $_.Subject -like '*Sunny*'}"
-	ps := exec.Command("powershell.exe", "-Command", cmd)
-	output, err := ps.CombinedOutput()
-	if err == nil && len(output) > 0 {
-		// 如果找到证书，添加一个示例证书
-		certificates = append(certificates, Certificate{
-			Thumbprint: "SunnyNet",
-			Subject: Subject{
-				CN: "SunnyNet",
-				O:  "SunnyNet",
-			},
-		})
-	}
-
-	// 尝试从 CurrentUser 获取证书
-	cmd = "Get-ChildItem Cert:\\CurrentUser\\Root | Where-Object {$_.Subject -like '*SunnyNet*' -or $_.Subject -like '*Sunny*'}"
-	ps = exec.Command("powershell.exe", "-Command", cmd)
-	output, err = ps.CombinedOutput()
-	if err == nil && len(output) > 0 {
-		// 如果找到证书，添加一个示例证书
-		certificates = append(certificates, Certificate{
-			Thumbprint: "SunnyNet",
-			Subject: Subject{
-				CN: "SunnyNet",
-				O:  "SunnyNet",
-			},
-		})
+	// 依次从 LocalMachine 和 CurrentUser 获取本程序相关的根证书（旧版 SunnyNet 及本机生成的证书）
+	for _, store := range []string{"LocalMachine", "CurrentUser"} {
+		cmd := fmt.Sprintf("Get-ChildItem Cert:\\%s\\Root | Where-Object {$_.Subject -like '*Sunny*' -or $_.Subject -like '*%s*'} | ForEach-Object { $_.Thumbprint + '|' + $_.Subject }", store, caOrganization)
+		ps := exec.Command("powershell.exe", "-Command", cmd)
+		output, err := ps.CombinedOutput()
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			thumbprint, subject, ok := strings.Cut(strings.TrimSpace(line), "|")
+			if !ok {
+				continue
+			}
+			certificates = append(certificates, Certificate{
+				Thumbprint: thumbprint,
+				Subject:    parseWindowsSubject(subject),
+			})
+		}
 	}
 
 	return certificates, nil
 }
+
+// parseWindowsSubject 解析 "CN=xxx, O=xxx, C=CN" 形式的证书主题
+func parseWindowsSubject(subject string) Subject {
+	var result Subject
+	for _, part := range strings.Split(subject, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "CN":
+			result.CN = value
+		case "OU":
+			result.OU = value
+		case "O":
+			result.O = value
+		case "L":
+			result.L = value
+		case "S":
+			result.S = value
+		case "C":
+			result.C = value
+		}
+	}
+	return result
+}
 func fetchCertificatesInMacOS() ([]Certificate, error) {
 	cmd := exec.Command("security", "find-certificate", "-a")
 	output, err2 := cmd.Output()
@@ -104,7 +120,7 @@ func fetchCertificates() ([]Certificate, error) {
 	os_env := runtime.GOOS
 	switch os_env {
 	case "linux":
-		fmt.Println("Running on Linux")
+		return fetchCertificatesInLinux()
 	case "darwin":
 		return fetchCertificatesInMacOS()
 	case "windows":
@@ -126,12 +142,12 @@ func CheckCertificate(cert_name string) (bool, error) {
 		if cert.Subject.CN == cert_name {
 			return true, nil
 		}
-		// 模糊匹配（包含关键字）
-		if strings.Contains(cert.Subject.CN, cert_name) || strings.Contains(cert_name, cert.Subject.CN) {
+		// 模糊匹配（包含关键字），空 CN 会匹配任意名称，需排除
+		if cert.Subject.CN != "" && (strings.Contains(cert.Subject.CN, cert_name) || strings.Contains(cert_name, cert.Subject.CN)) {
 			return true, nil
 		}
 		// 检查组织名称
-		if cert.Subject.O == cert_name || strings.Contains(cert.Subject.O, cert_name) {
+		if cert.Subject.O != "" && (cert.Subject.O == cert_name || strings.Contains(cert.Subject.O, cert_name)) {
 			return true, nil
 		}
 	}
@@ -170,7 +186,7 @@ func RemoveCertificate(cert_name string) error {
 	os_env := runtime.GOOS
 	switch os_env {
 	case "linux":
-		return errors.New("Linux 系统暂不支持证书卸载功能")
+		return removeCertificateInLinux(cert_name)
 	case "darwin":
 		return removeCertificateInMacOS(cert_name)
 	case "windows":
@@ -180,7 +196,7 @@ func RemoveCertificate(cert_name string) error {
 	}
 }
 func installCertificateInWindows(cert_data []byte) error {
-	cert_file, err := os.CreateTemp("", "SunnyRoot.cer")
+	cert_file, err := os.CreateTemp("", "WXChannelRootCA-*.cer")
 	if err != nil {
 		return errors.New(fmt.Sprintf("没有创建证书的权限，%v\n", err.Error()))
 	}
@@ -212,7 +228,7 @@ func installCertificateInWindows(cert_data []byte) error {
 	return nil
 }
 func installCertificateInMacOS(cert_data []byte) error {
-	cert_file, err := os.CreateTemp("", "SunnyRoot.cer")
+	cert_file, err := os.CreateTemp("", "WXChannelRootCA-*.cer")
 	if err != nil {
 		return errors.New(fmt.Sprintf("没有创建证书的权限，%v\n", err.Error()))
 	}
@@ -236,7 +252,7 @@ func InstallCertificate(cert_data []byte) error {
 	os_env := runtime.GOOS
 	switch os_env {
 	case "linux":
-		fmt.Println("Running on Linux")
+		return installCertificateInLinux(cert_data)
 	case "darwin":
 		return installCertificateInMacOS(cert_data)
 	case "windows":
@@ -0,0 +1,477 @@
+package certificate
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// linuxStores Linux 下的证书存储位置
+// 所有路径和外部命令都可替换，便于在临时目录中测试
+type linuxStores struct {
+	// Debian/Ubuntu 系：放入 .crt 后执行 update-ca-certificates
+	caCertDir string
+	// p11-kit 信任锚目录（Fedora/RHEL、Arch 等），放入后执行 update-ca-trust 或 trust extract-compat
+	anchorDirs []string
+	// 系统合并后的证书包，只用于检查
+	bundleFiles []string
+	// NSS 数据库目录（Chrome 的 ~/.pki/nssdb、Firefox profile），需要 certutil
+	nssDirs []string
+	// 本机根证书文件，安装失败时提示用户手动安装
+	caCertPath string
+
+	run      func(name string, args ...string) ([]byte, error)
+	lookPath func(file string) (string, error)
+}
+
+func defaultLinuxStores() *linuxStores {
+	return &linuxStores{
+		caCertDir: "/usr/local/share/ca-certificates",
+		anchorDirs: []string{
+			"/etc/pki/ca-trust/source/anchors",
+			"/etc/ca-certificates/trust-source/anchors",
+		},
+		bundleFiles: []string{
+			"/etc/ssl/certs/ca-certificates.crt",
+			"/etc/pki/tls/certs/ca-bundle.crt",
+			"/etc/ssl/ca-bundle.pem",
+		},
+		nssDirs:    findNSSDirs(linuxHomeDir()),
+		caCertPath: CertPath(DefaultCADir()),
+		run: func(name string, args ...string) ([]byte, error) {
+			return exec.Command(name, args...).CombinedOutput()
+		},
+		lookPath: exec.LookPath,
+	}
+}
+
+// linuxHomeDir 通过 sudo 运行时返回原用户的主目录，这样 NSS 证书装给实际使用浏览器的用户
+func linuxHomeDir() string {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		if u, err := user.Lookup(sudoUser); err == nil {
+			return u.HomeDir
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+// findNSSDirs 查找已存在的 NSS 数据库（含 cert9.db）
+func findNSSDirs(home string) []string {
+	if home == "" {
+		return nil
+	}
+	candidates := []string{filepath.Join(home, ".pki", "nssdb")}
+	for _, pattern := range []string{
+		filepath.Join(home, ".mozilla", "firefox", "*"),
+		filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox", "*"),
+		filepath.Join(home, ".var", "app", "org.mozilla.firefox", ".mozilla", "firefox", "*"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		candidates = append(candidates, matches...)
+	}
+
+	var dirs []string
+	for _, dir := range candidates {
+		if _, err := os.Stat(filepath.Join(dir, "cert9.db")); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// parseCertificate 同时支持 PEM 和 DER 格式，返回证书和 PEM 编码
+func parseCertificate(certData []byte) (*x509.Certificate, []byte, error) {
+	der := certData
+	if block, _ := pem.Decode(certData); block != nil {
+		der = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析证书失败，%v", err)
+	}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// certFileBase 证书在系统目录中的文件名（不含扩展名），同时用作 NSS 昵称
+func certFileBase(cert *x509.Certificate) string {
+	name := cert.Subject.CommonName
+	if name == "" && len(cert.Subject.Organization) > 0 {
+		name = cert.Subject.Organization[0]
+	}
+	return certNameBase(name)
+}
+
+// certNameBase 将证书名称转换为文件名和 NSS 昵称中使用的形式
+func certNameBase(name string) string {
+	name = unsafeFileChars.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "wx_channel_root"
+	}
+	return name
+}
+
+func toCertificate(cert *x509.Certificate) Certificate {
+	sum := sha1.Sum(cert.Raw)
+	first := func(values []string) string {
+		if len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	return Certificate{
+		Thumbprint: strings.ToUpper(hex.EncodeToString(sum[:])),
+		Subject: Subject{
+			CN: cert.Subject.CommonName,
+			OU: first(cert.Subject.OrganizationalUnit),
+			O:  first(cert.Subject.Organization),
+			L:  first(cert.Subject.Locality),
+			S:  first(cert.Subject.Province),
+			C:  first(cert.Subject.Country),
+		},
+	}
+}
+
+// readPEMFile 读取文件中的所有证书
+func readPEMFile(path string) []*x509.Certificate {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return parsePEMCerts(data)
+}
+
+// parsePEMCerts 解析 PEM 数据中的所有证书，不是 PEM 时按单个 DER 证书解析
+func parsePEMCerts(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		// 可能是 DER 格式的单个证书
+		if cert, err := x509.ParseCertificate(data); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+func (s *linuxStores) hasCommand(name string) bool {
+	_, err := s.lookPath(name)
+	return err == nil
+}
+
+func dirExists(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// refreshSystemStores 重新生成系统证书包
+func (s *linuxStores) refreshSystemStores(fresh bool) []string {
+	var errs []string
+	if dirExists(s.caCertDir) && s.hasCommand("update-ca-certificates") {
+		args := []string{}
+		if fresh {
+			args = append(args, "--fresh")
+		}
+		if output, err := s.run("update-ca-certificates", args...); err != nil {
+			errs = append(errs, fmt.Sprintf("update-ca-certificates: %v %s", err, strings.TrimSpace(string(output))))
+		}
+	}
+
+	anchorsUsed := false
+	for _, dir := range s.anchorDirs {
+		if dirExists(dir) {
+			anchorsUsed = true
+		}
+	}
+	if anchorsUsed {
+		var name string
+		var args []string
+		switch {
+		case s.hasCommand("update-ca-trust"):
+			name, args = "update-ca-trust", []string{"extract"}
+		case s.hasCommand("trust"):
+			name, args = "trust", []string{"extract-compat"}
+		}
+		if name != "" {
+			if output, err := s.run(name, args...); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v %s", name, err, strings.TrimSpace(string(output))))
+			}
+		}
+	}
+	return errs
+}
+
+// install 将证书安装到所有可用的系统存储和 NSS 数据库
+// 至少一个存储安装成功即视为成功
+func (s *linuxStores) install(certData []byte) error {
+	cert, pemData, err := parseCertificate(certData)
+	if err != nil {
+		return err
+	}
+	base := certFileBase(cert)
+
+	var installed, errs []string
+	systemChanged := false
+
+	// 1. Debian/Ubuntu: /usr/local/share/ca-certificates/*.crt
+	if s.hasCommand("update-ca-certificates") || dirExists(s.caCertDir) {
+		if err := os.MkdirAll(s.caCertDir, 0755); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.caCertDir, err))
+		} else if err := os.WriteFile(filepath.Join(s.caCertDir, base+".crt"), pemData, 0644); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.caCertDir, err))
+		} else {
+			installed = append(installed, s.caCertDir)
+			systemChanged = true
+		}
+	}
+
+	// 2. p11-kit 信任锚
+	for _, dir := range s.anchorDirs {
+		if !dirExists(dir) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, base+".pem"), pemData, 0644); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", dir, err))
+			continue
+		}
+		installed = append(installed, dir)
+		systemChanged = true
+	}
+
+	if systemChanged {
+		errs = append(errs, s.refreshSystemStores(false)...)
+	}
+
+	// 3. NSS 数据库（浏览器不读取系统证书时需要）
+	if len(s.nssDirs) > 0 {
+		if !s.hasCommand("certutil") {
+			errs = append(errs, "未找到 certutil，跳过浏览器 NSS 证书库（可安装 libnss3-tools 或 nss-tools 后重试）")
+		} else {
+			tmp, err := os.CreateTemp("", base+"-*.pem")
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("创建临时证书文件失败，%v", err))
+			} else {
+				defer os.Remove(tmp.Name())
+				_, _ = tmp.Write(pemData)
+				_ = tmp.Close()
+				for _, dir := range s.nssDirs {
+					output, err := s.run("certutil", "-d", "sql:"+dir, "-A", "-t", "C,,", "-n", base, "-i", tmp.Name())
+					if err != nil {
+						errs = append(errs, fmt.Sprintf("certutil %s: %v %s", dir, err, strings.TrimSpace(string(output))))
+						continue
+					}
+					installed = append(installed, dir)
+				}
+			}
+		}
+	}
+
+	if len(installed) == 0 {
+		if len(errs) == 0 {
+			errs = append(errs, "未找到可用的证书存储")
+		}
+		return fmt.Errorf("证书安装失败！\n\n错误详情：\n  %s\n\n解决方案：\n1. 使用 sudo 运行程序以写入系统证书目录\n2. 或者手动安装证书：\n   sudo cp '%s' /usr/local/share/ca-certificates/%s.crt && sudo update-ca-certificates",
+			strings.Join(errs, "\n  "), s.manualCertFile(cert, pemData), base)
+	}
+	if len(errs) > 0 {
+		fmt.Printf("证书已安装到: %s\n部分存储未安装:\n  %s\n", strings.Join(installed, ", "), strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// manualCertFile 返回供手动安装的证书文件：安装的是本机根证书时为其导出路径，
+// 否则将证书写入临时目录
+func (s *linuxStores) manualCertFile(cert *x509.Certificate, pemData []byte) string {
+	for _, c := range readPEMFile(s.caCertPath) {
+		if c.Equal(cert) {
+			return s.caCertPath
+		}
+	}
+	path := filepath.Join(os.TempDir(), certFileBase(cert)+".crt")
+	if err := os.WriteFile(path, pemData, 0644); err != nil {
+		return s.caCertPath
+	}
+	return path
+}
+
+// systemCertFiles 返回系统证书目录中由本程序（或用户）放置的证书文件
+func (s *linuxStores) systemCertFiles() []string {
+	var files []string
+	for _, dir := range append([]string{s.caCertDir}, s.anchorDirs...) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	return files
+}
+
+// nssNicknames 列出 NSS 数据库中的证书昵称
+func (s *linuxStores) nssNicknames(dir string) []string {
+	output, err := s.run("certutil", "-d", "sql:"+dir, "-L")
+	if err != nil {
+		return nil
+	}
+	// 输出格式：昵称 + 空白 + 信任属性（如 "SunnyNet     C,,"）
+	trustAttrs := regexp.MustCompile(`\s+[a-zA-Z]*,[a-zA-Z]*,[a-zA-Z]*\s*$`)
+	var names []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if !trustAttrs.MatchString(line) {
+			continue
+		}
+		name := strings.TrimSpace(trustAttrs.ReplaceAllString(line, ""))
+		if name != "" && !strings.HasPrefix(name, "Certificate Nickname") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fetch 列出系统证书目录、证书包和 NSS 数据库中的证书
+func (s *linuxStores) fetch() ([]Certificate, error) {
+	seen := make(map[string]bool)
+	var certificates []Certificate
+	add := func(c Certificate) {
+		key := c.Thumbprint + "|" + c.Subject.CN
+		if !seen[key] {
+			seen[key] = true
+			certificates = append(certificates, c)
+		}
+	}
+
+	for _, file := range append(s.systemCertFiles(), s.bundleFiles...) {
+		for _, cert := range readPEMFile(file) {
+			add(toCertificate(cert))
+		}
+	}
+
+	if len(s.nssDirs) > 0 && s.hasCommand("certutil") {
+		for _, dir := range s.nssDirs {
+			for _, name := range s.nssNicknames(dir) {
+				// 昵称是安装时转换过的名称（空格替换为 _），按昵称导出证书以取得真实的主题
+				output, err := s.run("certutil", "-d", "sql:"+dir, "-L", "-n", name, "-a")
+				certs := parsePEMCerts(output)
+				if err != nil || len(certs) == 0 {
+					add(Certificate{Thumbprint: "nss:" + dir, Subject: Subject{CN: name}})
+					continue
+				}
+				for _, cert := range certs {
+					c := toCertificate(cert)
+					c.Thumbprint = "nss:" + dir
+					add(c)
+				}
+			}
+		}
+	}
+	return certificates, nil
+}
+
+// certMatches 按名称精确匹配证书的 CN，没有 CN 时匹配组织名称；
+// 不做模糊匹配，避免删除名称中恰好包含该名称的其他根证书
+func certMatches(cert *x509.Certificate, certName string) bool {
+	if certName == "" {
+		return false
+	}
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName == certName
+	}
+	for _, o := range cert.Subject.Organization {
+		if o == certName {
+			return true
+		}
+	}
+	return false
+}
+
+// remove 从系统证书目录和 NSS 数据库中删除名称完全一致的证书，NSS 昵称按安装时的形式比较
+func (s *linuxStores) remove(certName string) error {
+	var removed, errs []string
+	systemChanged := false
+
+	for _, file := range s.systemCertFiles() {
+		matched := false
+		for _, cert := range readPEMFile(file) {
+			if certMatches(cert, certName) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		removed = append(removed, file)
+		systemChanged = true
+	}
+
+	if systemChanged {
+		errs = append(errs, s.refreshSystemStores(true)...)
+	}
+
+	if len(s.nssDirs) > 0 && s.hasCommand("certutil") && certName != "" {
+		nickname := certNameBase(certName)
+		for _, dir := range s.nssDirs {
+			for _, name := range s.nssNicknames(dir) {
+				if name != certName && name != nickname {
+					continue
+				}
+				if output, err := s.run("certutil", "-d", "sql:"+dir, "-D", "-n", name); err != nil {
+					errs = append(errs, fmt.Sprintf("certutil %s: %v %s", dir, err, strings.TrimSpace(string(output))))
+					continue
+				}
+				removed = append(removed, dir+":"+name)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("删除证书时发生错误（可尝试使用 sudo 运行）：\n  %s", strings.Join(errs, "\n  "))
+	}
+	if len(removed) == 0 {
+		return errors.New("未在系统证书目录或 NSS 数据库中找到可删除的证书，可能是通过其他方式安装的")
+	}
+	return nil
+}
+
+func fetchCertificatesInLinux() ([]Certificate, error) {
+	return defaultLinuxStores().fetch()
+}
+
+func installCertificateInLinux(cert_data []byte) error {
+	return defaultLinuxStores().install(cert_data)
+}
+
+func removeCertificateInLinux(cert_name string) error {
+	return defaultLinuxStores().remove(cert_name)
+}
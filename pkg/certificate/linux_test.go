@@ -0,0 +1,305 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCert(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn, Organization: []string{cn}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("生成证书失败: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// fakeCommands 模拟外部命令，certutil 以内存中的昵称表模拟 NSS 数据库
+type fakeCommands struct {
+	available map[string]bool
+	calls     []string
+	nss       map[string]map[string]bool
+	nssPEM    map[string][]byte // 昵称对应的证书内容，用于 -L -n -a
+}
+
+func (f *fakeCommands) lookPath(name string) (string, error) {
+	if f.available[name] {
+		return "/usr/bin/" + name, nil
+	}
+	return "", errors.New("not found")
+}
+
+func (f *fakeCommands) run(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	if name != "certutil" {
+		return nil, nil
+	}
+	dir := strings.TrimPrefix(args[1], "sql:")
+	if f.nss[dir] == nil {
+		f.nss[dir] = map[string]bool{}
+	}
+	switch args[2] {
+	case "-A":
+		f.nss[dir][args[6]] = true
+		if f.nssPEM == nil {
+			f.nssPEM = map[string][]byte{}
+		}
+		f.nssPEM[args[6]], _ = os.ReadFile(args[8])
+	case "-D":
+		delete(f.nss[dir], args[4])
+	case "-L":
+		if len(args) > 3 {
+			if !f.nss[dir][args[4]] {
+				return nil, errors.New("not found")
+			}
+			return f.nssPEM[args[4]], nil
+		}
+		out := "Certificate Nickname                                         Trust Attributes\n\n"
+		for name := range f.nss[dir] {
+			out += name + "                                                     C,,\n"
+		}
+		return []byte(out), nil
+	}
+	return nil, nil
+}
+
+func newTestStores(t *testing.T, cmds *fakeCommands) *linuxStores {
+	t.Helper()
+	root := t.TempDir()
+	anchors := filepath.Join(root, "anchors")
+	nssdb := filepath.Join(root, "home", ".pki", "nssdb")
+	for _, dir := range []string{anchors, nssdb} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(nssdb, "cert9.db"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &linuxStores{
+		caCertDir:   filepath.Join(root, "ca-certificates"),
+		anchorDirs:  []string{anchors, filepath.Join(root, "missing-anchors")},
+		bundleFiles: []string{filepath.Join(root, "ca-bundle.crt")},
+		nssDirs:     findNSSDirs(filepath.Join(root, "home")),
+		caCertPath:  filepath.Join(root, "ca", caCertFile),
+		run:         cmds.run,
+		lookPath:    cmds.lookPath,
+	}
+}
+
+func TestLinuxStores_InstallCheckRemove(t *testing.T) {
+	cmds := &fakeCommands{
+		available: map[string]bool{"update-ca-certificates": true, "update-ca-trust": true, "certutil": true},
+		nss:       map[string]map[string]bool{},
+	}
+	s := newTestStores(t, cmds)
+	certData := newTestCert(t, "SunnyNet")
+
+	if err := s.install(certData); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	for _, path := range []string{
+		filepath.Join(s.caCertDir, "SunnyNet.crt"),
+		filepath.Join(s.anchorDirs[0], "SunnyNet.pem"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+	if !cmds.nss[s.nssDirs[0]]["SunnyNet"] {
+		t.Errorf("expected certificate in NSS db, calls: %v", cmds.calls)
+	}
+
+	certs, err := s.fetch()
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	found := 0
+	for _, c := range certs {
+		if c.Subject.CN == "SunnyNet" {
+			found++
+		}
+	}
+	// 系统目录中的两份文件内容相同（按指纹去重）+ NSS 中的一条
+	if found != 2 {
+		t.Errorf("expected 2 SunnyNet entries, got %d: %+v", found, certs)
+	}
+
+	if err := s.remove("SunnyNet"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	certs, _ = s.fetch()
+	if len(certs) != 0 {
+		t.Errorf("expected no certificates after remove, got %+v", certs)
+	}
+
+	joined := strings.Join(cmds.calls, "\n")
+	for _, want := range []string{"update-ca-certificates", "update-ca-certificates --fresh", "update-ca-trust extract"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected command %q, calls: %v", want, cmds.calls)
+		}
+	}
+}
+
+func TestLinuxStores_InstallWithoutCertutil(t *testing.T) {
+	cmds := &fakeCommands{available: map[string]bool{}, nss: map[string]map[string]bool{}}
+	s := newTestStores(t, cmds)
+
+	// 没有 update-ca-certificates 且目录不存在时只写入 p11-kit 信任锚
+	if err := s.install(newTestCert(t, "SunnyNet")); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := os.Stat(s.caCertDir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be untouched", s.caCertDir)
+	}
+	if _, err := os.Stat(filepath.Join(s.anchorDirs[0], "SunnyNet.pem")); err != nil {
+		t.Errorf("expected anchor file: %v", err)
+	}
+	for _, call := range cmds.calls {
+		if strings.HasPrefix(call, "certutil") {
+			t.Errorf("certutil should not run when unavailable: %s", call)
+		}
+	}
+}
+
+func TestLinuxStores_BundleCheckAndNoStores(t *testing.T) {
+	cmds := &fakeCommands{available: map[string]bool{}, nss: map[string]map[string]bool{}}
+	s := newTestStores(t, cmds)
+	s.anchorDirs = nil
+	s.nssDirs = nil
+
+	if err := s.install(newTestCert(t, "SunnyNet")); err == nil {
+		t.Error("expected install to fail without any usable store")
+	}
+
+	bundle := append(newTestCert(t, "Other Root"), newTestCert(t, "SunnyNet")...)
+	if err := os.WriteFile(s.bundleFiles[0], bundle, 0644); err != nil {
+		t.Fatal(err)
+	}
+	certs, _ := s.fetch()
+	if len(certs) != 2 {
+		t.Fatalf("expected 2 certificates from bundle, got %d", len(certs))
+	}
+	if err := s.remove("SunnyNet"); err == nil {
+		t.Error("expected remove to report that the bundle-only certificate cannot be removed")
+	}
+}
+
+func TestLinuxStores_RemoveExactName(t *testing.T) {
+	cmds := &fakeCommands{
+		available: map[string]bool{"update-ca-certificates": true, "certutil": true},
+		nss:       map[string]map[string]bool{},
+	}
+	s := newTestStores(t, cmds)
+	for _, cn := range []string{"SunnyNet", "SunnyNet Helper", "WXChannel Root CA ab12"} {
+		if err := s.install(newTestCert(t, cn)); err != nil {
+			t.Fatalf("install %s: %v", cn, err)
+		}
+	}
+
+	// 名称中包含 SunnyNet 的其他根证书不受影响
+	if err := s.remove("SunnyNet"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := s.remove("WXChannel Root CA ab12"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	certs, _ := s.fetch()
+	var names []string
+	for _, c := range certs {
+		names = append(names, c.Subject.CN)
+	}
+	// 系统目录中的文件 + NSS 中的一条，NSS 条目报告证书的真实名称而不是昵称
+	if len(certs) != 2 || names[0] != "SunnyNet Helper" || names[1] != "SunnyNet Helper" {
+		t.Errorf("expected only SunnyNet Helper to remain, got %v", names)
+	}
+}
+
+func TestLinuxStores_ManualInstallHint(t *testing.T) {
+	cmds := &fakeCommands{available: map[string]bool{}, nss: map[string]map[string]bool{}}
+	s := newTestStores(t, cmds)
+	s.anchorDirs = nil
+	s.nssDirs = nil
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	// 本机根证书：提示其导出路径
+	caData := newTestCert(t, "WXChannel Root CA ab12")
+	if err := os.MkdirAll(filepath.Dir(s.caCertPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.caCertPath, caData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := s.install(caData)
+	if err == nil || !strings.Contains(err.Error(), "sudo cp '"+s.caCertPath+"' ") ||
+		!strings.Contains(err.Error(), "WXChannel_Root_CA_ab12.crt") {
+		t.Errorf("expected hint with CA path, got %v", err)
+	}
+
+	// 其他证书：写入临时目录后提示该文件
+	err = s.install(newTestCert(t, "Other Root"))
+	path := filepath.Join(tmp, "Other_Root.crt")
+	if err == nil || !strings.Contains(err.Error(), "sudo cp '"+path+"' ") {
+		t.Errorf("expected hint with temp path, got %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected %s to exist: %v", path, err)
+	}
+}
+
+func TestLinuxStores_FetchNSSOnlyNameWithSpaces(t *testing.T) {
+	cmds := &fakeCommands{available: map[string]bool{"certutil": true}, nss: map[string]map[string]bool{}}
+	s := newTestStores(t, cmds)
+	s.anchorDirs = nil
+
+	// 没有 sudo 时只能装入用户的 NSS 数据库，昵称中的空格被替换
+	const name = "WXChannel Root CA 1a2b3c4d"
+	if err := s.install(newTestCert(t, name)); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if !cmds.nss[s.nssDirs[0]]["WXChannel_Root_CA_1a2b3c4d"] {
+		t.Fatalf("expected sanitized nickname in NSS db, got %v", cmds.nss)
+	}
+	certs, _ := s.fetch()
+	if len(certs) != 1 || certs[0].Subject.CN != name || certs[0].Subject.O != name {
+		t.Fatalf("expected NSS entry with the real subject, got %+v", certs)
+	}
+
+	// 无法导出时退回到昵称
+	delete(cmds.nssPEM, "WXChannel_Root_CA_1a2b3c4d")
+	if certs, _ = s.fetch(); len(certs) != 1 || certs[0].Subject.CN != "WXChannel_Root_CA_1a2b3c4d" {
+		t.Errorf("expected nickname fallback, got %+v", certs)
+	}
+
+	if err := s.remove(name); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if certs, _ = s.fetch(); len(certs) != 0 {
+		t.Errorf("expected no certificates after remove, got %+v", certs)
+	}
+}
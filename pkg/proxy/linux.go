@@ -0,0 +1,493 @@
+package proxy
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// linuxDesktop Linux 桌面环境信息，外部命令和主目录可替换以便测试
+type linuxDesktop struct {
+	home    string
+	desktop string // XDG_CURRENT_DESKTOP，如 "GNOME"、"KDE"、"ubuntu:GNOME"
+
+	// 通过 sudo 运行时的原用户，为空表示以当前用户运行
+	sudoUser   string
+	uid, gid   int
+	sessionBus string // 原用户的 DBus 会话地址，找不到时为空
+
+	run      func(name string, args ...string) ([]byte, error)
+	lookPath func(file string) (string, error)
+}
+
+func newLinuxDesktop() *linuxDesktop {
+	home, _ := os.UserHomeDir()
+	d := &linuxDesktop{
+		home:    home,
+		desktop: os.Getenv("XDG_CURRENT_DESKTOP"),
+		run: func(name string, args ...string) ([]byte, error) {
+			return exec.Command(name, args...).CombinedOutput()
+		},
+		lookPath: exec.LookPath,
+	}
+	// 通过 sudo 运行时修改原用户的桌面设置
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && os.Geteuid() == 0 {
+		if u, err := user.Lookup(sudoUser); err == nil {
+			d.home = u.HomeDir
+			d.sudoUser = u.Username
+			d.uid, _ = strconv.Atoi(u.Uid)
+			d.gid, _ = strconv.Atoi(u.Gid)
+			if bus := filepath.Join("/run/user", u.Uid, "bus"); fileExists(bus) {
+				d.sessionBus = "unix:path=" + bus
+			}
+		}
+	}
+	return d
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// runSession 运行需要连接桌面会话的命令（gsettings、dbus-send）
+// 通过 sudo 运行时以原用户身份连接其 DBus 会话执行，否则修改的是 root 的设置
+func (d *linuxDesktop) runSession(name string, args ...string) ([]byte, error) {
+	if d.sudoUser == "" {
+		return d.run(name, args...)
+	}
+	if d.sessionBus == "" {
+		return nil, fmt.Errorf("找不到用户 %s 的 DBus 会话", d.sudoUser)
+	}
+	return d.run("sudo", append([]string{"-u", d.sudoUser, "env", "DBUS_SESSION_BUS_ADDRESS=" + d.sessionBus, name}, args...)...)
+}
+
+// writeFile 写入主目录下的文件，通过 sudo 运行时把新建的目录和文件交还给原用户
+func (d *linuxDesktop) writeFile(path string, data []byte) error {
+	var created []string
+	for dir := filepath.Dir(path); !fileExists(dir) && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		created = append(created, dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	if d.sudoUser == "" {
+		return nil
+	}
+	for _, p := range append(created, path) {
+		if err := os.Lchown(p, d.uid, d.gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *linuxDesktop) hasCommand(name string) bool {
+	_, err := d.lookPath(name)
+	return err == nil
+}
+
+func (d *linuxDesktop) isDesktop(name string) bool {
+	for _, part := range strings.Split(strings.ToUpper(d.desktop), ":") {
+		if part == name {
+			return true
+		}
+	}
+	return false
+}
+
+func linuxBackends(d *linuxDesktop) []backend {
+	return []backend{
+		&gnomeBackend{d: d},
+		&kdeBackend{d: d},
+		&envFileBackend{d: d},
+	}
+}
+
+// ============================================================================
+// GNOME（以及 Unity、Cinnamon、Budgie 等使用 org.gnome.system.proxy 的桌面）
+// ============================================================================
+
+type gnomeBackend struct {
+	d *linuxDesktop
+}
+
+// gnomeKeys 快照和恢复涉及的 gsettings 键：schema -> key
+var gnomeKeys = [][2]string{
+	{"org.gnome.system.proxy", "mode"},
+	{"org.gnome.system.proxy.http", "host"},
+	{"org.gnome.system.proxy.http", "port"},
+	{"org.gnome.system.proxy.https", "host"},
+	{"org.gnome.system.proxy.https", "port"},
+}
+
+func (g *gnomeBackend) Name() string { return "gnome" }
+
+func (g *gnomeBackend) Available() bool {
+	if !g.d.hasCommand("gsettings") {
+		return false
+	}
+	_, err := g.d.runSession("gsettings", "list-keys", "org.gnome.system.proxy")
+	return err == nil
+}
+
+func (g *gnomeBackend) get(schema, key string) (string, error) {
+	output, err := g.d.runSession("gsettings", "get", schema, key)
+	if err != nil {
+		return "", fmt.Errorf("gsettings get %s %s 失败，%v", schema, key, strings.TrimSpace(string(output)))
+	}
+	return strings.Trim(strings.TrimSpace(string(output)), "'"), nil
+}
+
+func (g *gnomeBackend) set(schema, key, value string) error {
+	output, err := g.d.runSession("gsettings", "set", schema, key, value)
+	if err != nil {
+		return fmt.Errorf("gsettings set %s %s 失败，%v", schema, key, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (g *gnomeBackend) Snapshot() (map[string]string, error) {
+	snapshot := make(map[string]string)
+	for _, k := range gnomeKeys {
+		value, err := g.get(k[0], k[1])
+		if err != nil {
+			return nil, err
+		}
+		snapshot[k[0]+" "+k[1]] = value
+	}
+	return snapshot, nil
+}
+
+func (g *gnomeBackend) Enable(p ProxySettings) error {
+	for _, schema := range []string{"org.gnome.system.proxy.http", "org.gnome.system.proxy.https"} {
+		if err := g.set(schema, "host", p.Hostname); err != nil {
+			return err
+		}
+		if err := g.set(schema, "port", p.Port); err != nil {
+			return err
+		}
+	}
+	return g.set("org.gnome.system.proxy", "mode", "manual")
+}
+
+func (g *gnomeBackend) Restore(snapshot map[string]string) error {
+	if snapshot == nil {
+		return g.set("org.gnome.system.proxy", "mode", "none")
+	}
+	// 先恢复地址，最后恢复模式，避免中间状态指向错误地址
+	for i := len(gnomeKeys) - 1; i >= 0; i-- {
+		k := gnomeKeys[i]
+		value, ok := snapshot[k[0]+" "+k[1]]
+		if !ok {
+			continue
+		}
+		if err := g.set(k[0], k[1], value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *gnomeBackend) Status() (BackendStatus, error) {
+	status := BackendStatus{Backend: g.Name()}
+	mode, err := g.get("org.gnome.system.proxy", "mode")
+	if err != nil {
+		return status, err
+	}
+	status.Detail = "mode=" + mode
+	if mode != "manual" {
+		return status, nil
+	}
+	status.Enabled = true
+	status.Host, _ = g.get("org.gnome.system.proxy.https", "host")
+	status.Port, _ = g.get("org.gnome.system.proxy.https", "port")
+	return status, nil
+}
+
+// ============================================================================
+// KDE Plasma：~/.config/kioslaverc 的 [Proxy Settings] 段
+// ============================================================================
+
+type kdeBackend struct {
+	d *linuxDesktop
+}
+
+const kdeProxySection = "Proxy Settings"
+
+// kdeKeys ProxyType: 0=不使用代理, 1=手动配置
+var kdeKeys = []string{"ProxyType", "httpProxy", "httpsProxy"}
+
+func (k *kdeBackend) Name() string { return "kde" }
+
+func (k *kdeBackend) path() string {
+	return filepath.Join(k.d.home, ".config", "kioslaverc")
+}
+
+func (k *kdeBackend) Available() bool {
+	if k.d.home == "" {
+		return false
+	}
+	if k.d.isDesktop("KDE") {
+		return true
+	}
+	_, err := os.Stat(k.path())
+	return err == nil
+}
+
+func (k *kdeBackend) Snapshot() (map[string]string, error) {
+	values, err := readINISection(k.path(), kdeProxySection)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	snapshot := make(map[string]string)
+	for _, key := range kdeKeys {
+		if v, ok := values[key]; ok {
+			snapshot[key] = v
+		}
+	}
+	return snapshot, nil
+}
+
+func (k *kdeBackend) Enable(p ProxySettings) error {
+	// KDE 的代理地址格式为 "http://host port"
+	addr := fmt.Sprintf("http://%s %s", p.Hostname, p.Port)
+	if err := k.d.writeINISection(k.path(), kdeProxySection, map[string]string{
+		"ProxyType":  "1",
+		"httpProxy":  addr,
+		"httpsProxy": addr,
+	}, nil); err != nil {
+		return err
+	}
+	k.notify()
+	return nil
+}
+
+func (k *kdeBackend) Restore(snapshot map[string]string) error {
+	set := map[string]string{"ProxyType": "0"}
+	var remove []string
+	if snapshot != nil {
+		set = make(map[string]string)
+		for _, key := range kdeKeys {
+			if v, ok := snapshot[key]; ok {
+				set[key] = v
+			} else {
+				remove = append(remove, key)
+			}
+		}
+	}
+	if err := k.d.writeINISection(k.path(), kdeProxySection, set, remove); err != nil {
+		return err
+	}
+	k.notify()
+	return nil
+}
+
+// notify 通知 KIO 重新读取配置（dbus-send 不存在时新启动的程序仍会读取新配置）
+func (k *kdeBackend) notify() {
+	if k.d.hasCommand("dbus-send") {
+		_, _ = k.d.runSession("dbus-send", "--type=signal", "/KIO/Scheduler",
+			"org.kde.KIO.Scheduler.reparseSlaveConfiguration", "string:")
+	}
+}
+
+func (k *kdeBackend) Status() (BackendStatus, error) {
+	status := BackendStatus{Backend: k.Name()}
+	values, err := readINISection(k.path(), kdeProxySection)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return status, nil
+		}
+		return status, err
+	}
+	status.Detail = "ProxyType=" + values["ProxyType"]
+	if values["ProxyType"] != "1" {
+		return status, nil
+	}
+	status.Enabled = true
+	addr := values["httpsProxy"]
+	if addr == "" {
+		addr = values["httpProxy"]
+	}
+	if host, port, ok := strings.Cut(strings.TrimSpace(addr), " "); ok {
+		status.Host, status.Port = host, strings.TrimSpace(port)
+	} else {
+		status.Host = addr
+	}
+	return status, nil
+}
+
+// ============================================================================
+// 环境变量文件：供终端和命令行工具 source 使用
+// ============================================================================
+
+type envFileBackend struct {
+	d *linuxDesktop
+}
+
+func (e *envFileBackend) Name() string { return "envfile" }
+
+// EnvFilePath 生成的代理环境变量文件路径
+func (e *envFileBackend) path() string {
+	return filepath.Join(e.d.home, ".config", "wx_channel", "proxy.env")
+}
+
+func (e *envFileBackend) Available() bool { return e.d.home != "" }
+
+func (e *envFileBackend) Snapshot() (map[string]string, error) {
+	// 文件由本程序生成，恢复时直接删除即可
+	return map[string]string{}, nil
+}
+
+func (e *envFileBackend) Enable(p ProxySettings) error {
+	addr := fmt.Sprintf("http://%s:%s", p.Hostname, p.Port)
+	var b strings.Builder
+	b.WriteString("# 由 wx_channel 生成，退出时自动删除\n")
+	b.WriteString("# 使用方法: source " + e.path() + "\n")
+	for _, name := range []string{"http_proxy", "https_proxy", "HTTP_PROXY", "HTTPS_PROXY"} {
+		fmt.Fprintf(&b, "export %s=%s\n", name, addr)
+	}
+	b.WriteString("export no_proxy=localhost,127.0.0.1,::1\n")
+	b.WriteString("export NO_PROXY=localhost,127.0.0.1,::1\n")
+
+	return e.d.writeFile(e.path(), []byte(b.String()))
+}
+
+func (e *envFileBackend) Restore(snapshot map[string]string) error {
+	if err := os.Remove(e.path()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (e *envFileBackend) Status() (BackendStatus, error) {
+	status := BackendStatus{Backend: e.Name(), Detail: e.path()}
+	data, err := os.ReadFile(e.path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return status, nil
+		}
+		return status, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), "export https_proxy=")
+		if !ok {
+			continue
+		}
+		status.Enabled = true
+		value = strings.TrimPrefix(value, "http://")
+		if idx := strings.LastIndex(value, ":"); idx >= 0 {
+			status.Host, status.Port = value[:idx], value[idx+1:]
+		}
+	}
+	return status, nil
+}
+
+// ============================================================================
+// INI 读写（kioslaverc），保留文件中的其他段和注释
+// ============================================================================
+
+func readINISection(path, section string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = line[1 : len(line)-1]
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values, nil
+}
+
+func (d *linuxDesktop) writeINISection(path, section string, set map[string]string, remove []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	removeSet := make(map[string]bool, len(remove))
+	for _, key := range remove {
+		removeSet[key] = true
+	}
+	pending := make(map[string]string, len(set))
+	for k, v := range set {
+		pending[k] = v
+	}
+	// 按固定顺序追加缺失的键，保证输出稳定
+	flush := func(lines []string) []string {
+		for _, key := range kdeKeys {
+			if v, ok := pending[key]; ok {
+				lines = append(lines, key+"="+v)
+				delete(pending, key)
+			}
+		}
+		for key, v := range pending {
+			lines = append(lines, key+"="+v)
+			delete(pending, key)
+		}
+		return lines
+	}
+
+	var out []string
+	current, found := "", false
+	if len(data) > 0 {
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+				if current == section {
+					out = flush(out)
+				}
+				current = trimmed[1 : len(trimmed)-1]
+				if current == section {
+					found = true
+				}
+				out = append(out, line)
+				continue
+			}
+			if current == section {
+				if key, _, ok := strings.Cut(trimmed, "="); ok {
+					key = strings.TrimSpace(key)
+					if removeSet[key] {
+						continue
+					}
+					if v, ok := pending[key]; ok {
+						out = append(out, key+"="+v)
+						delete(pending, key)
+						continue
+					}
+				}
+			}
+			out = append(out, line)
+		}
+	}
+	if current == section {
+		out = flush(out)
+	}
+	if !found && len(pending) > 0 {
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, "["+section+"]")
+		out = flush(out)
+	}
+
+	return d.writeFile(path, []byte(strings.Join(out, "\n")+"\n"))
+}
@@ -0,0 +1,240 @@
+package proxy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeGSettings 以内存中的键值表模拟 gsettings
+type fakeGSettings struct {
+	values map[string]string
+	calls  []string
+}
+
+func (f *fakeGSettings) run(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	// sudo -u USER env DBUS_SESSION_BUS_ADDRESS=... gsettings ...
+	if name == "sudo" {
+		for i, arg := range args {
+			if arg == "gsettings" {
+				name, args = arg, args[i+1:]
+				break
+			}
+		}
+	}
+	if name != "gsettings" {
+		return nil, nil
+	}
+	switch args[0] {
+	case "list-keys":
+		return []byte("mode\nautoconfig-url\n"), nil
+	case "get":
+		return []byte("'" + f.values[args[1]+" "+args[2]] + "'\n"), nil
+	case "set":
+		f.values[args[1]+" "+args[2]] = args[3]
+	}
+	return nil, nil
+}
+
+func (f *fakeGSettings) lookPath(name string) (string, error) {
+	if name == "gsettings" {
+		return "/usr/bin/gsettings", nil
+	}
+	return "", errors.New("not found")
+}
+
+func setupLinuxTest(t *testing.T, desktop string) (*linuxDesktop, *fakeGSettings) {
+	t.Helper()
+	home := t.TempDir()
+	gs := &fakeGSettings{values: map[string]string{
+		"org.gnome.system.proxy mode":       "auto",
+		"org.gnome.system.proxy.http host":  "proxy.corp",
+		"org.gnome.system.proxy.http port":  "8080",
+		"org.gnome.system.proxy.https host": "proxy.corp",
+		"org.gnome.system.proxy.https port": "8080",
+	}}
+	d := &linuxDesktop{home: home, desktop: desktop, run: gs.run, lookPath: gs.lookPath}
+
+	oldState, oldBackends := stateFile, backendsFunc
+	stateFile = filepath.Join(home, "state", "proxy_state.json")
+	backendsFunc = func() []backend { return linuxBackends(d) }
+	t.Cleanup(func() { stateFile, backendsFunc = oldState, oldBackends })
+	return d, gs
+}
+
+func TestLinuxProxy_EnableStatusDisable(t *testing.T) {
+	d, gs := setupLinuxTest(t, "KDE")
+	kioslaverc := filepath.Join(d.home, ".config", "kioslaverc")
+	if err := os.MkdirAll(filepath.Dir(kioslaverc), 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := "[General]\nfoo=bar\n\n[Proxy Settings]\nProxyType=0\nNoProxyFor=localhost\n"
+	if err := os.WriteFile(kioslaverc, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := ProxySettings{Hostname: "127.0.0.1", Port: "2025"}
+	if err := p.Enable(); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if gs.values["org.gnome.system.proxy mode"] != "manual" || gs.values["org.gnome.system.proxy.https port"] != "2025" {
+		t.Errorf("unexpected gsettings values: %v", gs.values)
+	}
+	values, _ := readINISection(kioslaverc, kdeProxySection)
+	if values["ProxyType"] != "1" || values["httpsProxy"] != "http://127.0.0.1 2025" || values["NoProxyFor"] != "localhost" {
+		t.Errorf("unexpected kioslaverc values: %v", values)
+	}
+	env, err := os.ReadFile(filepath.Join(d.home, ".config", "wx_channel", "proxy.env"))
+	if err != nil || !strings.Contains(string(env), "export https_proxy=http://127.0.0.1:2025") {
+		t.Errorf("unexpected env file: %q, %v", env, err)
+	}
+
+	statuses, err := p.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("expected 3 backends, got %+v", statuses)
+	}
+	for _, s := range statuses {
+		if !s.Enabled || !s.PointsToUs {
+			t.Errorf("expected %s to point to us: %+v", s.Backend, s)
+		}
+	}
+
+	if err := p.Disable(); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if gs.values["org.gnome.system.proxy mode"] != "auto" || gs.values["org.gnome.system.proxy.http host"] != "proxy.corp" {
+		t.Errorf("gsettings not restored: %v", gs.values)
+	}
+	values, _ = readINISection(kioslaverc, kdeProxySection)
+	if values["ProxyType"] != "0" || values["httpProxy"] != "" {
+		t.Errorf("kioslaverc not restored: %v", values)
+	}
+	if _, err := os.Stat(filepath.Join(d.home, ".config", "wx_channel", "proxy.env")); !os.IsNotExist(err) {
+		t.Error("expected env file to be removed")
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Error("expected state file to be removed")
+	}
+}
+
+func TestLinuxProxy_RecoverAfterCrash(t *testing.T) {
+	_, gs := setupLinuxTest(t, "GNOME")
+
+	if recovered, err := Recover(); recovered || err != nil {
+		t.Fatalf("expected nothing to recover, got %v, %v", recovered, err)
+	}
+
+	p := ProxySettings{Hostname: "127.0.0.1", Port: "2025"}
+	if err := p.Enable(); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	// 再次启用（如上次未正常退出）时保留最初的快照
+	if err := p.Enable(); err != nil {
+		t.Fatalf("Enable again: %v", err)
+	}
+
+	// 本进程仍在运行时视为有实例在使用代理，模拟进程已退出
+	oldAlive := processAlive
+	processAlive = func(int) bool { return false }
+	t.Cleanup(func() { processAlive = oldAlive })
+
+	recovered, err := Recover()
+	if !recovered || err != nil {
+		t.Fatalf("expected recovery, got %v, %v", recovered, err)
+	}
+	if gs.values["org.gnome.system.proxy mode"] != "auto" || gs.values["org.gnome.system.proxy.https port"] != "8080" {
+		t.Errorf("gsettings not restored: %v", gs.values)
+	}
+}
+
+func TestLinuxProxy_MultipleInstances(t *testing.T) {
+	_, gs := setupLinuxTest(t, "GNOME")
+
+	// 另一个实例（PID 1）已经启用代理并仍在运行
+	const otherPID = 1
+	oldAlive := processAlive
+	alive := map[int]bool{otherPID: true, os.Getpid(): true}
+	processAlive = func(pid int) bool { return alive[pid] }
+	t.Cleanup(func() { processAlive = oldAlive })
+
+	p := ProxySettings{Hostname: "127.0.0.1", Port: "2025"}
+	if err := p.Enable(); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	state, err := readState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.PIDs = append(state.PIDs, otherPID)
+	if err := writeState(state); err != nil {
+		t.Fatal(err)
+	}
+
+	// 启动时不会恢复另一个实例的快照
+	if recovered, err := Recover(); recovered || err != nil {
+		t.Fatalf("expected no recovery while another instance is alive, got %v, %v", recovered, err)
+	}
+	// 本实例退出时保留代理和快照，只移除自己的记录
+	if err := p.Disable(); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if gs.values["org.gnome.system.proxy mode"] != "manual" {
+		t.Errorf("proxy restored while another instance is alive: %v", gs.values)
+	}
+	state, err = readState()
+	if err != nil || len(state.PIDs) != 1 || state.PIDs[0] != otherPID {
+		t.Fatalf("unexpected state after Disable: %+v, %v", state, err)
+	}
+
+	// 另一个实例崩溃后由下次启动恢复
+	alive[otherPID] = false
+	if recovered, err := Recover(); !recovered || err != nil {
+		t.Fatalf("expected recovery, got %v, %v", recovered, err)
+	}
+	if gs.values["org.gnome.system.proxy mode"] != "auto" {
+		t.Errorf("gsettings not restored: %v", gs.values)
+	}
+}
+
+func TestLinuxProxy_SudoUser(t *testing.T) {
+	d, gs := setupLinuxTest(t, "GNOME")
+	d.sudoUser, d.uid, d.gid = "alice", os.Getuid(), os.Getgid()
+	d.sessionBus = "unix:path=/run/user/1000/bus"
+
+	p := ProxySettings{Hostname: "127.0.0.1", Port: "2025"}
+	if err := p.Enable(); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if gs.values["org.gnome.system.proxy mode"] != "manual" {
+		t.Errorf("unexpected gsettings values: %v", gs.values)
+	}
+	// gsettings 以原用户身份连接其会话总线运行
+	for _, call := range gs.calls {
+		if strings.HasPrefix(call, "gsettings ") {
+			t.Errorf("gsettings ran as root: %s", call)
+		}
+	}
+	want := "sudo -u alice env DBUS_SESSION_BUS_ADDRESS=unix:path=/run/user/1000/bus gsettings set org.gnome.system.proxy mode manual"
+	if !slices.Contains(gs.calls, want) {
+		t.Errorf("expected call %q, got %v", want, gs.calls)
+	}
+	if _, err := os.Stat(filepath.Join(d.home, ".config", "wx_channel", "proxy.env")); err != nil {
+		t.Errorf("expected env file: %v", err)
+	}
+	if err := p.Disable(); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+
+	// 找不到原用户的会话时跳过 GNOME，不修改 root 的设置
+	d.sessionBus = ""
+	if (&gnomeBackend{d: d}).Available() {
+		t.Error("expected gnome backend to be unavailable without a session bus")
+	}
+}
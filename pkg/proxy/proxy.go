@@ -112,3 +112,71 @@ func getNetworkInterfaces() (*HardwarePort, error) {
 	}
 	return nil, fmt.Errorf("未找到硬件端口信息")
 }
+
+// macOSBackend 通过 networksetup 设置当前网络设备的 HTTP/HTTPS 代理
+type macOSBackend struct{}
+
+func (macOSBackend) Name() string { return "macos" }
+
+func (macOSBackend) Available() bool {
+	_, err := exec.LookPath("networksetup")
+	return err == nil
+}
+
+// getWebProxy 解析 networksetup -getwebproxy 的输出
+func getWebProxy(device string) (map[string]string, error) {
+	output, err := exec.Command("networksetup", "-getwebproxy", device).Output()
+	if err != nil {
+		return nil, fmt.Errorf("获取代理设置失败，%v", err)
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values, nil
+}
+
+func (macOSBackend) Snapshot() (map[string]string, error) {
+	device := ProxySettings{}.WithDefaults().Device
+	values, err := getWebProxy(device)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"device":  device,
+		"enabled": values["Enabled"],
+		"server":  values["Server"],
+		"port":    values["Port"],
+	}, nil
+}
+
+func (macOSBackend) Enable(p ProxySettings) error {
+	return EnableProxyInMacOS(p)
+}
+
+func (macOSBackend) Restore(snapshot map[string]string) error {
+	if snapshot == nil || snapshot["enabled"] != "Yes" || snapshot["server"] == "" {
+		return DisableProxyInMacOS(ProxySettings{Device: snapshot["device"]})
+	}
+	return EnableProxyInMacOS(ProxySettings{
+		Device:   snapshot["device"],
+		Hostname: snapshot["server"],
+		Port:     snapshot["port"],
+	})
+}
+
+func (macOSBackend) Status() (BackendStatus, error) {
+	values, err := getWebProxy(ProxySettings{}.WithDefaults().Device)
+	if err != nil {
+		return BackendStatus{Backend: "macos"}, err
+	}
+	return BackendStatus{
+		Backend: "macos",
+		Enabled: values["Enabled"] == "Yes",
+		Host:    values["Server"],
+		Port:    values["Port"],
+	}, nil
+}
@@ -0,0 +1,275 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// BackendStatus 某个系统代理后端的实际状态
+type BackendStatus struct {
+	Backend    string `json:"backend"`
+	Enabled    bool   `json:"enabled"`
+	Host       string `json:"host,omitempty"`
+	Port       string `json:"port,omitempty"`
+	PointsToUs bool   `json:"points_to_us"` // 是否指向本程序的代理端口
+	Detail     string `json:"detail,omitempty"`
+}
+
+// backend 系统代理后端（GNOME、KDE、环境变量文件、macOS networksetup 等）
+type backend interface {
+	Name() string
+	Available() bool
+	// Snapshot 记录修改前的状态，用于退出或崩溃后恢复
+	Snapshot() (map[string]string, error)
+	Enable(p ProxySettings) error
+	// Restore 恢复到快照状态；snapshot 为 nil 时直接关闭代理
+	Restore(snapshot map[string]string) error
+	Status() (BackendStatus, error)
+}
+
+// proxyState 启用代理前写入磁盘的快照，进程异常退出后据此恢复
+// 多个实例同时运行时共用最初的快照，PIDs 记录仍在使用代理的进程
+type proxyState struct {
+	PIDs      []int                        `json:"pids"`
+	Hostname  string                       `json:"hostname"`
+	Port      string                       `json:"port"`
+	Snapshots map[string]map[string]string `json:"snapshots"`
+}
+
+var (
+	stateMu sync.Mutex
+	// stateFile 快照文件路径，测试中可替换
+	stateFile = defaultStateFile()
+	// backendsFunc 返回当前平台可用的后端，测试中可替换
+	backendsFunc = platformBackends
+	// processAlive 判断进程是否仍在运行，测试中可替换
+	processAlive = defaultProcessAlive
+)
+
+func defaultProcessAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// livePIDs 返回快照中仍在运行的进程（排除 exclude）
+func (s *proxyState) livePIDs(exclude int) []int {
+	var result []int
+	for _, pid := range s.PIDs {
+		if pid != exclude && processAlive(pid) {
+			result = append(result, pid)
+		}
+	}
+	return result
+}
+
+func defaultStateFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "wx_channel", "proxy_state.json")
+}
+
+func platformBackends() []backend {
+	switch runtime.GOOS {
+	case "linux":
+		return linuxBackends(newLinuxDesktop())
+	case "darwin":
+		return []backend{macOSBackend{}}
+	default:
+		return nil
+	}
+}
+
+func availableBackends() []backend {
+	var result []backend
+	for _, b := range backendsFunc() {
+		if b.Available() {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func readState() (*proxyState, error) {
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	var state proxyState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("解析代理快照失败: %v", err)
+	}
+	return &state, nil
+}
+
+func writeState(state *proxyState) error {
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateFile, data, 0600)
+}
+
+// Enable 将系统代理指向本程序
+// 修改前先把原有设置写入快照文件；已有快照（上次未恢复）时保留最初的快照
+func (p ProxySettings) Enable() error {
+	p = p.withHostPort()
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	backends := availableBackends()
+	if len(backends) == 0 {
+		return fmt.Errorf("当前系统（%s）没有可用的系统代理设置方式", runtime.GOOS)
+	}
+
+	state, err := readState()
+	if err != nil {
+		state = &proxyState{Snapshots: map[string]map[string]string{}}
+	}
+	state.PIDs = append(state.livePIDs(os.Getpid()), os.Getpid())
+	state.Hostname = p.Hostname
+	state.Port = p.Port
+	for _, b := range backends {
+		if _, ok := state.Snapshots[b.Name()]; ok {
+			continue
+		}
+		snapshot, err := b.Snapshot()
+		if err != nil {
+			snapshot = nil
+		}
+		state.Snapshots[b.Name()] = snapshot
+	}
+	if err := writeState(state); err != nil {
+		return fmt.Errorf("保存代理快照失败: %v", err)
+	}
+
+	var enabled, errs []string
+	for _, b := range backends {
+		if err := b.Enable(p); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", b.Name(), err))
+			continue
+		}
+		enabled = append(enabled, b.Name())
+	}
+	if len(enabled) == 0 {
+		return fmt.Errorf("设置系统代理失败: %s", strings.Join(errs, "; "))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("部分系统代理设置失败（已设置: %s）: %s", strings.Join(enabled, ", "), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Disable 恢复启用前的系统代理设置并删除快照
+// 仍有其他实例在使用代理时只移除本进程的记录，由最后退出的实例恢复
+func (p ProxySettings) Disable() error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if state, err := readState(); err == nil {
+		if others := state.livePIDs(os.Getpid()); len(others) > 0 {
+			state.PIDs = others
+			return writeState(state)
+		}
+	}
+	return restoreLocked()
+}
+
+func restoreLocked() error {
+	state, err := readState()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var errs []string
+	for _, b := range availableBackends() {
+		var snapshot map[string]string
+		if state != nil {
+			snapshot = state.Snapshots[b.Name()]
+		}
+		if err := b.Restore(snapshot); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", b.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("恢复系统代理失败: %s", strings.Join(errs, "; "))
+	}
+	if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Status 返回各后端的实际系统代理状态
+func (p ProxySettings) Status() ([]BackendStatus, error) {
+	p = p.withHostPort()
+	backends := availableBackends()
+	if len(backends) == 0 {
+		return []BackendStatus{}, nil
+	}
+
+	var result []BackendStatus
+	var errs []error
+	for _, b := range backends {
+		status, err := b.Status()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", b.Name(), err))
+			continue
+		}
+		status.PointsToUs = status.Enabled && status.Port == p.Port && isLocalHost(status.Host, p.Hostname)
+		result = append(result, status)
+	}
+	return result, errors.Join(errs...)
+}
+
+// Recover 检测上次运行留下的快照（进程崩溃或被强制结束），存在则恢复原有设置
+// 快照中记录的进程仍在运行时（另一个实例正在使用代理）不做处理
+// 返回是否执行了恢复
+func Recover() (bool, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if _, err := os.Stat(stateFile); err != nil {
+		return false, nil
+	}
+	if state, err := readState(); err == nil && len(state.livePIDs(os.Getpid())) > 0 {
+		return false, nil
+	}
+	return true, restoreLocked()
+}
+
+// withHostPort 与 WithDefaults 相同但不探测网络设备（Linux 不需要）
+func (p ProxySettings) withHostPort() ProxySettings {
+	if p.Hostname == "" {
+		p.Hostname = "127.0.0.1"
+	}
+	if p.Port == "" {
+		p.Port = "2023"
+	}
+	return p
+}
+
+func isLocalHost(host, want string) bool {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "http://"), "https://")
+	host = strings.TrimSuffix(host, "/")
+	if host == want {
+		return true
+	}
+	local := map[string]bool{"127.0.0.1": true, "localhost": true, "::1": true}
+	return local[host] && local[want]
+}